@@ -1,37 +1,204 @@
 package config
 
 import (
+	logUtil "crud/internal/util/log"
 	"fmt"
 	"log/slog"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	DB  DatabaseConfig
-	App AppConfig
+	DB        DatabaseConfig
+	App       AppConfig
+	RateLimit RateLimitConfig
+	Auth      AuthConfig
+	Admin     AdminConfig
+	Tenant    TenantConfig
+	Outbox    OutboxConfig
+	Webhook   WebhookConfig
+	Stream    StreamConfig
+	Job       JobConfig
 }
 
 type DatabaseConfig struct {
 	Host     string
 	Port     string
 	Username string
-	Password string
+	Password string `redact:"true"`
 	Database string
 	Schema   string
-	Params   string
+	Params   string `redact:"true"`
+
+	// SlowQueryThreshold is the duration above which queries are logged at warn level, "0" disables it
+	SlowQueryThreshold string
+	ExplainSlowQueries string
+	// PoolSaturationThreshold is the share of acquired connections above which the instance is not ready
+	PoolSaturationThreshold string
 }
 
 type AppConfig struct {
-	LogLevel string
-	AppMode  string
+	LogLevel          string
+	AppMode           string
+	RequestIDHeaders  string
+	TrustedProxies    string
+	ClientIPHeader    string
+	LogRedact         string
+	LogRedactKeys     string
+	LogRedactColumns  string
+	LogMaxValueLength string
+	// LogDebugSecret signs the tokens which escalate single requests to debug level
+	LogDebugSecret string `redact:"true"`
+	LogFormat      string
+	LogSinks       string
+	// LogSampling is "<first>,<thereafter>", see GetLogSampling
+	LogSampling string
+	// ShutdownDelay is how long the server keeps serving after readiness fails, so load balancers notice it
+	ShutdownDelay   string
+	ShutdownTimeout string
+	// CORSAllowedOrigins are the origins browsers may call the user routes and open the WebSocket API from
+	CORSAllowedOrigins string
+}
+
+type RateLimitConfig struct {
+	DefaultLimit string
+	RouteLimits  string
+	Store        string
+	// IPLimit limits every client IP ahead of the authentication, so that guessing credentials is throttled too
+	IPLimit string
+}
+
+type AuthConfig struct {
+	Enabled            string
+	Issuer             string
+	Audience           string
+	KeysFile           string
+	HMACSecret         string `redact:"true"`
+	KeysReloadInterval string
+	AnonymousStatus    string
+	AnonymousSwagger   string
+	RolePermissions    string
+	MaxFailedLogins    string
+	LockoutDuration    string
+	SessionTTL         string
+	SessionRoles       string
+}
+
+// AdminConfig configures the diagnostics listener, it serves pprof, expvar and runtime state. Token guards the
+// admin endpoints and the listener while authentication is disabled, it is sent as "Bearer <token>"
+type AdminConfig struct {
+	Enabled string
+	Address string
+	Token   string `redact:"true"`
+}
+
+// TenantConfig configures how the tenant of a request is resolved, while it is disabled every request
+// belongs to the default tenant
+type TenantConfig struct {
+	Enabled    string
+	Header     string
+	BaseDomain string
+	// Default is the tenant of requests which name none, they are rejected when it is empty
+	Default string
+	// Mode is how tenants are isolated: "row" filters shared tables by tenant, "schema" gives every tenant a schema
+	// of its own next to DB_SCHEMA, which keeps the data of the default tenant
+	Mode string
+}
+
+// OutboxConfig configures the relay of the domain events, events are only recorded when there are sinks or
+// webhooks are enabled
+type OutboxConfig struct {
+	// Sinks is a semicolon separated list of outputs, see outbox.OpenSinks. Webhook URLs may carry credentials
+	Sinks string `redact:"true"`
+	// PollInterval, BatchSize, Retention and MaxAttempts fall back to the defaults of the relay when they are empty
+	PollInterval string
+	BatchSize    string
+	Retention    string
+	MaxAttempts  string
+}
+
+// WebhookConfig configures the webhook subscriptions of the tenants and the dispatcher of their deliveries
+type WebhookConfig struct {
+	Enabled string
+	// MaxAttempts, Timeout and PollInterval fall back to the defaults of the dispatcher when they are empty
+	MaxAttempts  string
+	Timeout      string
+	PollInterval string
+	// AllowedNetworks is a comma separated list of CIDRs or addresses the webhooks may target although they are
+	// internal, e.g. a receiver in the cluster network
+	AllowedNetworks string
+}
+
+// StreamConfig configures the streams of the user changes, server-sent events and WebSocket live queries
+type StreamConfig struct {
+	// HeartbeatInterval, LogSize and MaxSubscriptions fall back to the defaults of the streams when they are empty
+	HeartbeatInterval string
+	LogSize           string
+	// MaxSubscriptions is how many live queries a WebSocket connection subscribes to at most
+	MaxSubscriptions string
 }
 
+// JobConfig configures the worker of the background jobs
+type JobConfig struct {
+	Enabled string
+	// Concurrency, PollInterval, ShutdownTimeout and Retention fall back to the defaults of the worker when they are empty
+	Concurrency     string
+	PollInterval    string
+	ShutdownTimeout string
+	Retention       string
+}
+
+const (
+	// DefaultKeysReloadInterval is how often the JWT keys file is checked for changes
+	DefaultKeysReloadInterval = 30 * time.Second
+	DefaultMaxFailedLogins    = 5
+	DefaultLockoutDuration    = 15 * time.Minute
+	DefaultSessionTTL         = 24 * time.Hour
+	DefaultSlowQueryThreshold = 200 * time.Millisecond
+	// DefaultPoolSaturationThreshold fails readiness once 90% of the pool connections are in use
+	DefaultPoolSaturationThreshold = 0.9
+	DefaultShutdownDelay           = 5 * time.Second
+	DefaultShutdownTimeout         = 30 * time.Second
+	// DefaultAdminAddress keeps the diagnostics listener reachable from the host only
+	DefaultAdminAddress = "127.0.0.1:6060"
+	DefaultTenantHeader = "X-Tenant-ID"
+)
+
+// DefaultClientIPHeader is the forwarding header trusted when the peer is a trusted proxy and none is configured
+const DefaultClientIPHeader = "X-Forwarded-For"
+
+// DefaultRequestIDHeaders are the inbound headers checked for a request id when none are configured
+var DefaultRequestIDHeaders = []string{"X-Request-ID", "X-Correlation-ID"}
+
 func (config *DatabaseConfig) ToConnectionString() string {
 	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?%s",
 		config.Username, config.Password, config.Host, config.Port, config.Database, config.Params)
 }
 
+func (config *DatabaseConfig) GetSlowQueryThreshold() (time.Duration, error) {
+	return parseDurationOrDefault("slow query threshold", config.SlowQueryThreshold, DefaultSlowQueryThreshold)
+}
+
+func (config *DatabaseConfig) GetPoolSaturationThreshold() (float64, error) {
+	if config.PoolSaturationThreshold == "" {
+		return DefaultPoolSaturationThreshold, nil
+	}
+	threshold, err := strconv.ParseFloat(config.PoolSaturationThreshold, 64)
+	if err != nil || threshold < 0 || threshold > 1 {
+		return DefaultPoolSaturationThreshold, fmt.Errorf("invalid pool saturation threshold %q, expected a number from 0 to 1",
+			config.PoolSaturationThreshold)
+	}
+	return threshold, nil
+}
+
+// IsExplainSlowQueriesEnabled reports whether the query statistics may run EXPLAIN for samples of slow queries
+func (config *DatabaseConfig) IsExplainSlowQueriesEnabled() bool {
+	return strings.ToLower(config.ExplainSlowQueries) == "true"
+}
+
 func (config *AppConfig) ToSlogLevel() (slog.Level, error) {
 	level := strings.ToLower(config.LogLevel)
 	if level == "debug" {
@@ -51,6 +218,300 @@ func (config *AppConfig) IsAppInReleaseMode() bool {
 	return strings.ToLower(config.AppMode) == "release"
 }
 
+// IsLogRedactionEnabled reports whether secrets are masked in logs, redaction can only be
+// turned off outside of release mode
+func (config *AppConfig) IsLogRedactionEnabled() bool {
+	return config.IsAppInReleaseMode() || strings.ToLower(config.LogRedact) != "false"
+}
+
+// GetLogRedactKeys returns the comma separated list of attribute keys masked in addition to the defaults
+func (config *AppConfig) GetLogRedactKeys() []string {
+	return splitList(config.LogRedactKeys, []string{})
+}
+
+// GetLogRedactColumns returns the comma separated list of columns whose query parameters are logged,
+// it replaces the default list when set
+func (config *AppConfig) GetLogRedactColumns() []string {
+	return splitList(config.LogRedactColumns, nil)
+}
+
+// GetLogMaxValueLength returns the length logged string values are truncated to, 0 disables truncation
+func (config *AppConfig) GetLogMaxValueLength(defaultValue int) (int, error) {
+	return parseIntOrDefault("log max value length", config.LogMaxValueLength, defaultValue)
+}
+
+// GetLogFormat returns the default format of the log sinks, json unless configured
+func (config *AppConfig) GetLogFormat() string {
+	if config.LogFormat == "" {
+		return "json"
+	}
+	return config.LogFormat
+}
+
+// GetLogSampling returns how many info records with the same message are logged per second: the first ones,
+// then every thereafter-th one. Sampling is disabled when it is not configured
+func (config *AppConfig) GetLogSampling() (first int, thereafter int, enabled bool, err error) {
+	if config.LogSampling == "" {
+		return 0, 0, false, nil
+	}
+	firstValue, thereafterValue, found := strings.Cut(config.LogSampling, ",")
+	if !found {
+		return 0, 0, false, fmt.Errorf("invalid log sampling: %s. Please use <first>,<thereafter>", config.LogSampling)
+	}
+	if first, err = strconv.Atoi(strings.TrimSpace(firstValue)); err != nil {
+		return 0, 0, false, fmt.Errorf("invalid log sampling: %w", err)
+	}
+	if thereafter, err = strconv.Atoi(strings.TrimSpace(thereafterValue)); err != nil {
+		return 0, 0, false, fmt.Errorf("invalid log sampling: %w", err)
+	}
+	return first, thereafter, true, nil
+}
+
+// GetShutdownDelay returns how long the server keeps serving after it reports not ready, 5s unless configured
+func (config *AppConfig) GetShutdownDelay() (time.Duration, error) {
+	return parseDurationOrDefault("shutdown delay", config.ShutdownDelay, DefaultShutdownDelay)
+}
+
+// GetShutdownTimeout returns how long the server waits for the in-flight requests on shutdown, 30s unless configured
+func (config *AppConfig) GetShutdownTimeout() (time.Duration, error) {
+	return parseDurationOrDefault("shutdown timeout", config.ShutdownTimeout, DefaultShutdownTimeout)
+}
+
+// GetRequestIDHeaders returns the comma separated list of request id headers, the first one is used in responses
+func (config *AppConfig) GetRequestIDHeaders() []string {
+	return splitList(config.RequestIDHeaders, DefaultRequestIDHeaders)
+}
+
+// GetTrustedProxies returns the comma separated list of trusted proxy CIDRs or addresses
+func (config *AppConfig) GetTrustedProxies() []string {
+	return splitList(config.TrustedProxies, []string{})
+}
+
+// GetCORSAllowedOrigins returns the comma separated list of allowed origins, e.g. https://app.example.com, "*"
+// allows all of them. All origins may call the user routes when it is empty, the WebSocket API then only accepts
+// handshakes from its own origin
+func (config *AppConfig) GetCORSAllowedOrigins() []string {
+	return splitList(config.CORSAllowedOrigins, []string{})
+}
+
+// GetClientIPHeader returns the single forwarding header the trusted proxies set, the other ones are ignored
+func (config *AppConfig) GetClientIPHeader() string {
+	if header := strings.TrimSpace(config.ClientIPHeader); header != "" {
+		return header
+	}
+	return DefaultClientIPHeader
+}
+
+// IsEnabled reports whether any rate limit is configured
+func (config *RateLimitConfig) IsEnabled() bool {
+	return strings.TrimSpace(config.DefaultLimit) != "" || strings.TrimSpace(config.RouteLimits) != "" ||
+		strings.TrimSpace(config.IPLimit) != ""
+}
+
+// IsPostgresStore reports whether buckets are shared between replicas through the database
+func (config *RateLimitConfig) IsPostgresStore() bool {
+	return strings.ToLower(config.Store) == "postgres"
+}
+
+func (config *AuthConfig) IsEnabled() bool {
+	return strings.ToLower(config.Enabled) == "true"
+}
+
+// AllowsAnonymousStatus reports whether /status can be called without credentials, which is the default
+func (config *AuthConfig) AllowsAnonymousStatus() bool {
+	return strings.ToLower(config.AnonymousStatus) != "false"
+}
+
+// AllowsAnonymousSwagger reports whether the swagger UI can be opened without credentials, which is the default
+func (config *AuthConfig) AllowsAnonymousSwagger() bool {
+	return strings.ToLower(config.AnonymousSwagger) != "false"
+}
+
+func (config *AuthConfig) GetKeysReloadInterval() (time.Duration, error) {
+	return parseDurationOrDefault("keys reload interval", config.KeysReloadInterval, DefaultKeysReloadInterval)
+}
+
+func (config *AuthConfig) GetMaxFailedLogins() (int, error) {
+	return parseIntOrDefault("max failed logins", config.MaxFailedLogins, DefaultMaxFailedLogins)
+}
+
+func (config *AuthConfig) GetLockoutDuration() (time.Duration, error) {
+	return parseDurationOrDefault("lockout duration", config.LockoutDuration, DefaultLockoutDuration)
+}
+
+func (config *AuthConfig) GetSessionTTL() (time.Duration, error) {
+	return parseDurationOrDefault("session ttl", config.SessionTTL, DefaultSessionTTL)
+}
+
+// GetSessionRoles returns the roles granted to users logged in with a password, "user" by default
+func (config *AuthConfig) GetSessionRoles() []string {
+	return splitList(config.SessionRoles, []string{"user"})
+}
+
+func (config *AdminConfig) IsEnabled() bool {
+	return strings.ToLower(config.Enabled) == "true"
+}
+
+func (config *AdminConfig) GetAddress() string {
+	if config.Address == "" {
+		return DefaultAdminAddress
+	}
+	return config.Address
+}
+
+func (config *TenantConfig) IsEnabled() bool {
+	return strings.ToLower(config.Enabled) == "true"
+}
+
+// IsSchemaMode reports whether every tenant has a schema of its own, tenancy has to be enabled
+func (config *TenantConfig) IsSchemaMode() bool {
+	return config.IsEnabled() && strings.ToLower(config.Mode) == "schema"
+}
+
+func (config *OutboxConfig) IsEnabled() bool {
+	return strings.TrimSpace(config.Sinks) != ""
+}
+
+// RecordsEvents reports whether the domain events are written to the outbox and relayed
+func (config *Config) RecordsEvents() bool {
+	return config.Outbox.IsEnabled() || config.Webhook.IsEnabled()
+}
+
+func (config *OutboxConfig) GetPollInterval() (time.Duration, error) {
+	return parseDurationOrDefault("outbox poll interval", config.PollInterval, 0)
+}
+
+func (config *OutboxConfig) GetBatchSize() (int, error) {
+	return parseIntOrDefault("outbox batch size", config.BatchSize, 0)
+}
+
+func (config *OutboxConfig) GetRetention() (time.Duration, error) {
+	return parseDurationOrDefault("outbox retention", config.Retention, 0)
+}
+
+func (config *OutboxConfig) GetMaxAttempts() (int, error) {
+	return parseIntOrDefault("outbox max attempts", config.MaxAttempts, 0)
+}
+
+func (config *WebhookConfig) IsEnabled() bool {
+	return strings.ToLower(config.Enabled) == "true"
+}
+
+func (config *WebhookConfig) GetMaxAttempts() (int, error) {
+	return parseIntOrDefault("webhook max attempts", config.MaxAttempts, 0)
+}
+
+func (config *WebhookConfig) GetTimeout() (time.Duration, error) {
+	return parseDurationOrDefault("webhook timeout", config.Timeout, 0)
+}
+
+func (config *WebhookConfig) GetPollInterval() (time.Duration, error) {
+	return parseDurationOrDefault("webhook poll interval", config.PollInterval, 0)
+}
+
+func (config *WebhookConfig) GetAllowedNetworks() []string {
+	return splitList(config.AllowedNetworks, []string{})
+}
+
+func (config *StreamConfig) GetHeartbeatInterval() (time.Duration, error) {
+	return parseDurationOrDefault("stream heartbeat interval", config.HeartbeatInterval, 0)
+}
+
+func (config *StreamConfig) GetLogSize() (int, error) {
+	return parseIntOrDefault("stream log size", config.LogSize, 0)
+}
+
+func (config *StreamConfig) GetMaxSubscriptions() (int, error) {
+	return parseIntOrDefault("websocket max subscriptions", config.MaxSubscriptions, 0)
+}
+
+func (config *JobConfig) IsEnabled() bool {
+	return strings.ToLower(config.Enabled) == "true"
+}
+
+func (config *JobConfig) GetConcurrency() (int, error) {
+	return parseIntOrDefault("job concurrency", config.Concurrency, 0)
+}
+
+func (config *JobConfig) GetPollInterval() (time.Duration, error) {
+	return parseDurationOrDefault("job poll interval", config.PollInterval, 0)
+}
+
+func (config *JobConfig) GetShutdownTimeout() (time.Duration, error) {
+	return parseDurationOrDefault("job shutdown timeout", config.ShutdownTimeout, 0)
+}
+
+func (config *JobConfig) GetRetention() (time.Duration, error) {
+	return parseDurationOrDefault("job retention", config.Retention, 0)
+}
+
+// GetHeader returns the request header naming the tenant, "none" disables it
+func (config *TenantConfig) GetHeader() string {
+	if config.Header == "" {
+		return DefaultTenantHeader
+	}
+	if strings.ToLower(config.Header) == "none" {
+		return ""
+	}
+	return config.Header
+}
+
+// Redacted returns the configuration by section and field, fields tagged with redact are masked
+func (config *Config) Redacted() map[string]map[string]string {
+	redacted := make(map[string]map[string]string)
+	sections := reflect.ValueOf(config).Elem()
+	for i := range sections.NumField() {
+		section := sections.Field(i)
+		fields := make(map[string]string)
+		for j := range section.NumField() {
+			field := section.Type().Field(j)
+			value := fmt.Sprint(section.Field(j).Interface())
+			if field.Tag.Get("redact") == "true" && !section.Field(j).IsZero() {
+				value = logUtil.Redacted
+			}
+			fields[field.Name] = value
+		}
+		redacted[sections.Type().Field(i).Name] = fields
+	}
+	return redacted
+}
+
+func parseDurationOrDefault(name, value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultValue, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return duration, nil
+}
+
+func parseIntOrDefault(name, value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return number, nil
+}
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(value string, defaultValue []string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return defaultValue
+	}
+	return items
+}
+
 func GetEnv(key string, required bool, missedEnvs *[]string) string {
 	value, ok := os.LookupEnv(key)
 	if !ok && required {
@@ -59,20 +520,103 @@ func GetEnv(key string, required bool, missedEnvs *[]string) string {
 	return value
 }
 
+// LoadConfig reads the configuration from the environment, after loading the file named by CONFIG_FILE if it is set.
+// It can be called again to re-read the configuration
 func LoadConfig() (*Config, error) {
+	if configFile, ok := os.LookupEnv(ConfigFileEnv); ok && configFile != "" {
+		if err := LoadEnvFile(configFile); err != nil {
+			return nil, fmt.Errorf("error loading config file: %w", err)
+		}
+	}
 	missedEnvs := make([]string, 0)
 	config := &Config{
 		DB: DatabaseConfig{
-			Host:     GetEnv("DB_HOST", true, &missedEnvs),
-			Port:     GetEnv("DB_PORT", true, &missedEnvs),
-			Username: GetEnv("DB_USERNAME", true, &missedEnvs),
-			Password: GetEnv("DB_PASSWORD", true, &missedEnvs),
-			Database: GetEnv("DB_DATABASE", true, &missedEnvs),
-			Schema:   GetEnv("DB_SCHEMA", true, &missedEnvs),
+			Host:                    GetEnv("DB_HOST", true, &missedEnvs),
+			Port:                    GetEnv("DB_PORT", true, &missedEnvs),
+			Username:                GetEnv("DB_USERNAME", true, &missedEnvs),
+			Password:                GetEnv("DB_PASSWORD", true, &missedEnvs),
+			Database:                GetEnv("DB_DATABASE", true, &missedEnvs),
+			Schema:                  GetEnv("DB_SCHEMA", true, &missedEnvs),
+			SlowQueryThreshold:      GetEnv("DB_SLOW_QUERY_THRESHOLD", false, &missedEnvs),
+			ExplainSlowQueries:      GetEnv("DB_EXPLAIN_SLOW_QUERIES", false, &missedEnvs),
+			PoolSaturationThreshold: GetEnv("DB_POOL_SATURATION_THRESHOLD", false, &missedEnvs),
 		},
 		App: AppConfig{
-			LogLevel: GetEnv("LOG_LEVEL", false, &missedEnvs),
-			AppMode:  GetEnv("APP_MODE", false, &missedEnvs),
+			LogLevel:           GetEnv("LOG_LEVEL", false, &missedEnvs),
+			AppMode:            GetEnv("APP_MODE", false, &missedEnvs),
+			RequestIDHeaders:   GetEnv("REQUEST_ID_HEADERS", false, &missedEnvs),
+			TrustedProxies:     GetEnv("TRUSTED_PROXIES", false, &missedEnvs),
+			ClientIPHeader:     GetEnv("CLIENT_IP_HEADER", false, &missedEnvs),
+			LogRedact:          GetEnv("LOG_REDACT", false, &missedEnvs),
+			LogRedactKeys:      GetEnv("LOG_REDACT_KEYS", false, &missedEnvs),
+			LogRedactColumns:   GetEnv("LOG_REDACT_ALLOWED_COLUMNS", false, &missedEnvs),
+			LogMaxValueLength:  GetEnv("LOG_MAX_VALUE_LENGTH", false, &missedEnvs),
+			LogDebugSecret:     GetEnv("LOG_DEBUG_SECRET", false, &missedEnvs),
+			LogFormat:          GetEnv("LOG_FORMAT", false, &missedEnvs),
+			LogSinks:           GetEnv("LOG_SINKS", false, &missedEnvs),
+			LogSampling:        GetEnv("LOG_SAMPLING", false, &missedEnvs),
+			ShutdownDelay:      GetEnv("SHUTDOWN_DELAY", false, &missedEnvs),
+			ShutdownTimeout:    GetEnv("SHUTDOWN_TIMEOUT", false, &missedEnvs),
+			CORSAllowedOrigins: GetEnv("CORS_ALLOWED_ORIGINS", false, &missedEnvs),
+		},
+		RateLimit: RateLimitConfig{
+			DefaultLimit: GetEnv("RATE_LIMIT_DEFAULT", false, &missedEnvs),
+			RouteLimits:  GetEnv("RATE_LIMIT_ROUTES", false, &missedEnvs),
+			Store:        GetEnv("RATE_LIMIT_STORE", false, &missedEnvs),
+			IPLimit:      GetEnv("RATE_LIMIT_IP", false, &missedEnvs),
+		},
+		Auth: AuthConfig{
+			Enabled:            GetEnv("AUTH_ENABLED", false, &missedEnvs),
+			Issuer:             GetEnv("AUTH_JWT_ISSUER", false, &missedEnvs),
+			Audience:           GetEnv("AUTH_JWT_AUDIENCE", false, &missedEnvs),
+			KeysFile:           GetEnv("AUTH_JWT_KEYS_FILE", false, &missedEnvs),
+			HMACSecret:         GetEnv("AUTH_JWT_HMAC_SECRET", false, &missedEnvs),
+			KeysReloadInterval: GetEnv("AUTH_JWT_KEYS_RELOAD_INTERVAL", false, &missedEnvs),
+			AnonymousStatus:    GetEnv("AUTH_ANONYMOUS_STATUS", false, &missedEnvs),
+			AnonymousSwagger:   GetEnv("AUTH_ANONYMOUS_SWAGGER", false, &missedEnvs),
+			RolePermissions:    GetEnv("AUTH_ROLE_PERMISSIONS", false, &missedEnvs),
+			MaxFailedLogins:    GetEnv("AUTH_MAX_FAILED_LOGINS", false, &missedEnvs),
+			LockoutDuration:    GetEnv("AUTH_LOCKOUT_DURATION", false, &missedEnvs),
+			SessionTTL:         GetEnv("AUTH_SESSION_TTL", false, &missedEnvs),
+			SessionRoles:       GetEnv("AUTH_SESSION_ROLES", false, &missedEnvs),
+		},
+		Admin: AdminConfig{
+			Enabled: GetEnv("ADMIN_ENABLED", false, &missedEnvs),
+			Address: GetEnv("ADMIN_ADDRESS", false, &missedEnvs),
+			Token:   GetEnv("ADMIN_TOKEN", false, &missedEnvs),
+		},
+		Tenant: TenantConfig{
+			Enabled:    GetEnv("TENANT_ENABLED", false, &missedEnvs),
+			Header:     GetEnv("TENANT_HEADER", false, &missedEnvs),
+			BaseDomain: GetEnv("TENANT_BASE_DOMAIN", false, &missedEnvs),
+			Default:    GetEnv("TENANT_DEFAULT", false, &missedEnvs),
+			Mode:       GetEnv("TENANT_MODE", false, &missedEnvs),
+		},
+		Outbox: OutboxConfig{
+			Sinks:        GetEnv("OUTBOX_SINKS", false, &missedEnvs),
+			PollInterval: GetEnv("OUTBOX_POLL_INTERVAL", false, &missedEnvs),
+			BatchSize:    GetEnv("OUTBOX_BATCH_SIZE", false, &missedEnvs),
+			Retention:    GetEnv("OUTBOX_RETENTION", false, &missedEnvs),
+			MaxAttempts:  GetEnv("OUTBOX_MAX_ATTEMPTS", false, &missedEnvs),
+		},
+		Webhook: WebhookConfig{
+			Enabled:         GetEnv("WEBHOOK_ENABLED", false, &missedEnvs),
+			MaxAttempts:     GetEnv("WEBHOOK_MAX_ATTEMPTS", false, &missedEnvs),
+			Timeout:         GetEnv("WEBHOOK_TIMEOUT", false, &missedEnvs),
+			PollInterval:    GetEnv("WEBHOOK_POLL_INTERVAL", false, &missedEnvs),
+			AllowedNetworks: GetEnv("WEBHOOK_ALLOWED_NETWORKS", false, &missedEnvs),
+		},
+		Stream: StreamConfig{
+			HeartbeatInterval: GetEnv("STREAM_HEARTBEAT_INTERVAL", false, &missedEnvs),
+			LogSize:           GetEnv("STREAM_LOG_SIZE", false, &missedEnvs),
+			MaxSubscriptions:  GetEnv("WS_MAX_SUBSCRIPTIONS", false, &missedEnvs),
+		},
+		Job: JobConfig{
+			Enabled:         GetEnv("JOB_ENABLED", false, &missedEnvs),
+			Concurrency:     GetEnv("JOB_CONCURRENCY", false, &missedEnvs),
+			PollInterval:    GetEnv("JOB_POLL_INTERVAL", false, &missedEnvs),
+			ShutdownTimeout: GetEnv("JOB_SHUTDOWN_TIMEOUT", false, &missedEnvs),
+			Retention:       GetEnv("JOB_RETENTION", false, &missedEnvs),
 		},
 	}
 	var err error
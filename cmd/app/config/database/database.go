@@ -3,20 +3,40 @@ package database
 import (
 	"context"
 	"crud/cmd/app/config"
+	"crud/internal/querystats"
+	"crud/internal/repository"
+	"crud/internal/repository/db"
+	"crud/internal/tenant"
+	"crud/internal/util/log"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"log/slog"
 	"regexp"
 	"strings"
+	"time"
 )
 
-func NewPool(dbConfig config.DatabaseConfig) (*pgxpool.Pool, error) {
+// NewPool creates the connection pool, its queries are logged with logger and their durations are recorded
+// in stats when it is not nil. The connections use the schema of the tenant they are acquired for when schemas
+// is not nil, see db.TenantSchemas
+func NewPool(dbConfig config.DatabaseConfig, stats *querystats.Collector, schemas *db.TenantSchemas, logger *slog.Logger) (*pgxpool.Pool, error) {
 	connectionString := dbConfig.ToConnectionString()
 	pgConfig, err := pgxpool.ParseConfig(connectionString)
 	if err != nil {
 		return nil, err
 	}
-	pgConfig.ConnConfig.Tracer = NewMultiQueryTracer(NewLoggingQueryTracer(slog.Default()))
+	slowThreshold, err := dbConfig.GetSlowQueryThreshold()
+	if err != nil {
+		return nil, err
+	}
+	tracers := []pgx.QueryTracer{NewLoggingQueryTracer(logger, log.DefaultRedactor(), slowThreshold)}
+	if stats != nil {
+		tracers = append(tracers, NewStatsQueryTracer(stats, slowThreshold))
+	}
+	pgConfig.ConnConfig.Tracer = NewMultiQueryTracer(tracers...)
+	if schemas != nil {
+		schemas.Configure(pgConfig)
+	}
 	return pgxpool.NewWithConfig(context.Background(), pgConfig)
 }
 
@@ -48,30 +68,69 @@ func prettyPrintSQL(sql string) string {
 
 // https://github.com/jackc/pgx/issues/1061#issuecomment-1186250809
 
+type queryStartKey struct{}
+
 type LoggingQueryTracer struct {
-	logger *slog.Logger
+	logger   *slog.Logger
+	redactor *log.Redactor
+	// slowThreshold logs queries taking longer at warn level, 0 disables slow query logging
+	slowThreshold time.Duration
+}
+
+func NewLoggingQueryTracer(logger *slog.Logger, redactor *log.Redactor, slowThreshold time.Duration) *LoggingQueryTracer {
+	return &LoggingQueryTracer{logger: logger, redactor: redactor, slowThreshold: slowThreshold}
 }
 
-func NewLoggingQueryTracer(logger *slog.Logger) *LoggingQueryTracer {
-	return &LoggingQueryTracer{logger: logger}
+type queryStart struct {
+	time time.Time
+	data pgx.TraceQueryStartData
 }
 
 func (l *LoggingQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
-	l.logger.
-		DebugContext(ctx, "query start",
-			slog.String("sql", prettyPrintSQL(data.SQL)),
-			slog.Any("args", data.Args),
-		)
-	return ctx
+	if l.logger.Enabled(ctx, slog.LevelDebug) {
+		l.logger.
+			DebugContext(ctx, "query start",
+				slog.String("sql", prettyPrintSQL(data.SQL)),
+				slog.Any("args", l.redactArgs(ctx, data)),
+			)
+	}
+	return context.WithValue(ctx, queryStartKey{}, &queryStart{time: time.Now(), data: data})
+}
+
+// redactArgs masks query arguments, queries marked as sensitive are masked even when redaction is disabled
+func (l *LoggingQueryTracer) redactArgs(ctx context.Context, data pgx.TraceQueryStartData) any {
+	if repository.HasSensitiveArgs(ctx) {
+		return log.Redacted
+	}
+	return redactQueryArgs(l.redactor, data.SQL, data.Args)
 }
 
 func (l *LoggingQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	var duration time.Duration
+	start, ok := ctx.Value(queryStartKey{}).(*queryStart)
+	if ok {
+		duration = time.Since(start.time)
+	}
+
 	// Failure
 	if data.Err != nil {
 		l.logger.
 			ErrorContext(ctx, "query end",
 				slog.String("error", data.Err.Error()),
 				slog.String("command_tag", data.CommandTag.String()),
+				slog.Int64("duration_ms", duration.Milliseconds()),
+			)
+		return
+	}
+
+	// Slow query
+	if ok && l.slowThreshold > 0 && duration >= l.slowThreshold {
+		l.logger.
+			WarnContext(ctx, "slow query",
+				slog.String("sql", prettyPrintSQL(start.data.SQL)),
+				slog.Any("args", l.redactArgs(ctx, start.data)),
+				slog.String("command_tag", data.CommandTag.String()),
+				slog.Int64("duration_ms", duration.Milliseconds()),
 			)
 		return
 	}
@@ -80,9 +139,45 @@ func (l *LoggingQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, dat
 	l.logger.
 		DebugContext(ctx, "query end",
 			slog.String("command_tag", data.CommandTag.String()),
+			slog.Int64("duration_ms", duration.Milliseconds()),
 		)
 }
 
+// StatsQueryTracer records the duration of queries by statement, using prettyPrintSQL as the fingerprint
+type StatsQueryTracer struct {
+	collector     *querystats.Collector
+	slowThreshold time.Duration
+}
+
+func NewStatsQueryTracer(collector *querystats.Collector, slowThreshold time.Duration) *StatsQueryTracer {
+	return &StatsQueryTracer{collector: collector, slowThreshold: slowThreshold}
+}
+
+type statsStartKey struct{}
+
+func (s *StatsQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if querystats.IsSkipped(ctx) {
+		return ctx
+	}
+	return context.WithValue(ctx, statsStartKey{}, &queryStart{time: time.Now(), data: data})
+}
+
+func (s *StatsQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(statsStartKey{}).(*queryStart)
+	if !ok {
+		return
+	}
+	duration := time.Since(start.time)
+	slow := s.slowThreshold > 0 && duration >= s.slowThreshold
+	var sample *querystats.Sample
+	// Arguments of sensitive queries never leave the request
+	if slow && data.Err == nil && !repository.HasSensitiveArgs(ctx) {
+		sample = &querystats.Sample{SQL: start.data.SQL, Args: start.data.Args, Duration: duration, At: time.Now()}
+		sample.TenantID, _ = tenant.FromContext(ctx)
+	}
+	s.collector.Record(prettyPrintSQL(start.data.SQL), duration, data.Err, slow, sample)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"crud/internal/probe"
+	"crud/internal/querystats"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RegisterProbeChecks adds the database checks: the ping and the pool saturation to readiness, the migration
+// state to readiness and startup. The pool is saturated once the share of acquired connections reaches
+// saturationThreshold, 0 disables that check
+func RegisterProbeChecks(registry *probe.Registry, pool *pgxpool.Pool, latestMigration uint, saturationThreshold float64) {
+	registry.Register(probe.Check{Name: "database", Func: func(ctx context.Context) error {
+		return pool.Ping(querystats.WithoutStats(ctx))
+	}}, probe.Readiness)
+	registry.Register(probe.Check{Name: "migrations", Func: func(ctx context.Context) error {
+		return checkMigrations(ctx, pool, latestMigration)
+	}}, probe.Readiness, probe.Startup)
+	if saturationThreshold > 0 {
+		registry.Register(probe.Check{Name: "database_pool", Func: func(context.Context) error {
+			return checkPoolSaturation(pool.Stat(), saturationThreshold)
+		}}, probe.Readiness)
+	}
+}
+
+func checkMigrations(ctx context.Context, pool *pgxpool.Pool, latestMigration uint) error {
+	var version uint
+	var dirty bool
+	err := pool.QueryRow(querystats.WithoutStats(ctx), "SELECT version, dirty FROM schema_migrations LIMIT 1").
+		Scan(&version, &dirty)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("migration %d is dirty", version)
+	}
+	if version < latestMigration {
+		return fmt.Errorf("database is at migration %d, expected %d", version, latestMigration)
+	}
+	return nil
+}
+
+func checkPoolSaturation(stat *pgxpool.Stat, threshold float64) error {
+	maxConns := stat.MaxConns()
+	if maxConns <= 0 {
+		return nil
+	}
+	if acquired := stat.AcquiredConns(); float64(acquired)/float64(maxConns) >= threshold {
+		return fmt.Errorf("pool saturated: %d of %d connections acquired", acquired, maxConns)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"crud/internal/util/log"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	comparedColumn = regexp.MustCompile(`(?i)(?:\w+\.)?"?(\w+)"?\s*(?:=|<>|!=|<=|>=|<|>|\bLIKE\b|\bILIKE\b|\bIN\b)\s*\(?\s*\$(\d+)`)
+	pagingClause   = regexp.MustCompile(`(?i)\b(LIMIT|OFFSET)\s+\$(\d+)`)
+	insertColumns  = regexp.MustCompile(`(?is)INSERT\s+INTO\s+[\w."]+\s*\(([^)]*)\)\s*VALUES\s*\(([^)]*)\)`)
+)
+
+// queryArgColumns maps the positions of query parameters to the columns they are bound to.
+// Only simple comparisons, LIMIT/OFFSET and INSERT column lists are recognized
+func queryArgColumns(sql string) map[int]string {
+	columns := make(map[int]string)
+	addColumn := func(column, placeholder string) {
+		position, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(placeholder), "$"))
+		if err == nil {
+			columns[position-1] = strings.Trim(strings.TrimSpace(column), `"`)
+		}
+	}
+	for _, match := range comparedColumn.FindAllStringSubmatch(sql, -1) {
+		addColumn(match[1], match[2])
+	}
+	for _, match := range pagingClause.FindAllStringSubmatch(sql, -1) {
+		addColumn(match[1], match[2])
+	}
+	for _, match := range insertColumns.FindAllStringSubmatch(sql, -1) {
+		names := strings.Split(match[1], ",")
+		values := strings.Split(match[2], ",")
+		for i := 0; i < len(names) && i < len(values); i++ {
+			if strings.HasPrefix(strings.TrimSpace(values[i]), "$") {
+				addColumn(names[i], values[i])
+			}
+		}
+	}
+	return columns
+}
+
+// redactQueryArgs applies the redaction policy to the arguments of a query
+func redactQueryArgs(redactor *log.Redactor, sql string, args []any) []any {
+	if !redactor.Enabled() {
+		return args
+	}
+	columns := queryArgColumns(sql)
+	redacted := make([]any, len(args))
+	for i, arg := range args {
+		redacted[i] = redactor.RedactQueryArg(columns[i], arg)
+	}
+	return redacted
+}
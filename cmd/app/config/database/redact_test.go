@@ -0,0 +1,36 @@
+package database
+
+import (
+	"crud/internal/util/log"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUnitRedactQueryArgs(t *testing.T) {
+	t.Parallel()
+
+	redactor := log.NewRedactor(log.DefaultRedactionPolicy())
+	tests := []struct {
+		name     string
+		sql      string
+		args     []any
+		expected []any
+	}{
+		{"Comparison with qualified column", "SELECT * FROM users u WHERE u.id = $1 AND u.email = $2",
+			[]any{"7", "user@example.com"}, []any{"7", log.Redacted}},
+		{"Paging clause", "SELECT * FROM users ORDER BY id LIMIT $1 OFFSET $2",
+			[]any{"10", "0"}, []any{"10", "0"}},
+		{"Insert column list", "INSERT INTO users (name, email, id) VALUES ($1, $2, $3)",
+			[]any{"Name", "user@example.com", "3"}, []any{log.Redacted, log.Redacted, "3"}},
+		{"Unknown column keeps numbers", "SELECT $1::int + $2", []any{1, "text"}, []any{1, log.Redacted}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redactQueryArgs(redactor, tt.sql, tt.args))
+		})
+	}
+
+	disabled := log.NewRedactor(log.RedactionPolicy{})
+	args := []any{"user@example.com"}
+	assert.Equal(t, args, redactQueryArgs(disabled, "SELECT * FROM users WHERE email = $1", args))
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ConfigFileEnv names the optional env file read by LoadConfig, its values override the environment
+const ConfigFileEnv = "CONFIG_FILE"
+
+// envFile remembers the variables set by LoadEnvFile with the values they had before, to restore them once they
+// are removed from the file
+var envFile = struct {
+	sync.Mutex
+	previous map[string]*string
+}{previous: make(map[string]*string)}
+
+// LoadEnvFile sets the environment variables of a file of "KEY=value" lines. Empty lines and lines
+// starting with # are skipped, an "export " prefix and quotes around the value are removed. The variables set
+// by an earlier load which are no longer in the file get back the value they had before, or are unset
+func LoadEnvFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return fmt.Errorf("%s:%d: expected KEY=value", path, lineNumber)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	envFile.Lock()
+	defer envFile.Unlock()
+	for key, value := range values {
+		if _, ok := envFile.previous[key]; !ok {
+			if previous, set := os.LookupEnv(key); set {
+				envFile.previous[key] = &previous
+			} else {
+				envFile.previous[key] = nil
+			}
+		}
+		if err = os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+	for key, previous := range envFile.previous {
+		if _, ok := values[key]; ok {
+			continue
+		}
+		if previous != nil {
+			err = os.Setenv(key, *previous)
+		} else {
+			err = os.Unsetenv(key)
+		}
+		if err != nil {
+			return err
+		}
+		delete(envFile.previous, key)
+	}
+	return nil
+}
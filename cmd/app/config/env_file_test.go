@@ -0,0 +1,34 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnitLoadEnvFileReload(t *testing.T) {
+	t.Setenv("CRUD_TEST_LOG_LEVEL", "info")
+	t.Setenv("CRUD_TEST_SECRET", "")
+	require.NoError(t, os.Unsetenv("CRUD_TEST_SECRET"))
+	path := filepath.Join(t.TempDir(), "app.env")
+
+	require.NoError(t, os.WriteFile(path, []byte("# reloaded on SIGHUP\nexport CRUD_TEST_LOG_LEVEL=debug\nCRUD_TEST_SECRET='s3cret'\n"), 0o600))
+	require.NoError(t, LoadEnvFile(path))
+	assert.Equal(t, "debug", os.Getenv("CRUD_TEST_LOG_LEVEL"))
+	assert.Equal(t, "s3cret", os.Getenv("CRUD_TEST_SECRET"))
+
+	require.NoError(t, os.WriteFile(path, []byte("CRUD_TEST_LOG_LEVEL=warn\n"), 0o600))
+	require.NoError(t, LoadEnvFile(path))
+	assert.Equal(t, "warn", os.Getenv("CRUD_TEST_LOG_LEVEL"))
+	_, set := os.LookupEnv("CRUD_TEST_SECRET")
+	assert.False(t, set, "removed from the file")
+
+	require.NoError(t, os.WriteFile(path, []byte(""), 0o600))
+	require.NoError(t, LoadEnvFile(path))
+	assert.Equal(t, "info", os.Getenv("CRUD_TEST_LOG_LEVEL"), "the environment value is back")
+
+	require.NoError(t, os.WriteFile(path, []byte("CRUD_TEST_LOG_LEVEL\n"), 0o600))
+	assert.EqualError(t, LoadEnvFile(path), path+":1: expected KEY=value")
+}
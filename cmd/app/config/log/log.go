@@ -1,24 +1,54 @@
 package log
 
 import (
+	"crud/cmd/app/config"
+	logUtil "crud/internal/util/log"
 	slogctx "github.com/veqryn/slog-context"
+	"io"
 	"log/slog"
 	"os"
 )
 
+// Add a few default environmental attributes that always are included
+var defaultAttrs = []slog.Attr{
+	slog.String("service", "userService"),
+}
+
+// CreateLogger sets a default logger writing JSON to stdout, ConfigureLogger replaces it once the config is loaded
 func CreateLogger() (*slog.Logger, *slog.LevelVar) {
-	// Add a few default environmental attributes that always are included
-	defaultAttrs := []slog.Attr{
-		slog.String("service", "userService"),
-	}
 	logLevel := new(slog.LevelVar)
 	logLevel.Set(slog.LevelInfo)
 	jsonHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		AddSource: true,
-		Level:     logLevel,
-	}).WithAttrs(defaultAttrs)
-	customHandler := slogctx.NewHandler(jsonHandler, nil)
-	logger := slog.New(customHandler)
+	})
+	logger := newLogger(logUtil.NewFanoutHandler(logLevel, logUtil.Sink{Handler: jsonHandler, MinLevel: slog.LevelDebug}))
 	slog.SetDefault(logger)
 	return logger, logLevel
 }
+
+// ConfigureLogger sets a default logger writing to the configured sinks, its level is still controlled by logLevel.
+// The closer closes the log files
+func ConfigureLogger(appConfig config.AppConfig, logLevel *slog.LevelVar) (*slog.Logger, io.Closer, error) {
+	sinks, closer, err := logUtil.OpenSinks(appConfig.LogSinks, appConfig.GetLogFormat())
+	if err != nil {
+		return nil, nil, err
+	}
+	var handler slog.Handler = logUtil.NewFanoutHandler(logLevel, sinks...)
+	first, thereafter, samplingEnabled, err := appConfig.GetLogSampling()
+	if err != nil {
+		_ = closer.Close()
+		return nil, nil, err
+	}
+	if samplingEnabled {
+		handler = logUtil.NewSamplingHandler(handler, first, thereafter, logUtil.DefaultSamplingTick)
+	}
+	logger := newLogger(handler)
+	slog.SetDefault(logger)
+	return logger, closer, nil
+}
+
+func newLogger(handler slog.Handler) *slog.Logger {
+	// Redact below slogctx so that attributes added to the context are masked too
+	redactingHandler := logUtil.NewRedactingHandler(handler.WithAttrs(defaultAttrs), logUtil.DefaultRedactor())
+	return slog.New(slogctx.NewHandler(logUtil.NewDebugEscalationHandler(redactingHandler), nil))
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"crud/cmd/app/config"
 	"crud/cmd/app/config/log"
 	"crud/cmd/app/server"
 	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
@@ -16,14 +20,27 @@ func main() {
 		return
 	}
 
-	engine, dbPool, err := server.ConfigureAppEngine(appConfig, logLevel)
+	configuredLogger, logCloser, err := log.ConfigureLogger(appConfig.App, logLevel)
+	if err != nil {
+		logger.Error("Error configuring logger", slog.String("error", err.Error()))
+		return
+	}
+	defer logCloser.Close()
+	logger = configuredLogger
+
+	app, err := server.ConfigureAppEngine(appConfig, logLevel, logger)
 	if err != nil {
 		logger.Error("Unable to configure app engine", slog.String("error", err.Error()))
 		return
 	}
-	defer dbPool.Close()
+	defer app.Close()
+	app.Go(func(ctx context.Context) {
+		server.ReloadOnSignal(ctx, logLevel, logger)
+	})
 
-	err = engine.Run(":8080")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err = app.Serve(ctx, ":8080", appConfig.App)
 	if err != nil {
 		logger.Error("Error starting server", slog.String("error", err.Error()))
 	}
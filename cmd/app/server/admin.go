@@ -0,0 +1,93 @@
+package server
+
+import (
+	"crud/cmd/app/config"
+	"crud/internal/auth"
+	"crud/internal/middleware"
+	"errors"
+	"expvar"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"net/http"
+	"net/http/pprof"
+	runtimePprof "runtime/pprof"
+	"strings"
+)
+
+// poolStats is the state of the database connection pool
+type poolStats struct {
+	AcquiredConns           int32   `json:"acquired_conns"`
+	IdleConns               int32   `json:"idle_conns"`
+	ConstructingConns       int32   `json:"constructing_conns"`
+	TotalConns              int32   `json:"total_conns"`
+	MaxConns                int32   `json:"max_conns"`
+	AcquireCount            int64   `json:"acquire_count"`
+	AcquireDurationMs       float64 `json:"acquire_duration_ms"`
+	EmptyAcquireCount       int64   `json:"empty_acquire_count"`
+	CanceledAcquireCount    int64   `json:"canceled_acquire_count"`
+	NewConnsCount           int64   `json:"new_conns_count"`
+	MaxLifetimeDestroyCount int64   `json:"max_lifetime_destroy_count"`
+	MaxIdleDestroyCount     int64   `json:"max_idle_destroy_count"`
+}
+
+func newPoolStats(stat *pgxpool.Stat) poolStats {
+	return poolStats{
+		AcquiredConns:           stat.AcquiredConns(),
+		IdleConns:               stat.IdleConns(),
+		ConstructingConns:       stat.ConstructingConns(),
+		TotalConns:              stat.TotalConns(),
+		MaxConns:                stat.MaxConns(),
+		AcquireCount:            stat.AcquireCount(),
+		AcquireDurationMs:       float64(stat.AcquireDuration().Microseconds()) / 1000,
+		EmptyAcquireCount:       stat.EmptyAcquireCount(),
+		CanceledAcquireCount:    stat.CanceledAcquireCount(),
+		NewConnsCount:           stat.NewConnsCount(),
+		MaxLifetimeDestroyCount: stat.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     stat.MaxIdleDestroyCount(),
+	}
+}
+
+// setupAdminEngine creates the engine of the diagnostics listener. With authentication its routes need the
+// ops:admin permission, without it they need the admin token. The listener refuses to start without either
+func setupAdminEngine(appConfig *config.Config, pool *pgxpool.Pool, authenticate gin.HandlerFunc, policy *auth.Policy) (*gin.Engine, error) {
+	admin := gin.New()
+	admin.Use(gin.Recovery())
+	if authenticate != nil && policy != nil {
+		admin.Use(authenticate, middleware.RequireAuthMiddleware(), middleware.RequirePermissionMiddleware(policy, auth.PermissionOpsAdmin))
+	} else if appConfig.Admin.Token != "" {
+		admin.Use(middleware.RequireAdminTokenMiddleware(appConfig.Admin.Token))
+	} else {
+		return nil, errors.New("the admin listener needs AUTH_ENABLED=true or ADMIN_TOKEN")
+	}
+
+	debug := admin.Group("/debug")
+	debug.Any("/pprof/*profile", gin.WrapF(servePprof))
+	debug.GET("/vars", gin.WrapH(expvar.Handler()))
+	debug.GET("/goroutines", func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		_ = runtimePprof.Lookup("goroutine").WriteTo(c.Writer, 2)
+	})
+	debug.GET("/pool", func(c *gin.Context) {
+		c.JSON(http.StatusOK, newPoolStats(pool.Stat()))
+	})
+	debug.GET("/config", func(c *gin.Context) {
+		c.JSON(http.StatusOK, appConfig.Redacted())
+	})
+	return admin, nil
+}
+
+// servePprof dispatches to the net/http/pprof handlers, the index also serves the named runtime profiles
+func servePprof(writer http.ResponseWriter, request *http.Request) {
+	switch strings.TrimPrefix(request.URL.Path, "/debug/pprof/") {
+	case "cmdline":
+		pprof.Cmdline(writer, request)
+	case "profile":
+		pprof.Profile(writer, request)
+	case "symbol":
+		pprof.Symbol(writer, request)
+	case "trace":
+		pprof.Trace(writer, request)
+	default:
+		pprof.Index(writer, request)
+	}
+}
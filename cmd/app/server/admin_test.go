@@ -0,0 +1,57 @@
+package server
+
+import (
+	"crud/cmd/app/config"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitAdminEngine(t *testing.T) {
+	t.Parallel()
+
+	appConfig := &config.Config{
+		DB:    config.DatabaseConfig{Host: "db", Password: "postgres-password", Params: "sslpassword=secret"},
+		Admin: config.AdminConfig{Enabled: "true", Address: "127.0.0.1:6060"},
+	}
+	_, err := setupAdminEngine(appConfig, nil, nil, nil)
+	assert.Error(t, err, "without authentication the listener needs a token, even on a loopback address")
+
+	appConfig.Admin.Token = "admin-token"
+	admin, err := setupAdminEngine(appConfig, nil, nil, nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	admin.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	admin.ServeHTTP(recorder, adminRequest("/debug/config"))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var redacted map[string]map[string]string
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &redacted))
+	assert.Equal(t, "db", redacted["DB"]["Host"])
+	assert.Equal(t, "[REDACTED]", redacted["DB"]["Password"])
+	assert.Equal(t, "[REDACTED]", redacted["DB"]["Params"])
+	assert.Equal(t, "[REDACTED]", redacted["Admin"]["Token"])
+	assert.Equal(t, "", redacted["Auth"]["HMACSecret"], "empty secrets are shown as unset")
+
+	recorder = httptest.NewRecorder()
+	admin.ServeHTTP(recorder, adminRequest("/debug/pprof/goroutine?debug=1"))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "goroutine profile")
+
+	recorder = httptest.NewRecorder()
+	admin.ServeHTTP(recorder, adminRequest("/debug/vars"))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "memstats")
+}
+
+func adminRequest(target string) *http.Request {
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	request.Header.Set("Authorization", "Bearer admin-token")
+	return request
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"crud/cmd/app/config"
+	"crud/internal/probe"
+	"crud/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	heartbeatInterval = time.Second
+	// heartbeatMaxAge fails liveness when the scheduler could not run the heartbeat for that long
+	heartbeatMaxAge = 10 * time.Second
+)
+
+// App is the configured application: the HTTP engine, its database pool, its probes and the background
+// workers, which are stopped before the pool is closed
+type App struct {
+	Engine *gin.Engine
+	DBPool *pgxpool.Pool
+	Probes *probe.Registry
+	// AdminEngine serves the diagnostics on AdminAddress, it is nil when the admin listener is disabled
+	AdminEngine  *gin.Engine
+	AdminAddress string
+	// Jobs enqueues the background jobs, it is nil when the job worker is disabled
+	Jobs service.IJobService
+
+	logger        *slog.Logger
+	onShutdown    []func()
+	workersCtx    context.Context
+	cancelWorkers context.CancelFunc
+	workers       sync.WaitGroup
+}
+
+func newApp(pool *pgxpool.Pool, logger *slog.Logger) *App {
+	workersCtx, cancelWorkers := context.WithCancel(context.Background())
+	return &App{
+		DBPool:        pool,
+		Probes:        probe.NewRegistry(),
+		logger:        logger,
+		workersCtx:    workersCtx,
+		cancelWorkers: cancelWorkers,
+	}
+}
+
+// Go runs a background worker until the application is closed
+func (application *App) Go(run func(ctx context.Context)) {
+	application.workers.Add(1)
+	go func() {
+		defer application.workers.Done()
+		run(application.workersCtx)
+	}()
+}
+
+// OnShutdown registers fn to run when the servers shut down, e.g. to end long-lived requests which would
+// hold up the shutdown
+func (application *App) OnShutdown(fn func()) {
+	application.onShutdown = append(application.onShutdown, fn)
+}
+
+// Serve serves the engine on address, and the admin engine when there is one, until ctx is done. Then it shuts
+// down gracefully: readiness fails first, after the shutdown delay no new connections are accepted and running
+// requests get the shutdown timeout to finish
+func (application *App) Serve(ctx context.Context, address string, appConfig config.AppConfig) error {
+	shutdownDelay, err := appConfig.GetShutdownDelay()
+	if err != nil {
+		return err
+	}
+	shutdownTimeout, err := appConfig.GetShutdownTimeout()
+	if err != nil {
+		return err
+	}
+	servers := []*http.Server{{Addr: address, Handler: application.Engine}}
+	if application.AdminEngine != nil {
+		servers = append(servers, &http.Server{Addr: application.AdminAddress, Handler: application.AdminEngine})
+	}
+	listeners := make([]net.Listener, 0, len(servers))
+	for _, server := range servers {
+		listener, err := net.Listen("tcp", server.Addr)
+		if err != nil {
+			for _, listener := range listeners {
+				_ = listener.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, listener)
+	}
+	serveErrors := make(chan error, len(servers))
+	for i, server := range servers {
+		for _, fn := range application.onShutdown {
+			server.RegisterOnShutdown(fn)
+		}
+		go func() {
+			serveErrors <- server.Serve(listeners[i])
+		}()
+		application.logger.Info("Listening", slog.String("address", listeners[i].Addr().String()))
+	}
+	application.Probes.MarkStarted()
+
+	select {
+	case err = <-serveErrors:
+	case <-ctx.Done():
+		application.logger.Info("Shutting down", slog.Duration("delay", shutdownDelay))
+		application.Probes.MarkShuttingDown()
+		select {
+		case <-time.After(shutdownDelay):
+		case err = <-serveErrors:
+		}
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, server := range servers {
+		err = errors.Join(err, server.Shutdown(shutdownCtx))
+	}
+	return err
+}
+
+// Close stops the background workers, waits for them to finish and closes the database pool
+func (application *App) Close() {
+	application.cancelWorkers()
+	application.workers.Wait()
+	application.DBPool.Close()
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"crud/cmd/app/config"
+	logConfig "crud/cmd/app/config/log"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"crud/internal/service"
+	"crud/internal/tenant"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIntegrationJobs(t *testing.T) {
+	const secret = "0123456789abcdef0123456789abcdef"
+	logger, logLevel := logConfig.CreateLogger()
+	appConfig := config.Config{DB: startPostgres(t), App: config.AppConfig{
+		LogLevel: "info",
+		AppMode:  "test",
+	}, Auth: config.AuthConfig{Enabled: "true", HMACSecret: secret},
+		Job: config.JobConfig{Enabled: "true", PollInterval: "50ms"}}
+
+	// The reports fail until the test lets them succeed
+	var failing atomic.Bool
+	failing.Store(true)
+	reports := service.JobType[map[string]int]{Name: "reports.send", MaxAttempts: 2}
+	app, err := ConfigureAppEngine(&appConfig, logLevel, logger, func(*pgxpool.Pool, *slog.Logger) service.JobHandler {
+		return reports.Handler(func(context.Context, map[string]int) error {
+			if failing.Load() {
+				return errors.New("report service unavailable")
+			}
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	server := httptest.NewServer(app.Engine.Handler())
+	defer app.Close()
+	defer server.Close()
+	client := server.Client()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"admin"},
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+	send := func(method, path string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		httpResponse, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = httpResponse.Body.Close() })
+		return httpResponse
+	}
+	jobs := func(status string) []model.JobResponse {
+		httpResponse := send(http.MethodGet, "/admin/jobs?status="+status)
+		require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+		var jobs []model.JobResponse
+		require.NoError(t, json.NewDecoder(httpResponse.Body).Decode(&jobs))
+		return jobs
+	}
+
+	jobService := app.Jobs
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultTenant)
+	request, err := reports.Request(map[string]int{"user_id": 5})
+	require.NoError(t, err)
+	request.RunAt, request.UniqueKey = time.Now().Add(time.Hour), "user-5"
+	delayed, err := jobService.Enqueue(request, &ctx)
+	require.NoError(t, err)
+	duplicate, err := jobService.Enqueue(request, &ctx)
+	require.NoError(t, err)
+	assert.Equal(t, delayed.ID, duplicate.ID, "the pending job holds the unique key")
+
+	request, err = reports.Request(map[string]int{"user_id": 6})
+	require.NoError(t, err)
+	job, err := jobService.Enqueue(request, &ctx)
+	require.NoError(t, err)
+
+	// The first attempt is retried after 5s
+	require.Eventually(t, func() bool {
+		failed := jobs(model.JobFailed)
+		return len(failed) == 1 && failed[0].ID == job.ID
+	}, 15*time.Second, 100*time.Millisecond)
+	failed := jobs(model.JobFailed)[0]
+	assert.Equal(t, 2, failed.Attempts)
+	require.NotNil(t, failed.LastError)
+	assert.Equal(t, "report service unavailable", *failed.LastError)
+	pending := jobs(model.JobPending)
+	purges := slices.DeleteFunc(slices.Clone(pending), func(job model.JobResponse) bool {
+		return job.Type != service.PurgeSessionsJob.Name
+	})
+	assert.Len(t, purges, 1, "the worker schedules the purge of the sessions")
+	pending = slices.DeleteFunc(pending, func(job model.JobResponse) bool { return job.Type == service.PurgeSessionsJob.Name })
+	require.Len(t, pending, 1, "the delayed job waits")
+	assert.Equal(t, delayed.ID, pending[0].ID)
+
+	failing.Store(false)
+	httpResponse := send(http.MethodPost, fmt.Sprintf("/admin/jobs/%d/retry", job.ID))
+	require.Equal(t, http.StatusAccepted, httpResponse.StatusCode)
+	require.Eventually(t, func() bool {
+		succeeded := jobs(model.JobSucceeded)
+		return len(succeeded) == 1 && succeeded[0].ID == job.ID
+	}, 5*time.Second, 50*time.Millisecond)
+	httpResponse = send(http.MethodPost, fmt.Sprintf("/admin/jobs/%d/retry", job.ID))
+	assert.Equal(t, http.StatusNotFound, httpResponse.StatusCode, "only failed jobs are retried")
+
+	// The purge of the application deletes the expired and revoked sessions, it is run at once here
+	sessionRepository := repository.NewSessionRepository(app.DBPool)
+	createSession := func(tokenHash string, expiresAt time.Time) *model.SessionModel {
+		session, err := sessionRepository.Create(&model.SessionModel{UserID: 1, TokenHash: []byte(tokenHash),
+			ExpiresAt: expiresAt}, &ctx)
+		require.NoError(t, err)
+		return session
+	}
+	expired := createSession("expired", time.Now().Add(-time.Minute))
+	revoked := createSession("revoked", time.Now().Add(time.Hour))
+	require.NoError(t, sessionRepository.Revoke(revoked.ID, &ctx))
+	active := createSession("active", time.Now().Add(time.Hour))
+	request, err = service.PurgeSessionsJob.Request(struct{}{})
+	require.NoError(t, err)
+	purge, err := jobService.Enqueue(request, &ctx)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return slices.ContainsFunc(jobs(model.JobSucceeded), func(job model.JobResponse) bool { return job.ID == purge.ID })
+	}, 5*time.Second, 50*time.Millisecond)
+	for _, session := range []*model.SessionModel{expired, revoked} {
+		_, err = sessionRepository.GetByTokenHash(session.TokenHash, &ctx)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	}
+	_, err = sessionRepository.GetByTokenHash(active.TokenHash, &ctx)
+	assert.NoError(t, err, "active sessions are kept")
+}
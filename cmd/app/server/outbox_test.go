@@ -0,0 +1,74 @@
+package server
+
+import (
+	"bufio"
+	"crud/cmd/app/config"
+	logConfig "crud/cmd/app/config/log"
+	"crud/internal/model"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIntegrationOutbox(t *testing.T) {
+	logger, logLevel := logConfig.CreateLogger()
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	appConfig := config.Config{DB: startPostgres(t), App: config.AppConfig{
+		LogLevel: "info",
+		AppMode:  "test",
+	}, Outbox: config.OutboxConfig{Sinks: "file://" + path, PollInterval: "50ms"}}
+
+	app, err := ConfigureAppEngine(&appConfig, logLevel, logger)
+	require.NoError(t, err)
+	server := httptest.NewServer(app.Engine.Handler())
+	defer app.Close()
+	defer server.Close()
+	client := server.Client()
+
+	httpResponse, err := client.Post(server.URL+"/api/v1/user/", "application/json",
+		strings.NewReader(`{"name":"Ada","email":"ada@example.com","age":36}`))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, httpResponse.StatusCode)
+	var user model.UserResponse
+	require.NoError(t, json.NewDecoder(httpResponse.Body).Decode(&user))
+	_ = httpResponse.Body.Close()
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/user/%d", server.URL, user.ID), nil)
+	require.NoError(t, err)
+	httpResponse, err = client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	_ = httpResponse.Body.Close()
+
+	var events []model.EventMessage
+	require.Eventually(t, func() bool {
+		file, err := os.Open(path)
+		if err != nil {
+			return false
+		}
+		defer file.Close()
+		events = events[:0]
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var event model.EventMessage
+			if json.Unmarshal(scanner.Bytes(), &event) == nil {
+				events = append(events, event)
+			}
+		}
+		return len(events) == 2
+	}, 10*time.Second, 50*time.Millisecond)
+	assert.Equal(t, model.UserCreatedEvent, events[0].Type)
+	assert.Equal(t, model.UserDeletedEvent, events[1].Type, "the events of a user are relayed in order")
+	assert.Equal(t, fmt.Sprint(user.ID), events[1].AggregateID)
+
+	var pending int
+	require.NoError(t, app.DBPool.QueryRow(t.Context(), "SELECT count(*) FROM outbox_events WHERE published_at IS NULL").Scan(&pending))
+	assert.Equal(t, 0, pending)
+}
@@ -5,58 +5,41 @@ import (
 	"crud/cmd/app/config"
 	"crud/cmd/app/config/database"
 	"crud/internal"
+	"crud/internal/auth"
+	"crud/internal/buildinfo"
 	"crud/internal/middleware"
+	"crud/internal/outbox"
+	"crud/internal/probe"
+	"crud/internal/querystats"
+	"crud/internal/ratelimit"
+	"crud/internal/repository"
 	"crud/internal/repository/db"
-	"database/sql"
+	"crud/internal/service"
+	"crud/internal/stream"
+	"crud/internal/tenant"
+	logUtil "crud/internal/util/log"
+	"crud/internal/util/request"
+	"crud/internal/webhook"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file" // To allow file:// path in migration
 	"github.com/hellofresh/health-go/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/jackc/pgx/v5/stdlib"
 	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
 	"time"
 )
 
-func runDbMigration(pool *pgxpool.Pool) error {
-	migrationDriver, err := db.GetMigrationDriver()
-	if err != nil {
-		return err
-	}
-	sqlDB := stdlib.OpenDBFromPool(pool)
-	conn, err := sqlDB.Conn(context.Background())
-	defer func(conn *sql.Conn) {
-		err := conn.Close()
-		if err != nil {
-			slog.Default().Warn("failed to close database connection", slog.String("error", err.Error()))
-		}
-	}(conn)
-	driver, err := postgres.WithConnection(context.Background(), conn, &postgres.Config{})
-	if err != nil {
-		return err
-	}
-	m, err := migrate.NewWithInstance("iofs", migrationDriver, "postgres", driver)
-	if err != nil {
-		return err
-	}
-	err = m.Up()
-	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return err
-	}
-	if errors.Is(err, migrate.ErrNoChange) {
-		return nil
-	} else {
-		return err
-	}
-}
-
-func setupHealthCheck(app *gin.Engine, pool *pgxpool.Pool) error {
+func setupHealthCheck(app *gin.Engine, pool *pgxpool.Pool, middlewares ...gin.HandlerFunc) error {
 	healthcheck, err := health.New(health.WithSystemInfo(), health.WithComponent(health.Component{
 		Name:    "crud",
-		Version: "v1.0.0",
+		Version: buildinfo.Get().String(),
 	}))
 	if err != nil {
 		return err
@@ -72,18 +55,141 @@ func setupHealthCheck(app *gin.Engine, pool *pgxpool.Pool) error {
 	if err != nil {
 		return err
 	}
-	app.GET("/status", func(c *gin.Context) {
+	handlers := append(slices.Clone(middlewares), func(c *gin.Context) {
 		healthcheck.HandlerFunc(c.Writer, c.Request)
 	})
+	app.GET("/status", handlers...)
 	return nil
 }
 
-func ConfigureAppEngine(appConfig *config.Config, logLevelVar *slog.LevelVar) (*gin.Engine, *pgxpool.Pool, error) {
-	logger := slog.Default()
+// setupVersion serves the build information of the binary
+func setupVersion(app *gin.Engine, middlewares ...gin.HandlerFunc) {
+	handlers := append(slices.Clone(middlewares), func(c *gin.Context) {
+		c.JSON(http.StatusOK, buildinfo.Get())
+	})
+	app.GET("/version", handlers...)
+}
+
+// setupProbes serves the liveness, readiness and startup probes of the registry
+func setupProbes(app *gin.Engine, probes *probe.Registry, middlewares ...gin.HandlerFunc) {
+	for _, kind := range []probe.Kind{probe.Liveness, probe.Readiness, probe.Startup} {
+		handlers := append(slices.Clone(middlewares), gin.WrapF(probes.Handler(kind)))
+		app.GET("/"+string(kind), handlers...)
+	}
+}
+
+// setupAuthenticators creates the authenticators by Authorization scheme: password sessions and JWTs
+// from the configured keys as bearer tokens, and API keys from the database
+func setupAuthenticators(application *App, authConfig config.AuthConfig, logger *slog.Logger) (map[string]auth.Authenticator, error) {
+	var keys auth.KeyProvider
+	switch {
+	case authConfig.KeysFile != "" && authConfig.HMACSecret != "":
+		return nil, errors.New("configure either a JWT keys file or an HMAC secret, put both keys in a JWKS file to use them together")
+	case authConfig.KeysFile != "":
+		interval, err := authConfig.GetKeysReloadInterval()
+		if err != nil {
+			return nil, err
+		}
+		if keys, err = auth.NewFileKeyProvider(authConfig.KeysFile, interval, logger); err != nil {
+			return nil, err
+		}
+	case authConfig.HMACSecret != "":
+		keySet := auth.NewKeySet()
+		keySet.Add("", []byte(authConfig.HMACSecret))
+		keys = auth.NewStaticKeyProvider(keySet)
+	}
+	// Without JWT keys only session tokens are accepted as bearer tokens
+	var verifier auth.Authenticator
+	if keys != nil {
+		verifier = auth.NewJWTVerifier(keys, authConfig.Issuer, authConfig.Audience, time.Minute)
+	}
+	sessionRepository := repository.NewSessionRepository(application.DBPool)
+
+	apiKeyRepository := repository.NewAPIKeyRepository(application.DBPool)
+	usageRecorder := service.NewAPIKeyUsageRecorder(apiKeyRepository, service.DefaultAPIKeyUsageFlushInterval, logger)
+	application.Go(usageRecorder.Run)
+	return map[string]auth.Authenticator{
+		"bearer": service.NewSessionAuthenticator(sessionRepository, authConfig.GetSessionRoles(), verifier),
+		"apikey": service.NewAPIKeyAuthenticator(apiKeyRepository, usageRecorder),
+	}, nil
+}
+
+func setupAccountOptions(authConfig config.AuthConfig) (service.AccountOptions, error) {
+	maxFailedLogins, err := authConfig.GetMaxFailedLogins()
+	if err != nil {
+		return service.AccountOptions{}, err
+	}
+	lockoutDuration, err := authConfig.GetLockoutDuration()
+	if err != nil {
+		return service.AccountOptions{}, err
+	}
+	sessionTTL, err := authConfig.GetSessionTTL()
+	if err != nil {
+		return service.AccountOptions{}, err
+	}
+	return service.AccountOptions{
+		MaxFailedLogins: maxFailedLogins,
+		LockoutDuration: lockoutDuration,
+		SessionTTL:      sessionTTL,
+	}, nil
+}
 
-	logger.Info("Starting server")
+// setupRateLimiters creates the limiter of the IP limit, nil when none is configured, and the one of the default and
+// route limits. Both share the store
+func setupRateLimiters(rateLimitConfig config.RateLimitConfig, pool *pgxpool.Pool, logger *slog.Logger) (*ratelimit.Limiter, *ratelimit.Limiter, error) {
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+	if rateLimitConfig.IsPostgresStore() {
+		store = ratelimit.NewPostgresStore(pool, logger)
+	}
+	var ipLimiter *ratelimit.Limiter
+	if rateLimitConfig.IPLimit != "" {
+		limit, err := ratelimit.ParseLimit(rateLimitConfig.IPLimit)
+		if err != nil {
+			return nil, nil, err
+		}
+		ipLimiter = ratelimit.NewLimiter(store, &limit, nil)
+	}
+	limiter, err := setupRateLimiter(rateLimitConfig, store)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ipLimiter, limiter, nil
+}
 
-	appLogLevel, err := appConfig.App.ToSlogLevel()
+func setupRateLimiter(rateLimitConfig config.RateLimitConfig, store ratelimit.Store) (*ratelimit.Limiter, error) {
+	var defaultLimit *ratelimit.Limit
+	if rateLimitConfig.DefaultLimit != "" {
+		limit, err := ratelimit.ParseLimit(rateLimitConfig.DefaultLimit)
+		if err != nil {
+			return nil, err
+		}
+		defaultLimit = &limit
+	}
+	routeLimits, err := ratelimit.ParseRouteLimits(rateLimitConfig.RouteLimits)
+	if err != nil {
+		return nil, err
+	}
+	return ratelimit.NewLimiter(store, defaultLimit, routeLimits), nil
+}
+
+func setupRedactionPolicy(appConfig config.AppConfig) (logUtil.RedactionPolicy, error) {
+	policy := logUtil.DefaultRedactionPolicy()
+	policy.Enabled = appConfig.IsLogRedactionEnabled()
+	policy.Keys = append(append([]string{}, logUtil.DefaultRedactKeys...), appConfig.GetLogRedactKeys()...)
+	if columns := appConfig.GetLogRedactColumns(); columns != nil {
+		policy.AllowedColumns = columns
+	}
+	maxValueLength, err := appConfig.GetLogMaxValueLength(logUtil.DefaultMaxValueLength)
+	if err != nil {
+		return policy, err
+	}
+	policy.MaxValueLength = maxValueLength
+	return policy, nil
+}
+
+// applyLogSettings applies the log settings which can change at runtime: the level and the redaction policy
+func applyLogSettings(appConfig config.AppConfig, logLevelVar *slog.LevelVar, logger *slog.Logger) error {
+	appLogLevel, err := appConfig.ToSlogLevel()
 	if err != nil {
 		logger.Warn("Error converting app log level. Using default level",
 			slog.String("error", err.Error()),
@@ -94,17 +200,96 @@ func ConfigureAppEngine(appConfig *config.Config, logLevelVar *slog.LevelVar) (*
 	logger.Info("Setting log level", slog.String("level", appLogLevel.String()))
 	logLevelVar.Set(appLogLevel)
 
-	dbPool, err := database.NewPool(appConfig.DB)
+	redactionPolicy, err := setupRedactionPolicy(appConfig)
+	if err != nil {
+		return err
+	}
+	if !redactionPolicy.Enabled {
+		logger.Warn("Log redaction is disabled, secrets may be written to logs")
+	}
+	logUtil.DefaultRedactor().SetPolicy(redactionPolicy)
+	return nil
+}
+
+// ReloadOnSignal re-reads the configuration on SIGHUP and applies the log settings until ctx is done.
+// Other settings need a restart
+func ReloadOnSignal(ctx context.Context, logLevelVar *slog.LevelVar, logger *slog.Logger) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			logger.Info("Reloading configuration")
+			appConfig, err := config.LoadConfig()
+			if appConfig == nil {
+				logger.Error("Error reloading config", slog.String("error", err.Error()))
+				continue
+			}
+			if err = applyLogSettings(appConfig.App, logLevelVar, logger); err != nil {
+				logger.Error("Error applying reloaded log settings", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// runDbMigration migrates the schema of the pool, and the schemas of all tenants in the schema per tenant mode
+func runDbMigration(pool *pgxpool.Pool, schemas *db.TenantSchemas, logger *slog.Logger) error {
+	if schemas != nil {
+		return schemas.MigrateAll(context.Background(), pool)
+	}
+	return db.Migrate(context.Background(), pool, logger)
+}
+
+// ConfigureAppEngine connects to the database, runs the migrations and sets up the routes, probes and background
+// workers. The jobs are registered with the job worker next to the jobs of the application. The caller serves the
+// application and closes it
+func ConfigureAppEngine(appConfig *config.Config, logLevelVar *slog.LevelVar, logger *slog.Logger, jobs ...JobRegistration) (*App, error) {
+	buildinfo.Publish()
+	logger.Info("Starting server", buildinfo.Get().LogAttrs()...)
+
+	if err := applyLogSettings(appConfig.App, logLevelVar, logger); err != nil {
+		logger.Error("Error parsing log redaction policy", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	queryStats := querystats.NewCollector(querystats.DefaultMaxStatements)
+	var schemas *db.TenantSchemas
+	if appConfig.Tenant.IsSchemaMode() {
+		schemas = db.NewTenantSchemas(appConfig.DB.Schema, logger)
+	}
+	dbPool, err := database.NewPool(appConfig.DB, queryStats, schemas, logger)
 	if err != nil {
 		logger.Error("Error connecting to database", slog.String("error", err.Error()))
-		return nil, nil, err
+		return nil, err
 	}
 
-	if err = runDbMigration(dbPool); err != nil {
+	if err = runDbMigration(dbPool, schemas, logger); err != nil {
 		dbPool.Close()
 		logger.Error("Error running migration", slog.String("error", err.Error()))
-		return nil, nil, err
+		return nil, err
+	}
+	latestMigration, err := db.LatestMigrationVersion()
+	if err != nil {
+		dbPool.Close()
+		logger.Error("Error reading migrations", slog.String("error", err.Error()))
+		return nil, err
+	}
+	saturationThreshold, err := appConfig.DB.GetPoolSaturationThreshold()
+	if err != nil {
+		dbPool.Close()
+		logger.Error("Error parsing pool saturation threshold", slog.String("error", err.Error()))
+		return nil, err
 	}
+	application := newApp(dbPool, logger)
+	database.RegisterProbeChecks(application.Probes, dbPool, latestMigration, saturationThreshold)
+	heartbeat := probe.NewHeartbeat(heartbeatMaxAge)
+	application.Go(func(ctx context.Context) {
+		heartbeat.Run(ctx, heartbeatInterval)
+	})
+	application.Probes.Register(probe.Check{Name: "heartbeat", Func: heartbeat.Check}, probe.Liveness)
 
 	if appConfig.App.IsAppInReleaseMode() {
 		logger.Info("Running app in release mode")
@@ -120,13 +305,338 @@ func ConfigureAppEngine(appConfig *config.Config, logLevelVar *slog.LevelVar) (*
 			slog.String("handler", handlerName),
 			slog.Int("handlers", nuHandlers))
 	}
+	clientIPResolver, err := request.NewClientIPResolver(appConfig.App.GetTrustedProxies(), appConfig.App.GetClientIPHeader())
+	if err != nil {
+		application.Close()
+		logger.Error("Error parsing trusted proxies", slog.String("error", err.Error()))
+		return nil, err
+	}
 	app := gin.New()
-	if err = setupHealthCheck(app, dbPool); err != nil {
+	// Recovery comes first, the panics of the other middlewares are recovered too
+	app.Use(gin.Recovery())
+	// Keep gin's own c.ClientIP() in line with the resolver used for logging and rate limiting
+	app.RemoteIPHeaders = []string{appConfig.App.GetClientIPHeader()}
+	if err = app.SetTrustedProxies(appConfig.App.GetTrustedProxies()); err != nil {
+		application.Close()
+		logger.Error("Error setting trusted proxies", slog.String("error", err.Error()))
+		return nil, err
+	}
+	// Every route, the status ones too, runs with a request id
+	app.Use(middleware.RequestIDMiddleware(appConfig.App.GetRequestIDHeaders()))
+	routerOptions := internal.RouterOptions{
+		QueryStats:         queryStats,
+		ExplainSlowQueries: appConfig.DB.IsExplainSlowQueriesEnabled(),
+		Logger:             logger,
+		LogLevel:           logLevelVar,
+		DebugLogSecret:     []byte(appConfig.App.LogDebugSecret),
+		RecordEvents:       appConfig.RecordsEvents(),
+		Webhooks:           appConfig.Webhook.IsEnabled(),
+		AllowedOrigins:     appConfig.App.GetCORSAllowedOrigins(),
+	}
+	statusMiddlewares := make([]gin.HandlerFunc, 0)
+	var authenticate gin.HandlerFunc
+	if appConfig.Auth.IsEnabled() {
+		authenticators, err := setupAuthenticators(application, appConfig.Auth, logger)
+		if err != nil {
+			application.Close()
+			logger.Error("Error setting up authentication", slog.String("error", err.Error()))
+			return nil, err
+		}
+		rolePermissions := auth.DefaultRolePermissions
+		if appConfig.Auth.RolePermissions != "" {
+			if rolePermissions, err = auth.ParseRolePermissions(appConfig.Auth.RolePermissions); err != nil {
+				application.Close()
+				logger.Error("Error parsing role permissions", slog.String("error", err.Error()))
+				return nil, err
+			}
+		}
+		if routerOptions.Account, err = setupAccountOptions(appConfig.Auth); err != nil {
+			application.Close()
+			logger.Error("Error parsing account options", slog.String("error", err.Error()))
+			return nil, err
+		}
+		authenticate = middleware.AuthenticateMiddleware(authenticators)
+		routerOptions.Reauthenticate = func(req *http.Request) (*auth.Principal, error) {
+			return middleware.Authenticate(authenticators, req)
+		}
+		routerOptions.Policy = auth.NewPolicy(rolePermissions, logger)
+		routerOptions.RequireAuth = middleware.RequireAuthMiddleware()
+		routerOptions.AnonymousSwagger = appConfig.Auth.AllowsAnonymousSwagger()
+		routerOptions.RequireAdmin = []gin.HandlerFunc{routerOptions.RequireAuth,
+			middleware.RequirePermissionMiddleware(routerOptions.Policy, auth.PermissionOpsAdmin)}
+		if !appConfig.Auth.AllowsAnonymousStatus() {
+			statusMiddlewares = append(statusMiddlewares, authenticate, routerOptions.RequireAuth)
+		}
+	} else if appConfig.Admin.Token != "" {
+		routerOptions.RequireAdmin = []gin.HandlerFunc{middleware.RequireAdminTokenMiddleware(appConfig.Admin.Token)}
+	}
+	if err = setupHealthCheck(app, dbPool, statusMiddlewares...); err != nil {
+		application.Close()
 		logger.Error("Error setting up health check", slog.String("error", err.Error()))
-		return nil, nil, err
+		return nil, err
 	}
-	app.Use(middleware.JSONLogMiddleware())
-	app.Use(gin.Recovery())
-	internal.SetupRouter(dbPool, app)
-	return app, dbPool, nil
+	setupProbes(app, application.Probes, statusMiddlewares...)
+	setupVersion(app, statusMiddlewares...)
+	if appConfig.App.LogDebugSecret != "" {
+		app.Use(middleware.DebugLogMiddleware([]byte(appConfig.App.LogDebugSecret)))
+	}
+	app.Use(middleware.ClientIPMiddleware(clientIPResolver))
+	app.Use(middleware.JSONLogMiddleware(logger))
+	var limiter *ratelimit.Limiter
+	if appConfig.RateLimit.IsEnabled() {
+		var ipLimiter *ratelimit.Limiter
+		if ipLimiter, limiter, err = setupRateLimiters(appConfig.RateLimit, dbPool, logger); err != nil {
+			application.Close()
+			logger.Error("Error setting up rate limiter", slog.String("error", err.Error()))
+			return nil, err
+		}
+		if ipLimiter != nil {
+			// The IP limit runs before the authentication, which rejects bad credentials without taking a token
+			app.Use(middleware.RateLimitMiddleware(ipLimiter, middleware.AuthenticationRateLimitKey))
+		}
+	}
+	tenantResolver := setupTenantResolver(appConfig.Tenant, dbPool, schemas)
+	if authenticate != nil {
+		if schemas != nil {
+			// Sessions and API keys live in the schema of their tenant
+			app.Use(middleware.RequestedTenantMiddleware(tenantResolver))
+		}
+		// Authentication runs before rate limiting so that callers are limited by subject
+		app.Use(authenticate)
+	}
+	// The tenant is resolved after authentication, the claims of the caller take precedence
+	app.Use(middleware.TenantMiddleware(tenantResolver))
+	if appConfig.Admin.IsEnabled() {
+		if application.AdminEngine, err = setupAdminEngine(appConfig, dbPool, authenticate, routerOptions.Policy); err != nil {
+			application.Close()
+			logger.Error("Error setting up admin listener", slog.String("error", err.Error()))
+			return nil, err
+		}
+		application.AdminAddress = appConfig.Admin.GetAddress()
+	}
+	if limiter != nil {
+		app.Use(middleware.RateLimitMiddleware(limiter, middleware.ClientRateLimitKey))
+	}
+	if err = setupUserStream(application, appConfig.Stream, &routerOptions, schemas, logger); err != nil {
+		application.Close()
+		logger.Error("Error setting up user stream", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if appConfig.RecordsEvents() {
+		if err = setupOutboxRelay(application, appConfig.Outbox, appConfig.Webhook.IsEnabled(), schemas, logger); err != nil {
+			application.Close()
+			logger.Error("Error setting up outbox relay", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+	if appConfig.Webhook.IsEnabled() {
+		if err = setupWebhookDispatcher(application, appConfig, &routerOptions, schemas, logger); err != nil {
+			application.Close()
+			logger.Error("Error setting up webhook dispatcher", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+	if appConfig.Job.IsEnabled() {
+		if err = setupJobWorker(application, appConfig.Job, &routerOptions, schemas, slices.Concat(applicationJobs, jobs), logger); err != nil {
+			application.Close()
+			logger.Error("Error setting up job worker", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+	internal.SetupRouter(dbPool, app, routerOptions)
+	application.Engine = app
+	return application, nil
+}
+
+// setupOutboxRelay relays the domain events to the sinks in the background, in the schema per tenant mode
+// the outbox of every tenant schema is relayed. With webhooks the events become deliveries of the subscriptions too
+func setupOutboxRelay(application *App, outboxConfig config.OutboxConfig, webhooks bool, schemas *db.TenantSchemas, logger *slog.Logger) error {
+	pollInterval, err := outboxConfig.GetPollInterval()
+	if err != nil {
+		return err
+	}
+	batchSize, err := outboxConfig.GetBatchSize()
+	if err != nil {
+		return err
+	}
+	retention, err := outboxConfig.GetRetention()
+	if err != nil {
+		return err
+	}
+	maxAttempts, err := outboxConfig.GetMaxAttempts()
+	if err != nil {
+		return err
+	}
+	options := service.OutboxRelayOptions{PollInterval: pollInterval, BatchSize: batchSize, Retention: retention,
+		MaxAttempts: maxAttempts, Tenants: listTenants(application.DBPool, schemas)}
+	sinks, closer, err := outbox.OpenSinks(outboxConfig.Sinks, application.DBPool)
+	if err != nil {
+		return err
+	}
+	if webhooks {
+		sinks = append(sinks, service.NewWebhookFanOut(repository.NewWebhookDeliveryRepository(application.DBPool)))
+	}
+	relay := service.NewOutboxRelay(repository.NewOutboxRepository(application.DBPool), repository.NewTransactor(application.DBPool),
+		sinks, options, logger)
+	application.Go(func(ctx context.Context) {
+		defer closer.Close()
+		relay.Run(ctx)
+	})
+	return nil
+}
+
+// setupUserStream listens for the user changes notified by the database and streams them to the clients, as server-sent
+// events and as the changes of live queries over WebSocket. The streams end when the server shuts down
+func setupUserStream(application *App, streamConfig config.StreamConfig, routerOptions *internal.RouterOptions,
+	schemas *db.TenantSchemas, logger *slog.Logger) error {
+	heartbeatInterval, err := streamConfig.GetHeartbeatInterval()
+	if err != nil {
+		return err
+	}
+	logSize, err := streamConfig.GetLogSize()
+	if err != nil {
+		return err
+	}
+	maxSubscriptions, err := streamConfig.GetMaxSubscriptions()
+	if err != nil {
+		return err
+	}
+	broker := stream.NewBroker(stream.DefaultBuffer)
+	var release func(conn *pgx.Conn)
+	if schemas != nil {
+		release = schemas.Release
+	}
+	listener := stream.NewListener(application.DBPool, stream.UserChangesChannel, broker, release, logger)
+	userStreamService := service.NewUserStreamService(repository.NewUserChangeRepository(application.DBPool), broker,
+		routerOptions.Policy, service.UserStreamOptions{LogSize: logSize, Tenants: listTenants(application.DBPool, schemas)}, logger)
+	application.Go(listener.Run)
+	application.Go(userStreamService.Run)
+	application.OnShutdown(broker.Close)
+	routerOptions.UserStream = userStreamService
+	routerOptions.StreamHeartbeatInterval = heartbeatInterval
+	routerOptions.UserLive = service.NewUserLiveService(repository.NewUserRepository(application.DBPool, logger), broker,
+		routerOptions.Policy)
+	routerOptions.MaxLiveSubscriptions = maxSubscriptions
+	return nil
+}
+
+// setupWebhookDispatcher sends the webhook deliveries in the background. The subscriptions and the deliveries may not
+// target the internal networks, the admin listener included, except for WEBHOOK_ALLOWED_NETWORKS
+func setupWebhookDispatcher(application *App, appConfig *config.Config, routerOptions *internal.RouterOptions,
+	schemas *db.TenantSchemas, logger *slog.Logger) error {
+	webhookConfig := appConfig.Webhook
+	blocked := make([]string, 0, 1)
+	if appConfig.Admin.IsEnabled() {
+		blocked = append(blocked, appConfig.Admin.GetAddress())
+	}
+	targets, err := webhook.NewTargetGuard(webhookConfig.GetAllowedNetworks(), blocked)
+	if err != nil {
+		return err
+	}
+	routerOptions.WebhookTargets = targets
+	maxAttempts, err := webhookConfig.GetMaxAttempts()
+	if err != nil {
+		return err
+	}
+	timeout, err := webhookConfig.GetTimeout()
+	if err != nil {
+		return err
+	}
+	pollInterval, err := webhookConfig.GetPollInterval()
+	if err != nil {
+		return err
+	}
+	dispatcher := service.NewWebhookDispatcher(repository.NewWebhookDeliveryRepository(application.DBPool),
+		service.WebhookDispatcherOptions{
+			PollInterval: pollInterval,
+			MaxAttempts:  maxAttempts,
+			Timeout:      timeout,
+			Targets:      targets,
+			Tenants:      listTenants(application.DBPool, schemas),
+		}, logger)
+	application.Go(dispatcher.Run)
+	return nil
+}
+
+// JobRegistration creates the handler of a job type when the job worker starts. The worker only claims jobs of the
+// registered types, jobs of other types wait for a replica handling them
+type JobRegistration func(pool *pgxpool.Pool, logger *slog.Logger) service.JobHandler
+
+// applicationJobs are the background jobs of the application itself
+var applicationJobs = []JobRegistration{
+	func(pool *pgxpool.Pool, logger *slog.Logger) service.JobHandler {
+		return service.NewPurgeSessionsHandler(repository.NewSessionRepository(pool), logger)
+	},
+}
+
+// setupJobWorker runs the background jobs of the registrations. The worker stops with the application, the running
+// jobs get the shutdown timeout to finish
+func setupJobWorker(application *App, jobConfig config.JobConfig, routerOptions *internal.RouterOptions,
+	schemas *db.TenantSchemas, jobs []JobRegistration, logger *slog.Logger) error {
+	concurrency, err := jobConfig.GetConcurrency()
+	if err != nil {
+		return err
+	}
+	pollInterval, err := jobConfig.GetPollInterval()
+	if err != nil {
+		return err
+	}
+	shutdownTimeout, err := jobConfig.GetShutdownTimeout()
+	if err != nil {
+		return err
+	}
+	retention, err := jobConfig.GetRetention()
+	if err != nil {
+		return err
+	}
+	handlers := make([]service.JobHandler, len(jobs))
+	for i, job := range jobs {
+		handlers[i] = job(application.DBPool, logger)
+	}
+	registry, err := service.NewJobRegistry(handlers...)
+	if err != nil {
+		return err
+	}
+	jobRepository := repository.NewJobRepository(application.DBPool)
+	worker := service.NewJobWorker(jobRepository, registry, service.JobWorkerOptions{
+		Concurrency:     concurrency,
+		PollInterval:    pollInterval,
+		ShutdownTimeout: shutdownTimeout,
+		Retention:       retention,
+		Tenants:         listTenants(application.DBPool, schemas),
+	}, logger)
+	application.Go(worker.Run)
+	application.Jobs = service.NewJobService(jobRepository, registry)
+	routerOptions.Jobs = application.Jobs
+	return nil
+}
+
+// listTenants lists the tenant schemas for the background workers, it is nil unless tenants have schemas of their own
+func listTenants(pool *pgxpool.Pool, schemas *db.TenantSchemas) func(ctx context.Context) ([]string, error) {
+	if schemas == nil {
+		return nil
+	}
+	return func(ctx context.Context) ([]string, error) {
+		return schemas.Tenants(ctx, pool)
+	}
+}
+
+// setupTenantResolver resolves the tenant from the claims, the header and the subdomain, while tenancy is disabled
+// every request belongs to the default tenant. In the schema per tenant mode only provisioned tenants are accepted
+func setupTenantResolver(tenantConfig config.TenantConfig, pool *pgxpool.Pool, schemas *db.TenantSchemas) *middleware.TenantResolver {
+	if !tenantConfig.IsEnabled() {
+		return &middleware.TenantResolver{Default: tenant.DefaultTenant}
+	}
+	resolver := &middleware.TenantResolver{
+		Claim:      tenant.ClaimName,
+		Header:     tenantConfig.GetHeader(),
+		BaseDomain: tenantConfig.BaseDomain,
+		Default:    tenantConfig.Default,
+	}
+	if schemas != nil {
+		resolver.Exists = func(ctx context.Context, tenantID string) (bool, error) {
+			return schemas.Exists(ctx, pool, tenantID)
+		}
+	}
+	return resolver
 }
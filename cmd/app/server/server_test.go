@@ -19,8 +19,39 @@ import (
 const postgresTestPassword = "testpassword"
 
 func TestIntegrationApp(t *testing.T) {
-	_, logLevel := logConfig.CreateLogger()
+	logger, logLevel := logConfig.CreateLogger()
+	appConfig := config.Config{DB: startPostgres(t), App: config.AppConfig{
+		LogLevel: "info",
+		AppMode:  "test",
+	}}
+
+	app, err := ConfigureAppEngine(&appConfig, logLevel, logger)
+	assert.NoError(t, err)
+	server := httptest.NewServer(app.Engine.Handler())
+	client := server.Client()
+
+	httpResponse, err := client.Get(server.URL + "/api/v1/user/")
+	assert.NoError(t, err)
+	body, err := io.ReadAll(httpResponse.Body)
+	var usersResponse []model.UserResponse
+	err = json.Unmarshal(body, &usersResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(usersResponse))
 
+	app.Probes.MarkStarted()
+	for _, path := range []string{"/livez", "/readyz", "/startupz"} {
+		httpResponse, err = client.Get(server.URL + path)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, httpResponse.StatusCode, path)
+	}
+
+	server.Close()
+	app.Close()
+	require.NoError(t, err)
+}
+
+// startPostgres starts a PostgreSQL container, removed when the test ends, and returns its configuration
+func startPostgres(t *testing.T) config.DatabaseConfig {
 	req := testcontainers.ContainerRequest{
 		Image:        "postgres:17-alpine",
 		ExposedPorts: []string{"5432/tcp"},
@@ -32,11 +63,12 @@ func TestIntegrationApp(t *testing.T) {
 		ContainerRequest: req,
 		Started:          true,
 	})
-	assert.NoError(t, err)
+	testcontainers.CleanupContainer(t, postgres)
+	require.NoError(t, err)
 	endpoint, err := postgres.Endpoint(ctx, "")
-	assert.NoError(t, err)
+	require.NoError(t, err)
 	hostAndPort := strings.Split(endpoint, ":")
-	appConfig := config.Config{DB: config.DatabaseConfig{
+	return config.DatabaseConfig{
 		Host:     hostAndPort[0],
 		Port:     hostAndPort[1],
 		Username: "postgres",
@@ -44,26 +76,5 @@ func TestIntegrationApp(t *testing.T) {
 		Database: "postgres",
 		Schema:   "public",
 		Params:   "",
-	}, App: config.AppConfig{
-		LogLevel: "info",
-		AppMode:  "test",
-	}}
-
-	engine, dbPool, err := ConfigureAppEngine(&appConfig, logLevel)
-	assert.NoError(t, err)
-	server := httptest.NewServer(engine.Handler())
-	client := server.Client()
-
-	httpResponse, err := client.Get(server.URL + "/api/v1/user/")
-	assert.NoError(t, err)
-	body, err := io.ReadAll(httpResponse.Body)
-	var usersResponse []model.UserResponse
-	err = json.Unmarshal(body, &usersResponse)
-	assert.NoError(t, err)
-	assert.Equal(t, 3, len(usersResponse))
-
-	server.Close()
-	dbPool.Close()
-	testcontainers.CleanupContainer(t, postgres)
-	require.NoError(t, err)
+	}
 }
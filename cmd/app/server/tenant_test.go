@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"crud/cmd/app/config"
+	logConfig "crud/cmd/app/config/log"
+	"crud/internal/model"
+	"crud/internal/repository/db"
+	"crud/internal/tenant"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIntegrationTenantIsolation(t *testing.T) {
+	logger, logLevel := logConfig.CreateLogger()
+	appConfig := config.Config{DB: startPostgres(t), App: config.AppConfig{
+		LogLevel: "info",
+		AppMode:  "test",
+	}, Tenant: config.TenantConfig{Enabled: "true"}}
+
+	app, err := ConfigureAppEngine(&appConfig, logLevel, logger)
+	require.NoError(t, err)
+	server := httptest.NewServer(app.Engine.Handler())
+	defer app.Close()
+	defer server.Close()
+	client := server.Client()
+
+	send := func(method, path, tenantID, body string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if tenantID != "" {
+			req.Header.Set(config.DefaultTenantHeader, tenantID)
+		}
+		httpResponse, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = httpResponse.Body.Close() })
+		return httpResponse
+	}
+
+	userIDs := map[string]int{}
+	for _, tenantID := range []string{"acme", "globex"} {
+		body := fmt.Sprintf(`{"name":"%s admin","email":"admin@example.com","age":30}`, tenantID)
+		httpResponse := send(http.MethodPost, "/api/v1/user/", tenantID, body)
+		require.Equal(t, http.StatusCreated, httpResponse.StatusCode, "the same email is unique per tenant only")
+		var user model.UserResponse
+		require.NoError(t, json.NewDecoder(httpResponse.Body).Decode(&user))
+		userIDs[tenantID] = user.ID
+	}
+
+	for tenantID, userID := range userIDs {
+		httpResponse := send(http.MethodGet, "/api/v1/user/", tenantID, "")
+		require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+		var users []model.UserResponse
+		require.NoError(t, json.NewDecoder(httpResponse.Body).Decode(&users))
+		require.Len(t, users, 1, tenantID)
+		assert.Equal(t, userID, users[0].ID)
+	}
+
+	httpResponse := send(http.MethodGet, fmt.Sprintf("/api/v1/user/%d", userIDs["globex"]), "acme", "")
+	assert.Equal(t, http.StatusNotFound, httpResponse.StatusCode, "rows of other tenants are not visible")
+	httpResponse = send(http.MethodGet, "/api/v1/user/", "", "")
+	assert.Equal(t, http.StatusBadRequest, httpResponse.StatusCode, "requests without a tenant are rejected")
+
+	// The superuser of the container bypasses row level security, the policies are checked with a plain role
+	ctx := context.Background()
+	_, err = app.DBPool.Exec(ctx, "CREATE ROLE tenant_test NOSUPERUSER NOBYPASSRLS")
+	require.NoError(t, err)
+	_, err = app.DBPool.Exec(ctx, "GRANT SELECT ON users TO tenant_test")
+	require.NoError(t, err)
+	for tenantID, expected := range map[string]int{"acme": 1, "globex": 1, "": 0} {
+		tx, err := app.DBPool.Begin(ctx)
+		require.NoError(t, err)
+		_, err = tx.Exec(ctx, "SET LOCAL ROLE tenant_test")
+		require.NoError(t, err)
+		if tenantID != "" {
+			_, err = tx.Exec(ctx, "SELECT set_config('app.tenant_id', $1, true)", tenantID)
+			require.NoError(t, err)
+		}
+		var count int
+		require.NoError(t, tx.QueryRow(ctx, "SELECT count(*) FROM users").Scan(&count))
+		assert.Equal(t, expected, count, "users visible to tenant %q", tenantID)
+		require.NoError(t, tx.Rollback(ctx))
+	}
+}
+
+func TestIntegrationTenantSchemas(t *testing.T) {
+	logger, logLevel := logConfig.CreateLogger()
+	appConfig := config.Config{DB: startPostgres(t), App: config.AppConfig{
+		LogLevel: "info",
+		AppMode:  "test",
+	}, Tenant: config.TenantConfig{Enabled: "true", Mode: "schema", Default: tenant.DefaultTenant}}
+
+	app, err := ConfigureAppEngine(&appConfig, logLevel, logger)
+	require.NoError(t, err)
+	server := httptest.NewServer(app.Engine.Handler())
+	defer app.Close()
+	defer server.Close()
+	client := server.Client()
+
+	ctx := context.Background()
+	schemas := db.NewTenantSchemas(appConfig.DB.Schema, logger)
+	require.NoError(t, schemas.Provision(ctx, app.DBPool, "acme"))
+
+	send := func(method, path, tenantID, body string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if tenantID != "" {
+			req.Header.Set(config.DefaultTenantHeader, tenantID)
+		}
+		httpResponse, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = httpResponse.Body.Close() })
+		return httpResponse
+	}
+
+	httpResponse := send(http.MethodPost, "/api/v1/user/", "acme", `{"name":"acme admin","email":"admin@example.com","age":30}`)
+	require.Equal(t, http.StatusCreated, httpResponse.StatusCode)
+
+	for tenantID, expected := range map[string]int{"acme": 1, "": 3} {
+		httpResponse = send(http.MethodGet, "/api/v1/user/", tenantID, "")
+		require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+		var users []model.UserResponse
+		require.NoError(t, json.NewDecoder(httpResponse.Body).Decode(&users))
+		assert.Len(t, users, expected, "users of tenant %q", tenantID)
+	}
+	httpResponse = send(http.MethodGet, "/api/v1/user/", "globex", "")
+	assert.Equal(t, http.StatusNotFound, httpResponse.StatusCode, "tenants without a schema are rejected")
+
+	var count int
+	require.NoError(t, app.DBPool.QueryRow(ctx, "SELECT count(*) FROM tenant_acme.users WHERE tenant_id = 'acme'").Scan(&count))
+	assert.Equal(t, 1, count, "the user is stored in the schema of the tenant")
+	require.NoError(t, app.DBPool.QueryRow(ctx, "SELECT count(*) FROM tenant_acme.users").Scan(&count))
+	assert.Equal(t, 1, count, "the schemas of tenants are not seeded")
+	require.NoError(t, app.DBPool.QueryRow(ctx, "SELECT count(*) FROM public.users WHERE tenant_id = 'acme'").Scan(&count))
+	assert.Equal(t, 0, count)
+
+	tenantSchemas, err := schemas.List(ctx, app.DBPool)
+	require.NoError(t, err)
+	latest, err := db.LatestMigrationVersion()
+	require.NoError(t, err)
+	require.Len(t, tenantSchemas, 2)
+	assert.Equal(t, db.TenantSchema{Tenant: "acme", Schema: "tenant_acme", Version: latest}, tenantSchemas[1])
+
+	require.NoError(t, schemas.Drop(ctx, app.DBPool, "acme"))
+	assert.ErrorIs(t, schemas.Drop(ctx, app.DBPool, tenant.DefaultTenant), db.ErrBaseSchema)
+}
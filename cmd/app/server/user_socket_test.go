@@ -0,0 +1,73 @@
+package server
+
+import (
+	"crud/cmd/app/config"
+	logConfig "crud/cmd/app/config/log"
+	"crud/internal/model"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIntegrationUserSocket(t *testing.T) {
+	logger, logLevel := logConfig.CreateLogger()
+	appConfig := config.Config{DB: startPostgres(t), App: config.AppConfig{
+		LogLevel: "info",
+		AppMode:  "test",
+	}}
+
+	app, err := ConfigureAppEngine(&appConfig, logLevel, logger)
+	require.NoError(t, err)
+	server := httptest.NewServer(app.Engine.Handler())
+	defer app.Close()
+	defer server.Close()
+	client := server.Client()
+	waitForListener(t, app)
+
+	send := func(method, path, body string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		httpResponse, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = httpResponse.Body.Close() })
+		return httpResponse
+	}
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v1/ws", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	read := func() model.LiveMessage {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Second)))
+		var message model.LiveMessage
+		require.NoError(t, conn.ReadJSON(&message))
+		return message
+	}
+
+	require.NoError(t, conn.WriteJSON(model.LiveRequest{Type: model.LiveSubscribe, ID: "graces",
+		Query: &model.UserQuery{Filter: &model.UserFilter{Name: "grace"}}}))
+	snapshot := read()
+	assert.Equal(t, model.LiveSnapshot, snapshot.Type)
+	assert.Empty(t, snapshot.Users)
+
+	httpResponse := send(http.MethodPost, "/api/v1/user/", `{"name":"Grace","email":"grace@example.com","age":45}`)
+	require.Equal(t, http.StatusCreated, httpResponse.StatusCode)
+	var user model.UserResponse
+	require.NoError(t, json.NewDecoder(httpResponse.Body).Decode(&user))
+	added := read()
+	assert.Equal(t, model.LiveAdded, added.Type)
+	assert.Equal(t, &user, added.User)
+
+	httpResponse = send(http.MethodPut, fmt.Sprintf("/api/v1/user/%d", user.ID), `{"name":"Ada","email":"grace@example.com","age":45}`)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	removed := read()
+	assert.Equal(t, model.LiveRemoved, removed.Type, "the renamed user no longer matches the filter")
+	assert.Equal(t, "Ada", removed.User.Name)
+	assert.Greater(t, removed.EventID, added.EventID)
+}
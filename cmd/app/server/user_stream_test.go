@@ -0,0 +1,110 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"crud/cmd/app/config"
+	logConfig "crud/cmd/app/config/log"
+	"crud/internal/model"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIntegrationUserStream(t *testing.T) {
+	logger, logLevel := logConfig.CreateLogger()
+	appConfig := config.Config{DB: startPostgres(t), App: config.AppConfig{
+		LogLevel: "info",
+		AppMode:  "test",
+	}, Stream: config.StreamConfig{HeartbeatInterval: "100ms"}}
+
+	app, err := ConfigureAppEngine(&appConfig, logLevel, logger)
+	require.NoError(t, err)
+	server := httptest.NewServer(app.Engine.Handler())
+	defer app.Close()
+	defer server.Close()
+	client := server.Client()
+
+	waitForListener(t, app)
+
+	send := func(method, path, body string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		httpResponse, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = httpResponse.Body.Close() })
+		return httpResponse
+	}
+	open := func(lastEventID string) *bufio.Reader {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/user/stream", nil)
+		require.NoError(t, err)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		httpResponse, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = httpResponse.Body.Close() })
+		require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+		return bufio.NewReader(httpResponse.Body)
+	}
+	// next returns the id and the message of the next event, skipping the comments
+	next := func(reader *bufio.Reader) (string, model.EventMessage) {
+		var id string
+		var event model.EventMessage
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case strings.HasPrefix(line, "id:"):
+				id = strings.TrimPrefix(line, "id:")
+			case strings.HasPrefix(line, "data:"):
+				require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &event))
+			case line == "" && id != "":
+				return id, event
+			}
+		}
+	}
+
+	live := open("")
+	httpResponse := send(http.MethodPost, "/api/v1/user/", `{"name":"Ada","email":"ada@example.com","age":36}`)
+	require.Equal(t, http.StatusCreated, httpResponse.StatusCode)
+	var user model.UserResponse
+	require.NoError(t, json.NewDecoder(httpResponse.Body).Decode(&user))
+	createdID, created := next(live)
+	assert.Equal(t, model.UserCreatedEvent, created.Type)
+	assert.Equal(t, fmt.Sprint(user.ID), created.AggregateID)
+	assert.JSONEq(t, fmt.Sprintf(`{"id":%d,"name":"Ada","email":"ada@example.com","age":36}`, user.ID), string(created.Payload))
+
+	httpResponse = send(http.MethodPut, fmt.Sprintf("/api/v1/user/%d", user.ID), `{"name":"Ada L.","email":"ada@example.com","age":36}`)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	httpResponse = send(http.MethodDelete, fmt.Sprintf("/api/v1/user/%d", user.ID), "")
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	_, updated := next(live)
+	assert.Equal(t, model.UserUpdatedEvent, updated.Type)
+
+	resumed := open(createdID)
+	for _, eventType := range []string{model.UserUpdatedEvent, model.UserDeletedEvent} {
+		_, event := next(resumed)
+		assert.Equal(t, eventType, event.Type, "the stream resumes after the last event id")
+		assert.Equal(t, fmt.Sprint(user.ID), event.AggregateID)
+	}
+}
+
+// waitForListener waits until the app listens for the user changes, the notifications sent before are only in the log
+func waitForListener(t *testing.T, app *App) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		var listening bool
+		err := app.DBPool.QueryRow(context.Background(),
+			"SELECT count(*) > 0 FROM pg_stat_activity WHERE query LIKE 'LISTEN%'").Scan(&listening)
+		return err == nil && listening
+	}, 10*time.Second, 50*time.Millisecond)
+}
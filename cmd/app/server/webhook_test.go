@@ -0,0 +1,117 @@
+package server
+
+import (
+	"crud/cmd/app/config"
+	logConfig "crud/cmd/app/config/log"
+	"crud/internal/model"
+	"crud/internal/webhook"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIntegrationWebhooks(t *testing.T) {
+	const secret = "0123456789abcdef"
+	var mutex sync.Mutex
+	failing := true
+	received := make([]model.EventMessage, 0)
+	receiver := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		if err := webhook.Verify(req.Header, []byte(secret), body, time.Now(), webhook.DefaultTolerance); err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		if failing {
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var event model.EventMessage
+		_ = json.Unmarshal(body, &event)
+		received = append(received, event)
+	}))
+	defer receiver.Close()
+
+	logger, logLevel := logConfig.CreateLogger()
+	appConfig := config.Config{DB: startPostgres(t), App: config.AppConfig{
+		LogLevel: "info",
+		AppMode:  "test",
+	}, Outbox: config.OutboxConfig{PollInterval: "50ms"},
+		Webhook: config.WebhookConfig{Enabled: "true", MaxAttempts: "1", PollInterval: "50ms", AllowedNetworks: "127.0.0.1"}}
+
+	app, err := ConfigureAppEngine(&appConfig, logLevel, logger)
+	require.NoError(t, err)
+	server := httptest.NewServer(app.Engine.Handler())
+	defer app.Close()
+	defer server.Close()
+	client := server.Client()
+
+	send := func(method, path, body string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		httpResponse, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = httpResponse.Body.Close() })
+		return httpResponse
+	}
+	deliveries := func(subscriptionID int) []model.WebhookDeliveryResponse {
+		httpResponse := send(http.MethodGet, fmt.Sprintf("/api/v1/webhook/%d/deliveries", subscriptionID), "")
+		require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+		var deliveries []model.WebhookDeliveryResponse
+		require.NoError(t, json.NewDecoder(httpResponse.Body).Decode(&deliveries))
+		return deliveries
+	}
+
+	httpResponse := send(http.MethodPost, "/api/v1/webhook/",
+		fmt.Sprintf(`{"url":"%s","event_types":["user.created"],"secret":"%s"}`, receiver.URL, secret))
+	require.Equal(t, http.StatusCreated, httpResponse.StatusCode)
+	var subscription model.WebhookSubscriptionResponse
+	require.NoError(t, json.NewDecoder(httpResponse.Body).Decode(&subscription))
+
+	httpResponse = send(http.MethodPost, "/api/v1/user/", `{"name":"Ada","email":"ada@example.com","age":36}`)
+	require.Equal(t, http.StatusCreated, httpResponse.StatusCode)
+	var user model.UserResponse
+	require.NoError(t, json.NewDecoder(httpResponse.Body).Decode(&user))
+	httpResponse = send(http.MethodDelete, fmt.Sprintf("/api/v1/user/%d", user.ID), "")
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	var delivery model.WebhookDeliveryResponse
+	require.Eventually(t, func() bool {
+		logged := deliveries(subscription.ID)
+		if len(logged) != 1 || logged[0].Status != model.WebhookDeliveryDead {
+			return false
+		}
+		delivery = logged[0]
+		return true
+	}, 10*time.Second, 50*time.Millisecond, "the delivery is dead after its only attempt failed")
+	assert.Equal(t, model.UserCreatedEvent, delivery.EventType)
+	require.NotNil(t, delivery.LastStatusCode)
+	assert.Equal(t, http.StatusInternalServerError, *delivery.LastStatusCode)
+	assert.Equal(t, 1, delivery.Attempts)
+
+	mutex.Lock()
+	failing = false
+	mutex.Unlock()
+	httpResponse = send(http.MethodPost, fmt.Sprintf("/api/v1/webhook/%d/deliveries/%d/replay", subscription.ID, delivery.ID), "")
+	require.Equal(t, http.StatusAccepted, httpResponse.StatusCode)
+	require.Eventually(t, func() bool {
+		logged := deliveries(subscription.ID)
+		return len(logged) == 1 && logged[0].Status == model.WebhookDeliverySucceeded
+	}, 10*time.Second, 50*time.Millisecond, "the replayed delivery succeeds")
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Len(t, received, 1, "user.deleted is not subscribed to")
+	assert.Equal(t, delivery.EventID, received[0].ID)
+	assert.Equal(t, fmt.Sprint(user.ID), received[0].AggregateID)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crud/cmd/gen/resource"
+	"flag"
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: go run ./cmd/gen <command> [flags]
+
+Commands:
+  resource  scaffold a CRUD resource, run "go run ./cmd/gen resource -h" for the flags
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "resource":
+		if err := runResource(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func runResource(args []string) error {
+	flags := flag.NewFlagSet("resource", flag.ExitOnError)
+	name := flags.String("name", "", "snake case singular resource name, e.g. order_item")
+	fields := flags.String("fields", "", "comma separated <name>:<type>[:required][:immutable] list, e.g. title:string:required,quantity:int")
+	table := flags.String("table", "", "table name, defaults to the resource name with an \"s\" appended")
+	specPath := flags.String("spec", "", "YAML spec of the resource, used instead of -name, -fields and -table")
+	root := flags.String("root", ".", "root of the repository, the directory of go.mod")
+	dropColumns := flags.Bool("drop-columns", false, "drop the columns of removed or renamed fields, with their data")
+	_ = flags.Parse(args)
+
+	spec := &resource.Spec{Name: *name, Table: *table}
+	if *specPath != "" {
+		loaded, err := resource.LoadSpec(*specPath)
+		if err != nil {
+			return err
+		}
+		spec = loaded
+	} else {
+		parsed, err := resource.ParseFields(*fields)
+		if err != nil {
+			return err
+		}
+		spec.Fields = parsed
+	}
+
+	changes, err := resource.Generate(*root, spec, resource.Options{DropColumns: *dropColumns})
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		fmt.Printf("%-9s %s\n", change.Action, change.Path)
+	}
+	return nil
+}
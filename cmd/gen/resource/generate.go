@@ -0,0 +1,492 @@
+package resource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFs embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"imports": imports,
+	"when":    when,
+}).ParseFS(templateFs, "templates/*.tmpl"))
+
+const (
+	migrationsDir = "internal/repository/db/migrations"
+	setupFile     = "internal/setup.go"
+	policyFile    = "internal/auth/policy.go"
+	mockeryFile   = ".mockery.yml"
+	// RouterMarker is the line of setupV1Router the resources are registered above
+	RouterMarker = "// gen:resources"
+	// AdminPermissionsMarker and ViewerPermissionsMarker are the lines of auth.DefaultRolePermissions the
+	// permissions of the resources are granted above
+	AdminPermissionsMarker  = "// gen:admin-permissions"
+	ViewerPermissionsMarker = "// gen:viewer-permissions"
+)
+
+// Action is what Generate did to a file
+type Action string
+
+const (
+	Created   Action = "created"
+	Updated   Action = "updated"
+	Unchanged Action = "unchanged"
+)
+
+// Change is a file Generate wrote or left as it is
+type Change struct {
+	Path   string
+	Action Action
+}
+
+// ErrEditedFiles is returned when generating would overwrite files changed since they were scaffolded
+var ErrEditedFiles = errors.New("refusing to overwrite edited files")
+
+// ErrDroppedColumns is returned when fields were removed or renamed and the columns would be dropped with their data
+var ErrDroppedColumns = errors.New("refusing to drop columns")
+
+// Options changes what Generate may do
+type Options struct {
+	// DropColumns lets the alter migration drop the columns of the removed or renamed fields
+	DropColumns bool
+}
+
+var headerPattern = regexp.MustCompile(`^(//|--) Scaffolded by gen resource, checksum: ([0-9a-f]{16})\n`)
+
+// The column statements of the scaffolded migrations, the columns of a table are replayed from them
+var (
+	createColumnPattern = regexp.MustCompile(`^\s+(\w+) (.+?) not null,?$`)
+	addColumnPattern    = regexp.MustCompile(`ADD COLUMN IF NOT EXISTS (\w+) (.+?) not null`)
+	alterColumnPattern  = regexp.MustCompile(`ALTER COLUMN (\w+) TYPE (.+?) USING`)
+	dropColumnPattern   = regexp.MustCompile(`DROP COLUMN IF EXISTS (\w+);`)
+)
+
+type templateField struct {
+	GoName    string
+	GoType    string
+	Column    string
+	SQLType   string
+	Sample    string
+	SQLZero   string
+	ZeroCheck string
+	Required  bool
+	Immutable bool
+}
+
+type templateData struct {
+	Module string
+	// Name is the snake case name and the route path
+	Name  string
+	Type  string
+	Var   string
+	Table string
+	Title string
+	// Article is "a" or "an" for the title
+	Article string
+	TitleID string
+	// TitleCap is the title starting with a capital letter
+	TitleCap          string
+	Fields            []templateField
+	HasTime           bool
+	HasRequired       bool
+	HasRequiredString bool
+	// Added, Changed and Dropped are the column changes of an alter migration
+	Added   []templateField
+	Changed []columnChange
+	Dropped []templateField
+}
+
+// columnChange is a column whose type changed
+type columnChange struct {
+	Column string
+	From   string
+	To     string
+}
+
+type plannedFile struct {
+	path    string
+	content []byte
+}
+
+// Generate scaffolds the resource in the repository at root: the model, the migration pair, the repository,
+// service and controller with their unit tests, the router registration, the permissions of the default roles
+// and the mockery entries. Running it again with the same spec changes nothing. Files which still match their
+// checksum are regenerated, when any scaffolded file was edited nothing is written and ErrEditedFiles is returned.
+// Migrations may have run already and are never regenerated, a new migration pair alters the changed columns.
+// Columns are only dropped with Options.DropColumns, else ErrDroppedColumns is returned
+func Generate(root string, spec *Spec, options Options) ([]Change, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	module, err := readModule(root)
+	if err != nil {
+		return nil, err
+	}
+	data := newTemplateData(module, spec)
+	migrations, err := planMigrations(root, data, options)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]plannedFile, 0)
+	for _, item := range []struct{ template, path string }{
+		{"model.go.tmpl", "internal/model/" + spec.Name + ".go"},
+		{"repository.go.tmpl", "internal/repository/" + spec.Name + ".go"},
+		{"service.go.tmpl", "internal/service/" + spec.Name + ".go"},
+		{"service_test.go.tmpl", "internal/service/" + spec.Name + "_test.go"},
+		{"controller.go.tmpl", "internal/controller/" + spec.Name + ".go"},
+		{"controller_test.go.tmpl", "internal/controller/" + spec.Name + "_test.go"},
+	} {
+		content, err := render(item.template, data)
+		if err != nil {
+			return nil, fmt.Errorf("rendering %s: %w", item.path, err)
+		}
+		files = append(files, plannedFile{path: item.path, content: content})
+	}
+	files = append(files, migrations...)
+
+	changes := make([]Change, 0, len(files)+3)
+	edited := make([]string, 0)
+	for _, file := range files {
+		action, err := plan(filepath.Join(root, file.path), file.content)
+		if err != nil {
+			return nil, err
+		}
+		if action == "" {
+			edited = append(edited, file.path)
+		}
+		changes = append(changes, Change{Path: file.path, Action: action})
+	}
+	if len(edited) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEditedFiles, strings.Join(edited, ", "))
+	}
+	routerChange, routerContent, err := planRouter(root, data)
+	if err != nil {
+		return nil, err
+	}
+	policyChange, policyContent, err := planPermissions(root, data)
+	if err != nil {
+		return nil, err
+	}
+	mockeryChange, mockeryContent, err := planMockery(root, data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, file := range files {
+		if changes[i].Action == Unchanged {
+			continue
+		}
+		path := filepath.Join(root, file.path)
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		if err = os.WriteFile(path, file.content, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	if routerChange.Action != Unchanged {
+		if err = os.WriteFile(filepath.Join(root, setupFile), routerContent, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	if policyChange.Action != Unchanged {
+		if err = os.WriteFile(filepath.Join(root, policyFile), policyContent, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	if mockeryChange.Action != Unchanged {
+		if err = os.WriteFile(filepath.Join(root, mockeryFile), mockeryContent, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return append(changes, routerChange, policyChange, mockeryChange), nil
+}
+
+func newTemplateData(module string, spec *Spec) templateData {
+	title := strings.ReplaceAll(spec.Name, "_", " ")
+	data := templateData{
+		Module:   module,
+		Name:     spec.Name,
+		Type:     upperCamel(spec.Name),
+		Var:      lowerCamel(spec.Name),
+		Table:    spec.Table,
+		Title:    title,
+		Article:  "a",
+		TitleID:  strings.ToUpper(title[:1]) + title[1:] + " ID",
+		TitleCap: strings.ToUpper(title[:1]) + title[1:],
+	}
+	if strings.ContainsRune("aeiou", rune(title[0])) {
+		data.Article = "an"
+	}
+	for _, field := range spec.Fields {
+		fieldType := fieldTypes[field.Type]
+		goName := upperCamel(field.Name)
+		data.Fields = append(data.Fields, templateField{
+			GoName:    goName,
+			GoType:    fieldType.goType,
+			Column:    field.Name,
+			SQLType:   fieldType.sqlType,
+			Sample:    fieldType.sample,
+			SQLZero:   fieldType.sqlZero,
+			ZeroCheck: fmt.Sprintf(fieldType.zeroCheck, goName),
+			Required:  field.Required,
+			Immutable: field.Immutable,
+		})
+		data.HasTime = data.HasTime || field.Type == "time"
+		data.HasRequired = data.HasRequired || field.Required
+		data.HasRequiredString = data.HasRequiredString || (field.Required && field.Type == "string")
+	}
+	return data
+}
+
+// render executes the template and prepends the checksum header, Go sources are formatted
+func render(name string, data templateData) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := templates.ExecuteTemplate(&buffer, name, data); err != nil {
+		return nil, err
+	}
+	body := buffer.Bytes()
+	comment := "--"
+	if strings.HasSuffix(name, ".go.tmpl") {
+		formatted, err := format.Source(body)
+		if err != nil {
+			return nil, err
+		}
+		// The blank line keeps the header from becoming the package comment
+		body, comment = append([]byte("\n"), formatted...), "//"
+	}
+	return append([]byte(fmt.Sprintf("%s Scaffolded by gen resource, checksum: %s\n", comment, checksum(body))), body...), nil
+}
+
+func checksum(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:8])
+}
+
+// plan returns what writing content to path does, or an empty action when the file was edited since it was scaffolded
+func plan(path string, content []byte) (Action, error) {
+	existing, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Created, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(existing, content) {
+		return Unchanged, nil
+	}
+	header := headerPattern.FindSubmatch(existing)
+	if header != nil && string(header[2]) == checksum(existing[len(header[0]):]) {
+		return Updated, nil
+	}
+	return "", nil
+}
+
+// planRouter inserts the registration of the resource above the RouterMarker line of the setup file
+func planRouter(root string, data templateData) (Change, []byte, error) {
+	change := Change{Path: setupFile, Action: Unchanged}
+	content, err := os.ReadFile(filepath.Join(root, setupFile))
+	if err != nil {
+		return change, nil, err
+	}
+	if bytes.Contains(content, []byte("controller.New"+data.Type+"Controller(")) {
+		return change, content, nil
+	}
+	markerAt := bytes.Index(content, []byte(RouterMarker))
+	if markerAt < 0 {
+		return change, nil, fmt.Errorf("%s has no %q line to register the resource above", setupFile, RouterMarker)
+	}
+	lineStart := bytes.LastIndexByte(content[:markerAt], '\n') + 1
+	var registration bytes.Buffer
+	if err = templates.ExecuteTemplate(&registration, "router.go.tmpl", data); err != nil {
+		return change, nil, err
+	}
+	updated := slices.Concat(content[:lineStart], registration.Bytes(), content[lineStart:])
+	if updated, err = format.Source(updated); err != nil {
+		return change, nil, err
+	}
+	change.Action = Updated
+	return change, updated, nil
+}
+
+// planPermissions grants the read and write permissions of the resource to the admin role and the read
+// permission to the viewer role of auth.DefaultRolePermissions, above their marker lines
+func planPermissions(root string, data templateData) (Change, []byte, error) {
+	change := Change{Path: policyFile, Action: Unchanged}
+	content, err := os.ReadFile(filepath.Join(root, policyFile))
+	if err != nil {
+		return change, nil, err
+	}
+	read, write := strconv.Quote(data.Table+":read"), strconv.Quote(data.Table+":write")
+	if bytes.Contains(content, []byte(write)) {
+		return change, content, nil
+	}
+	for _, grant := range []struct{ marker, permissions string }{
+		{AdminPermissionsMarker, read + ", " + write + ",\n"},
+		{ViewerPermissionsMarker, read + ",\n"},
+	} {
+		markerAt := bytes.Index(content, []byte(grant.marker))
+		if markerAt < 0 {
+			return change, nil, fmt.Errorf("%s has no %q line to grant the permissions above", policyFile, grant.marker)
+		}
+		lineStart := bytes.LastIndexByte(content[:markerAt], '\n') + 1
+		content = slices.Concat(content[:lineStart], []byte(grant.permissions), content[lineStart:])
+	}
+	if content, err = format.Source(content); err != nil {
+		return change, nil, err
+	}
+	change.Action = Updated
+	return change, content, nil
+}
+
+func readModule(root string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if module, found := strings.CutPrefix(strings.TrimSpace(line), "module "); found {
+			return strings.Trim(strings.TrimSpace(module), `"`), nil
+		}
+	}
+	return "", errors.New("go.mod has no module line")
+}
+
+// planMigrations returns the migration pair creating the table when it was not scaffolded before. Otherwise the
+// columns of the table are replayed from its scaffolded migrations, and a pair altering them to the fields is
+// returned, or none when they match
+func planMigrations(root string, data templateData, options Options) ([]plannedFile, error) {
+	entries, err := os.ReadDir(filepath.Join(root, migrationsDir))
+	if err != nil {
+		return nil, err
+	}
+	createSuffix := "_create_" + data.Table + "_table.up.sql"
+	alterSuffix := "_alter_" + data.Table + "_table.up.sql"
+	created := false
+	columns := make([]templateField, 0)
+	last := 0
+	for _, entry := range entries {
+		prefix, _, found := strings.Cut(entry.Name(), "_")
+		if number, err := strconv.Atoi(prefix); found && err == nil {
+			last = max(last, number)
+		}
+		if !strings.HasSuffix(entry.Name(), createSuffix) && !strings.HasSuffix(entry.Name(), alterSuffix) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(root, migrationsDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		created = true
+		columns = replayColumns(columns, string(content))
+	}
+
+	name := fmt.Sprintf("%06d_create_%s_table", last+1, data.Table)
+	templateName := "migration"
+	if created {
+		if data = diffColumns(data, columns); len(data.Added)+len(data.Changed)+len(data.Dropped) == 0 {
+			return nil, nil
+		}
+		if len(data.Dropped) > 0 && !options.DropColumns {
+			dropped := make([]string, len(data.Dropped))
+			for i, column := range data.Dropped {
+				dropped[i] = column.Column
+			}
+			return nil, fmt.Errorf("%w %s of %s, they have no field anymore, pass -drop-columns to drop them",
+				ErrDroppedColumns, strings.Join(dropped, ", "), data.Table)
+		}
+		name = fmt.Sprintf("%06d_alter_%s_table", last+1, data.Table)
+		templateName = "migration_alter"
+	}
+	files := make([]plannedFile, 0, 2)
+	for _, direction := range []string{"up", "down"} {
+		path := migrationsDir + "/" + name + "." + direction + ".sql"
+		content, err := render(templateName+"."+direction+".sql.tmpl", data)
+		if err != nil {
+			return nil, fmt.Errorf("rendering %s: %w", path, err)
+		}
+		files = append(files, plannedFile{path: path, content: content})
+	}
+	return files, nil
+}
+
+// replayColumns applies the column statements of a scaffolded migration to the columns, the id and tenant_id
+// columns are left out
+func replayColumns(columns []templateField, migration string) []templateField {
+	for _, line := range strings.Split(migration, "\n") {
+		if match := createColumnPattern.FindStringSubmatch(line); match != nil {
+			if match[1] != "id" && match[1] != "tenant_id" {
+				columns = append(columns, templateField{Column: match[1], SQLType: match[2]})
+			}
+		} else if match = addColumnPattern.FindStringSubmatch(line); match != nil {
+			columns = append(columns, templateField{Column: match[1], SQLType: match[2]})
+		} else if match = alterColumnPattern.FindStringSubmatch(line); match != nil {
+			for i := range columns {
+				if columns[i].Column == match[1] {
+					columns[i].SQLType = match[2]
+				}
+			}
+		} else if match = dropColumnPattern.FindStringSubmatch(line); match != nil {
+			columns = slices.DeleteFunc(columns, func(column templateField) bool { return column.Column == match[1] })
+		}
+	}
+	return columns
+}
+
+// diffColumns sets the column changes turning the columns of the table into the fields of data
+func diffColumns(data templateData, columns []templateField) templateData {
+	for _, field := range data.Fields {
+		at := slices.IndexFunc(columns, func(column templateField) bool { return column.Column == field.Column })
+		if at < 0 {
+			data.Added = append(data.Added, field)
+		} else if columns[at].SQLType != field.SQLType {
+			data.Changed = append(data.Changed, columnChange{Column: field.Column, From: columns[at].SQLType, To: field.SQLType})
+		}
+	}
+	for _, column := range columns {
+		if !slices.ContainsFunc(data.Fields, func(field templateField) bool { return field.Column == column.Column }) {
+			column.SQLZero = sqlZero(column.SQLType)
+			data.Dropped = append(data.Dropped, column)
+		}
+	}
+	return data
+}
+
+// imports formats an import block of the non-empty paths in the order of the repository, a single sorted group
+func imports(paths ...string) string {
+	paths = slices.DeleteFunc(slices.Clone(paths), func(path string) bool { return path == "" })
+	slices.Sort(paths)
+	if len(paths) == 1 {
+		return "import " + strconv.Quote(paths[0])
+	}
+	var builder strings.Builder
+	builder.WriteString("import (\n")
+	for _, path := range paths {
+		builder.WriteString("\t" + strconv.Quote(path) + "\n")
+	}
+	builder.WriteString(")")
+	return builder.String()
+}
+
+// when returns value when condition is set, for optional imports
+func when(condition bool, value string) string {
+	if condition {
+		return value
+	}
+	return ""
+}
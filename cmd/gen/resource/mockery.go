@@ -0,0 +1,80 @@
+package resource
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+)
+
+// planMockery adds the repository and service interfaces of the resource to the mockery configuration
+func planMockery(root string, data templateData) (Change, []byte, error) {
+	change := Change{Path: mockeryFile, Action: Unchanged}
+	content, err := os.ReadFile(filepath.Join(root, mockeryFile))
+	if err != nil {
+		return change, nil, err
+	}
+	document := &yaml.Node{}
+	if err = yaml.Unmarshal(content, document); err != nil {
+		return change, nil, fmt.Errorf("invalid %s: %w", mockeryFile, err)
+	}
+	if len(document.Content) == 0 {
+		return change, nil, fmt.Errorf("%s is empty", mockeryFile)
+	}
+	packages := mappingValue(document.Content[0], "packages")
+	if packages == nil {
+		return change, nil, errors.New(mockeryFile + " has no packages")
+	}
+	added := false
+	for _, entry := range []struct{ packagePath, iface string }{
+		{data.Module + "/internal/repository", "I" + data.Type + "Repository"},
+		{data.Module + "/internal/service", "I" + data.Type + "Service"},
+	} {
+		interfaces := ensureMapping(ensureMapping(packages, entry.packagePath), "interfaces")
+		if mappingValue(interfaces, entry.iface) == nil {
+			ensureMapping(interfaces, entry.iface)
+			added = true
+		}
+	}
+	if !added {
+		return change, content, nil
+	}
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(document); err != nil {
+		return change, nil, err
+	}
+	change.Action = Updated
+	return change, buffer.Bytes(), nil
+}
+
+// mappingValue returns the value of key in the mapping node, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// ensureMapping returns the mapping value of key, adding an empty mapping when the key is missing or null
+func ensureMapping(mapping *yaml.Node, key string) *yaml.Node {
+	value := mappingValue(mapping, key)
+	if value != nil && value.Kind == yaml.MappingNode {
+		return value
+	}
+	empty := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if value != nil {
+		*value = *empty
+		return value
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, empty)
+	return empty
+}
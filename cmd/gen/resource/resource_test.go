@@ -0,0 +1,247 @@
+package resource
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSetup = `package internal
+
+func setupV1Router() {
+	// gen:resources
+}
+`
+
+const testMockery = `packages:
+  example/internal/repository:
+    config:
+      all: true
+`
+
+func newTestRoot(t *testing.T) string {
+	root := t.TempDir()
+	// The policy of the repository, so that its marker lines are checked too
+	policy, err := os.ReadFile(filepath.Join("..", "..", "..", policyFile))
+	require.NoError(t, err)
+	files := map[string]string{
+		policyFile:                            string(policy),
+		"go.mod":                              "module example\n\ngo 1.24\n",
+		setupFile:                             testSetup,
+		mockeryFile:                           testMockery,
+		migrationsDir + "/000001_a.up.sql":    "",
+		migrationsDir + "/000007_b.down.sql":  "",
+		migrationsDir + "/not_a_migration.md": "",
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte(content), 0o644))
+	}
+	return root
+}
+
+func newTestSpec() *Spec {
+	return &Spec{Name: "order_item", Fields: []Field{
+		{Name: "title", Type: "string", Required: true},
+		{Name: "due_at", Type: "time", Immutable: true},
+	}}
+}
+
+func TestUnitGenerate(t *testing.T) {
+	t.Parallel()
+	root := newTestRoot(t)
+
+	changes, err := Generate(root, newTestSpec(), Options{})
+	require.NoError(t, err)
+	require.Len(t, changes, 11)
+	for _, change := range changes[:8] {
+		assert.Equal(t, Created, change.Action, change.Path)
+	}
+	assert.Equal(t, migrationsDir+"/000008_create_order_items_table.up.sql", changes[6].Path)
+	assert.Equal(t, Change{Path: setupFile, Action: Updated}, changes[8])
+	assert.Equal(t, Change{Path: policyFile, Action: Updated}, changes[9])
+	assert.Equal(t, Change{Path: mockeryFile, Action: Updated}, changes[10])
+
+	setup, err := os.ReadFile(filepath.Join(root, setupFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(setup), "orderItemController := controller.NewOrderItemController(")
+	mockery, err := os.ReadFile(filepath.Join(root, mockeryFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(mockery), "IOrderItemRepository: {}")
+	assert.Contains(t, string(mockery), "example/internal/service:")
+
+	changes, err = Generate(root, newTestSpec(), Options{})
+	require.NoError(t, err)
+	for _, change := range changes {
+		assert.Equal(t, Unchanged, change.Action, change.Path)
+	}
+}
+
+func TestUnitGenerateGrantsAndScopes(t *testing.T) {
+	t.Parallel()
+	root := newTestRoot(t)
+	_, err := Generate(root, newTestSpec(), Options{})
+	require.NoError(t, err)
+
+	policy, err := os.ReadFile(filepath.Join(root, policyFile))
+	require.NoError(t, err)
+	roles := string(policy)[strings.Index(string(policy), "var DefaultRolePermissions"):]
+	admin := roles[strings.Index(roles, `"admin"`):strings.Index(roles, AdminPermissionsMarker)]
+	assert.Contains(t, admin, `"order_items:read", "order_items:write",`, "the admin role reaches the resource")
+	viewer := roles[strings.Index(roles, `"viewer"`):strings.Index(roles, ViewerPermissionsMarker)]
+	assert.Contains(t, viewer, `"order_items:read",`)
+	assert.NotContains(t, viewer, `"order_items:write"`)
+	service, err := os.ReadFile(filepath.Join(root, "internal/service/order_item.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(service), `Read: "order_items:read", Write: "order_items:write"`)
+
+	repository, err := os.ReadFile(filepath.Join(root, "internal/repository/order_item.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(repository), "TenantScoped: true")
+	migration, err := os.ReadFile(filepath.Join(root, migrationsDir, "000008_create_order_items_table.up.sql"))
+	require.NoError(t, err)
+	assert.Contains(t, string(migration), "tenant_id VARCHAR(63) not null,")
+	assert.Contains(t, string(migration), "ALTER TABLE order_items FORCE ROW LEVEL SECURITY;")
+	assert.Contains(t, string(migration), "CREATE POLICY order_items_tenant_isolation ON order_items")
+}
+
+func TestUnitGenerateAltersMigratedTable(t *testing.T) {
+	t.Parallel()
+	root := newTestRoot(t)
+	_, err := Generate(root, newTestSpec(), Options{})
+	require.NoError(t, err)
+	createPath := filepath.Join(root, migrationsDir, "000008_create_order_items_table.up.sql")
+	created, err := os.ReadFile(createPath)
+	require.NoError(t, err)
+
+	spec := newTestSpec()
+	spec.Fields = []Field{{Name: "title", Type: "int"}, {Name: "quantity", Type: "int"}}
+	_, err = Generate(root, spec, Options{})
+	assert.ErrorIs(t, err, ErrDroppedColumns)
+	assert.ErrorContains(t, err, "due_at of order_items")
+	_, err = os.Stat(filepath.Join(root, migrationsDir, "000009_alter_order_items_table.up.sql"))
+	assert.ErrorIs(t, err, os.ErrNotExist, "nothing is written while the drop is not allowed")
+
+	changes, err := Generate(root, spec, Options{DropColumns: true})
+	require.NoError(t, err)
+	assert.Equal(t, Change{Path: migrationsDir + "/000009_alter_order_items_table.up.sql", Action: Created}, changes[6])
+	unchanged, err := os.ReadFile(createPath)
+	require.NoError(t, err)
+	assert.Equal(t, created, unchanged, "migrations which may have run are not regenerated")
+
+	up, err := os.ReadFile(filepath.Join(root, migrationsDir, "000009_alter_order_items_table.up.sql"))
+	require.NoError(t, err)
+	assert.Contains(t, string(up), "ALTER TABLE order_items ADD COLUMN IF NOT EXISTS quantity INT not null default 0;\n")
+	assert.Contains(t, string(up), "ALTER TABLE order_items ALTER COLUMN title TYPE INT USING title::INT;\n")
+	assert.Contains(t, string(up), "ALTER TABLE order_items DROP COLUMN IF EXISTS due_at;\n")
+	down, err := os.ReadFile(filepath.Join(root, migrationsDir, "000009_alter_order_items_table.down.sql"))
+	require.NoError(t, err)
+	assert.Contains(t, string(down), "ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ not null default 'epoch';\n")
+	assert.Contains(t, string(down), "ALTER COLUMN title TYPE VARCHAR(255) USING title::VARCHAR(255);\n")
+	assert.Contains(t, string(down), "DROP COLUMN IF EXISTS quantity;\n")
+
+	changes, err = Generate(root, spec, Options{})
+	require.NoError(t, err)
+	assert.Len(t, changes, 9, "no migration while the columns match the fields")
+	for _, change := range changes {
+		assert.Equal(t, Unchanged, change.Action, change.Path)
+	}
+}
+
+func TestUnitGenerateEditedFiles(t *testing.T) {
+	t.Parallel()
+	root := newTestRoot(t)
+	_, err := Generate(root, newTestSpec(), Options{})
+	require.NoError(t, err)
+
+	spec := newTestSpec()
+	spec.Fields = append(spec.Fields, Field{Name: "quantity", Type: "int"})
+	changes, err := Generate(root, spec, Options{})
+	require.NoError(t, err, "untouched scaffolded files are regenerated")
+	assert.Equal(t, Change{Path: "internal/model/order_item.go", Action: Updated}, changes[0])
+
+	modelPath := filepath.Join(root, "internal/model/order_item.go")
+	model, err := os.ReadFile(modelPath)
+	require.NoError(t, err)
+	edited := strings.Replace(string(model), "package model", "package model\n\n// OrderItem is edited", 1)
+	require.NoError(t, os.WriteFile(modelPath, []byte(edited), 0o644))
+	repository, err := os.ReadFile(filepath.Join(root, "internal/repository/order_item.go"))
+	require.NoError(t, err)
+
+	_, err = Generate(root, newTestSpec(), Options{DropColumns: true})
+	assert.ErrorIs(t, err, ErrEditedFiles)
+	assert.ErrorContains(t, err, "internal/model/order_item.go")
+	unchanged, err := os.ReadFile(filepath.Join(root, "internal/repository/order_item.go"))
+	require.NoError(t, err)
+	assert.Equal(t, repository, unchanged, "nothing is written when a file was edited")
+}
+
+func TestUnitParseFields(t *testing.T) {
+	t.Parallel()
+
+	fields, err := ParseFields("title:string:required, quantity:int,sku:string:immutable:required,")
+	require.NoError(t, err)
+	assert.Equal(t, []Field{
+		{Name: "title", Type: "string", Required: true},
+		{Name: "quantity", Type: "int"},
+		{Name: "sku", Type: "string", Required: true, Immutable: true},
+	}, fields)
+
+	_, err = ParseFields("title")
+	assert.ErrorContains(t, err, "expected <name>:<type>")
+	_, err = ParseFields("title:string:unique")
+	assert.ErrorContains(t, err, "invalid option \"unique\"")
+}
+
+func TestUnitValidate(t *testing.T) {
+	t.Parallel()
+
+	spec := newTestSpec()
+	require.NoError(t, spec.Validate())
+	assert.Equal(t, "order_items", spec.Table)
+
+	tests := []struct {
+		name         string
+		spec         Spec
+		errorMessage string
+	}{
+		{"Camel case name", Spec{Name: "OrderItem", Fields: newTestSpec().Fields},
+			"invalid resource name \"OrderItem\", use snake case, e.g. order_item"},
+		{"Reserved name", Spec{Name: "service", Fields: newTestSpec().Fields},
+			"the resource name \"service\" is reserved, choose another one"},
+		{"Keyword name", Spec{Name: "type", Fields: newTestSpec().Fields},
+			"the resource name \"type\" is reserved, choose another one"},
+		{"No fields", Spec{Name: "order_item"},
+			"a resource needs at least one field"},
+		{"Id field", Spec{Name: "order_item", Fields: []Field{{Name: "id", Type: "int"}}},
+			"the field name id is reserved, the id and tenant_id columns are always added"},
+		{"Tenant field", Spec{Name: "order_item", Fields: []Field{{Name: "tenant_id", Type: "string"}}},
+			"the field name tenant_id is reserved, the id and tenant_id columns are always added"},
+		{"Duplicate field", Spec{Name: "order_item", Fields: []Field{{Name: "title", Type: "string"}, {Name: "title", Type: "int"}}},
+			"duplicate field title"},
+		{"SQL keyword field", Spec{Name: "order_item", Fields: []Field{{Name: "order", Type: "int"}}},
+			"the field name \"order\" is an SQL keyword, choose another one"},
+		{"SQL keyword user field", Spec{Name: "order_item", Fields: []Field{{Name: "user", Type: "string"}}},
+			"the field name \"user\" is an SQL keyword, choose another one"},
+		{"SQL keyword table", Spec{Name: "order_item", Table: "limit", Fields: newTestSpec().Fields},
+			"the table name \"limit\" is an SQL keyword, choose another one"},
+		{"Unknown type", Spec{Name: "order_item", Fields: []Field{{Name: "price", Type: "decimal"}}},
+			"invalid type \"decimal\" of field price, use string, int, int64, bool, float64 or time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, tt.spec.Validate(), tt.errorMessage)
+		})
+	}
+}
+
+func TestUnitUpperCamel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "OrderItemID", upperCamel("order_item_id"))
+	assert.Equal(t, "APIKey", upperCamel("api_key"))
+	assert.Equal(t, "orderItem", lowerCamel("order_item"))
+}
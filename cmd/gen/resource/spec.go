@@ -0,0 +1,207 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"gopkg.in/yaml.v3"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Spec describes a resource, e.g. the YAML
+//
+//	name: order_item
+//	table: order_items
+//	fields:
+//	  - name: title
+//	    type: string
+//	    required: true
+//	  - name: quantity
+//	    type: int
+type Spec struct {
+	// Name is the snake case singular name, it is the route path too
+	Name string `yaml:"name"`
+	// Table defaults to the name with an "s" appended
+	Table  string  `yaml:"table"`
+	Fields []Field `yaml:"fields"`
+}
+
+// Field is a column of the resource, the id and tenant_id columns are always added
+type Field struct {
+	Name string `yaml:"name"`
+	// Type is one of string, int, int64, bool, float64 or time
+	Type     string `yaml:"type"`
+	Required bool   `yaml:"required"`
+	// Immutable fields are set on create only
+	Immutable bool `yaml:"immutable"`
+}
+
+type fieldType struct {
+	goType  string
+	sqlType string
+	sample  string
+	// sqlZero is the value of the column in the existing rows when it is added to a table
+	sqlZero string
+	// zeroCheck formats the expression reporting that the request field is not set
+	zeroCheck string
+}
+
+var fieldTypes = map[string]fieldType{
+	"string":  {goType: "string", sqlType: "VARCHAR(255)", sample: `"example"`, sqlZero: "''", zeroCheck: `strings.TrimSpace(request.%s) == ""`},
+	"int":     {goType: "int", sqlType: "INT", sample: "1", sqlZero: "0", zeroCheck: "request.%s == 0"},
+	"int64":   {goType: "int64", sqlType: "BIGINT", sample: "1", sqlZero: "0", zeroCheck: "request.%s == 0"},
+	"bool":    {goType: "bool", sqlType: "BOOLEAN", sample: "true", sqlZero: "false", zeroCheck: "!request.%s"},
+	"float64": {goType: "float64", sqlType: "DOUBLE PRECISION", sample: "1.5", sqlZero: "0", zeroCheck: "request.%s == 0"},
+	"time":    {goType: "time.Time", sqlType: "TIMESTAMPTZ", sample: "time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)", sqlZero: "'epoch'", zeroCheck: "request.%s.IsZero()"},
+}
+
+// sqlZero returns the zero value of the SQL type of a field type
+func sqlZero(sqlType string) string {
+	for _, fieldType := range fieldTypes {
+		if fieldType.sqlType == sqlType {
+			return fieldType.sqlZero
+		}
+	}
+	return "NULL"
+}
+
+var snakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// reservedNames collide with the packages and variables of the scaffolded files
+var reservedNames = map[string]bool{
+	"model": true, "repository": true, "service": true, "controller": true, "mocks": true, "auth": true,
+	"gin": true, "mock": true, "assert": true, "require": true, "context": true, "errors": true, "strings": true,
+	"time": true, "json": true, "http": true, "httptest": true, "testing": true, "pgxpool": true,
+	"router": true, "req": true, "ctx": true, "err": true, "created": true, "policy": true, "request": true,
+}
+
+// sqlKeywords are the PostgreSQL keywords which cannot name a table or column without quotes, the reserved ones and
+// those which can only name functions or types
+var sqlKeywords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true, "as": true, "asc": true,
+	"asymmetric": true, "authorization": true, "binary": true, "both": true, "case": true, "cast": true, "check": true,
+	"collate": true, "collation": true, "column": true, "concurrently": true, "constraint": true, "create": true,
+	"cross": true, "current_catalog": true, "current_date": true, "current_role": true, "current_schema": true,
+	"current_time": true, "current_timestamp": true, "current_user": true, "default": true, "deferrable": true,
+	"desc": true, "distinct": true, "do": true, "else": true, "end": true, "except": true, "false": true,
+	"fetch": true, "for": true, "foreign": true, "freeze": true, "from": true, "full": true, "grant": true,
+	"group": true, "having": true, "ilike": true, "in": true, "initially": true, "inner": true, "intersect": true,
+	"into": true, "is": true, "isnull": true, "join": true, "lateral": true, "leading": true, "left": true,
+	"like": true, "limit": true, "localtime": true, "localtimestamp": true, "natural": true, "not": true,
+	"notnull": true, "null": true, "offset": true, "on": true, "only": true, "or": true, "order": true, "outer": true,
+	"overlaps": true, "placing": true, "primary": true, "references": true, "returning": true, "right": true,
+	"select": true, "session_user": true, "similar": true, "some": true, "symmetric": true, "system_user": true,
+	"table": true, "tablesample": true, "then": true, "to": true, "trailing": true, "true": true, "union": true,
+	"unique": true, "user": true, "using": true, "variadic": true, "verbose": true, "when": true, "where": true,
+	"window": true, "with": true,
+}
+
+// LoadSpec reads a YAML spec
+func LoadSpec(path string) (*Spec, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &Spec{}
+	if err = yaml.Unmarshal(content, spec); err != nil {
+		return nil, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// ParseFields parses a comma separated list of "<name>:<type>[:required][:immutable]" entries,
+// e.g. "title:string:required,quantity:int"
+func ParseFields(value string) ([]Field, error) {
+	fields := make([]Field, 0)
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid field %q, expected <name>:<type>[:required][:immutable]", entry)
+		}
+		field := Field{Name: parts[0], Type: parts[1]}
+		for _, option := range parts[2:] {
+			switch option {
+			case "required":
+				field.Required = true
+			case "immutable":
+				field.Immutable = true
+			default:
+				return nil, fmt.Errorf("invalid option %q of field %s, use required or immutable", option, field.Name)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// Validate checks the spec and fills in the defaults
+func (spec *Spec) Validate() error {
+	if !snakeCase.MatchString(spec.Name) {
+		return fmt.Errorf("invalid resource name %q, use snake case, e.g. order_item", spec.Name)
+	}
+	if name := lowerCamel(spec.Name); token.IsKeyword(name) || reservedNames[name] {
+		return fmt.Errorf("the resource name %q is reserved, choose another one", spec.Name)
+	}
+	if spec.Table == "" {
+		spec.Table = spec.Name + "s"
+	}
+	if !snakeCase.MatchString(spec.Table) {
+		return fmt.Errorf("invalid table name %q, use snake case", spec.Table)
+	}
+	if sqlKeywords[spec.Table] {
+		return fmt.Errorf("the table name %q is an SQL keyword, choose another one", spec.Table)
+	}
+	if len(spec.Fields) == 0 {
+		return errors.New("a resource needs at least one field")
+	}
+	seen := make(map[string]bool, len(spec.Fields))
+	for _, field := range spec.Fields {
+		if !snakeCase.MatchString(field.Name) {
+			return fmt.Errorf("invalid field name %q, use snake case", field.Name)
+		}
+		if field.Name == "id" || field.Name == "tenant_id" {
+			return fmt.Errorf("the field name %s is reserved, the id and tenant_id columns are always added", field.Name)
+		}
+		if sqlKeywords[field.Name] {
+			return fmt.Errorf("the field name %q is an SQL keyword, choose another one", field.Name)
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("duplicate field %s", field.Name)
+		}
+		seen[field.Name] = true
+		if _, ok := fieldTypes[field.Type]; !ok {
+			return fmt.Errorf("invalid type %q of field %s, use string, int, int64, bool, float64 or time", field.Type, field.Name)
+		}
+	}
+	return nil
+}
+
+// initialisms are kept upper case in Go names
+var initialisms = map[string]bool{"id": true, "url": true, "api": true, "http": true, "json": true, "sql": true, "uuid": true}
+
+// upperCamel converts a snake case name to an exported Go name, e.g. order_item_id to OrderItemID
+func upperCamel(name string) string {
+	var builder strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if initialisms[word] {
+			builder.WriteString(strings.ToUpper(word))
+		} else {
+			builder.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return builder.String()
+}
+
+// lowerCamel converts a snake case name to an unexported Go name, e.g. order_item to orderItem
+func lowerCamel(name string) string {
+	words := strings.SplitN(name, "_", 2)
+	if len(words) == 1 {
+		return words[0]
+	}
+	return words[0] + upperCamel(words[1])
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"crud/cmd/app/config"
+	"crud/cmd/app/config/database"
+	"crud/internal/repository/db"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+)
+
+const usage = `Usage: go run ./cmd/tenants <command> [flags] [tenant...]
+
+Manages the tenant schemas of the schema per tenant mode, TENANT_ENABLED=true and TENANT_MODE=schema.
+The database is configured with the environment of the application.
+
+Commands:
+  list     list the tenants with the migration version of their schema
+  migrate  create the schemas of the given tenants when missing and run the migrations in them,
+           without tenants the migrations run in the schemas of all tenants
+  drop     drop the schemas of the given tenants with all of their data, requires -yes
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var run func(ctx context.Context, pool *pgxpool.Pool, schemas *db.TenantSchemas, args []string) error
+	switch os.Args[1] {
+	case "list":
+		run = runList
+	case "migrate":
+		run = runMigrate
+	case "drop":
+		run = runDrop
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := connect(ctx, os.Args[2:], run); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func connect(ctx context.Context, args []string, run func(context.Context, *pgxpool.Pool, *db.TenantSchemas, []string) error) error {
+	appConfig, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if !appConfig.Tenant.IsSchemaMode() {
+		return errors.New("tenant schemas require TENANT_ENABLED=true and TENANT_MODE=schema")
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	schemas := db.NewTenantSchemas(appConfig.DB.Schema, logger)
+	pool, err := database.NewPool(appConfig.DB, nil, schemas, logger)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	return run(ctx, pool, schemas, args)
+}
+
+func runList(ctx context.Context, pool *pgxpool.Pool, schemas *db.TenantSchemas, _ []string) error {
+	tenantSchemas, err := schemas.List(ctx, pool)
+	if err != nil {
+		return err
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "TENANT\tSCHEMA\tVERSION\tDIRTY")
+	for _, tenantSchema := range tenantSchemas {
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%t\n", tenantSchema.Tenant, tenantSchema.Schema, tenantSchema.Version, tenantSchema.Dirty)
+	}
+	return writer.Flush()
+}
+
+func runMigrate(ctx context.Context, pool *pgxpool.Pool, schemas *db.TenantSchemas, args []string) error {
+	if len(args) == 0 {
+		if err := schemas.MigrateAll(ctx, pool); err != nil {
+			return err
+		}
+		fmt.Println("migrated all tenants")
+		return nil
+	}
+	for _, tenantID := range args {
+		if err := schemas.Provision(ctx, pool, tenantID); err != nil {
+			return err
+		}
+		fmt.Printf("migrated %s\n", schemas.Schema(tenantID))
+	}
+	return nil
+}
+
+func runDrop(ctx context.Context, pool *pgxpool.Pool, schemas *db.TenantSchemas, args []string) error {
+	flags := flag.NewFlagSet("drop", flag.ExitOnError)
+	yes := flags.Bool("yes", false, "confirm that the schemas and all of their data are dropped")
+	_ = flags.Parse(args)
+	if flags.NArg() == 0 {
+		return errors.New("no tenant given")
+	}
+	if !*yes {
+		for _, tenantID := range flags.Args() {
+			fmt.Printf("would drop %s\n", schemas.Schema(tenantID))
+		}
+		return errors.New("dropping deletes all data of the tenants, run again with -yes")
+	}
+	for _, tenantID := range flags.Args() {
+		if err := schemas.Drop(ctx, pool, tenantID); err != nil {
+			return err
+		}
+		fmt.Printf("dropped %s\n", schemas.Schema(tenantID))
+	}
+	return nil
+}
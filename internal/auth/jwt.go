@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// SupportedAlgorithms are the JWT signing algorithms accepted by the verifier
+var SupportedAlgorithms = []string{"RS256", "ES256", "EdDSA", "HS256"}
+
+// JWTVerifier validates bearer tokens against locally configured keys
+type JWTVerifier struct {
+	keys     KeyProvider
+	issuer   string
+	audience string
+	leeway   time.Duration
+}
+
+func NewJWTVerifier(keys KeyProvider, issuer, audience string, leeway time.Duration) *JWTVerifier {
+	return &JWTVerifier{keys: keys, issuer: issuer, audience: audience, leeway: leeway}
+}
+
+// Verify checks the signature and the iss, aud, exp and nbf claims and returns the token's principal
+func (verifier *JWTVerifier) Verify(token string) (*Principal, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(SupportedAlgorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(verifier.leeway),
+	}
+	if verifier.issuer != "" {
+		options = append(options, jwt.WithIssuer(verifier.issuer))
+	}
+	if verifier.audience != "" {
+		options = append(options, jwt.WithAudience(verifier.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, verifier.keyFunc, options...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrInvalidToken)
+	}
+	// The parser requires the exp claim
+	expiresAt, _ := claims.GetExpirationTime()
+	return &Principal{
+		Subject:   subject,
+		Method:    "jwt",
+		Roles:     stringListClaim(claims["roles"]),
+		Scopes:    append(stringListClaim(claims["scope"]), stringListClaim(claims["scp"])...),
+		Claims:    claims,
+		ExpiresAt: expiresAt.Time,
+	}, nil
+}
+
+// Authenticate implements Authenticator for the Bearer scheme
+func (verifier *JWTVerifier) Authenticate(_ context.Context, credentials string) (*Principal, error) {
+	if credentials == "" {
+		return nil, ErrMissingToken
+	}
+	return verifier.Verify(credentials)
+}
+
+// keyFunc picks the key by kid and makes sure its type matches the algorithm of the token,
+// so that e.g. an RSA public key can never be used as an HMAC secret
+func (verifier *JWTVerifier) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := verifier.keys.KeySet().Lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	matches := false
+	switch token.Method.Alg() {
+	case "RS256":
+		_, matches = key.(*rsa.PublicKey)
+	case "ES256":
+		_, matches = key.(*ecdsa.PublicKey)
+	case "EdDSA":
+		_, matches = key.(ed25519.PublicKey)
+	case "HS256":
+		_, matches = key.([]byte)
+	}
+	if !matches {
+		return nil, fmt.Errorf("key %q cannot be used with %s", kid, token.Method.Alg())
+	}
+	return key, nil
+}
+
+// stringListClaim accepts both JSON arrays and space separated strings, as used by the scope claim
+func stringListClaim(value any) []string {
+	switch claim := value.(type) {
+	case string:
+		return strings.Fields(claim)
+	case []any:
+		values := make([]string, 0, len(claim))
+		for _, item := range claim {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	testIssuer   = "https://issuer.example"
+	testAudience = "crud"
+)
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "42",
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nbf":   time.Now().Add(-time.Minute).Unix(),
+		"roles": []string{"admin"},
+		"scope": "users:read users:write",
+	}
+}
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func writeJWKS(t *testing.T, path string, keys ...map[string]string) {
+	content, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+}
+
+func TestUnitJWTVerifierAlgorithms(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hmacSecret := []byte("0123456789abcdef0123456789abcdef")
+
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksPath,
+		map[string]string{"kty": "RSA", "kid": "rsa", "n": encodeBigInt(rsaKey.N), "e": encodeBigInt(big.NewInt(int64(rsaKey.E)))},
+		map[string]string{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encodeBigInt(ecKey.X), "y": encodeBigInt(ecKey.Y)},
+		map[string]string{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": base64.RawURLEncoding.EncodeToString(edPublic)},
+		map[string]string{"kty": "oct", "kid": "hs", "k": base64.RawURLEncoding.EncodeToString(hmacSecret)},
+	)
+	keys, err := NewFileKeyProvider(jwksPath, time.Minute, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	verifier := NewJWTVerifier(keys, testIssuer, testAudience, 0)
+
+	tests := []struct {
+		name   string
+		method jwt.SigningMethod
+		kid    string
+		key    any
+	}{
+		{"RS256", jwt.SigningMethodRS256, "rsa", rsaKey},
+		{"ES256", jwt.SigningMethodES256, "ec", ecKey},
+		{"EdDSA", jwt.SigningMethodEdDSA, "ed", edPrivate},
+		{"HS256", jwt.SigningMethodHS256, "hs", hmacSecret},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(signToken(t, tt.method, tt.kid, tt.key, validClaims()))
+			require.NoError(t, err)
+			assert.Equal(t, "42", principal.Subject)
+			assert.Equal(t, []string{"admin"}, principal.Roles)
+			assert.Equal(t, []string{"users:read", "users:write"}, principal.Scopes)
+		})
+	}
+
+	t.Run("Key of another algorithm is rejected", func(t *testing.T) {
+		_, err := verifier.Verify(signToken(t, jwt.SigningMethodHS256, "rsa", hmacSecret, validClaims()))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestUnitJWTVerifierClaims(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	keySet := NewKeySet()
+	keySet.Add("", secret)
+	verifier := NewJWTVerifier(NewStaticKeyProvider(keySet), testIssuer, testAudience, 0)
+
+	tests := []struct {
+		name   string
+		mutate func(claims jwt.MapClaims)
+	}{
+		{"Expired token", func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{"Missing expiration", func(claims jwt.MapClaims) { delete(claims, "exp") }},
+		{"Token not valid yet", func(claims jwt.MapClaims) { claims["nbf"] = time.Now().Add(time.Hour).Unix() }},
+		{"Wrong issuer", func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example" }},
+		{"Wrong audience", func(claims jwt.MapClaims) { claims["aud"] = "other" }},
+		{"Missing subject", func(claims jwt.MapClaims) { delete(claims, "sub") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.mutate(claims)
+			_, err := verifier.Verify(signToken(t, jwt.SigningMethodHS256, "", secret, claims))
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
+
+func TestUnitFileKeyProviderRotation(t *testing.T) {
+	t.Parallel()
+
+	oldSecret := []byte("old-secret-old-secret-old-secret")
+	newSecret := []byte("new-secret-new-secret-new-secret")
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksPath, map[string]string{"kty": "oct", "kid": "old", "k": base64.RawURLEncoding.EncodeToString(oldSecret)})
+
+	keys, err := NewFileKeyProvider(jwksPath, 0, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	verifier := NewJWTVerifier(keys, testIssuer, testAudience, 0)
+	_, err = verifier.Verify(signToken(t, jwt.SigningMethodHS256, "old", oldSecret, validClaims()))
+	require.NoError(t, err)
+
+	writeJWKS(t, jwksPath,
+		map[string]string{"kty": "oct", "kid": "old", "k": base64.RawURLEncoding.EncodeToString(oldSecret)},
+		map[string]string{"kty": "oct", "kid": "new", "k": base64.RawURLEncoding.EncodeToString(newSecret)})
+	// Make sure the change is seen even on file systems with coarse modification times
+	require.NoError(t, os.Chtimes(jwksPath, time.Now(), time.Now().Add(time.Second)))
+
+	_, err = verifier.Verify(signToken(t, jwt.SigningMethodHS256, "new", newSecret, validClaims()))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,266 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// KeySet holds the verification keys by key id. Keys without a kid are stored under an empty id.
+type KeySet struct {
+	keys map[string]any
+}
+
+func NewKeySet() *KeySet {
+	return &KeySet{keys: make(map[string]any)}
+}
+
+// Add registers a key: *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey or []byte for HMAC
+func (keySet *KeySet) Add(kid string, key any) {
+	keySet.keys[kid] = key
+}
+
+// Lookup returns the key for a kid, falling back to the only key of the set when the token has no kid
+func (keySet *KeySet) Lookup(kid string) (any, bool) {
+	if key, ok := keySet.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(keySet.keys) == 1 {
+		for _, key := range keySet.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func (keySet *KeySet) Len() int {
+	return len(keySet.keys)
+}
+
+// KeyProvider returns the current key set, implementations may reload keys between calls
+type KeyProvider interface {
+	KeySet() *KeySet
+}
+
+// StaticKeyProvider is a key set that never changes
+type StaticKeyProvider struct {
+	keySet *KeySet
+}
+
+func NewStaticKeyProvider(keySet *KeySet) *StaticKeyProvider {
+	return &StaticKeyProvider{keySet: keySet}
+}
+
+func (provider *StaticKeyProvider) KeySet() *KeySet {
+	return provider.keySet
+}
+
+// FileKeyProvider loads keys from a JWKS or PEM file and reloads them when the file changes.
+// The file is checked at most once per interval, so rotated keys are picked up without a restart.
+type FileKeyProvider struct {
+	path      string
+	interval  time.Duration
+	mutex     sync.Mutex
+	keySet    *KeySet
+	modTime   time.Time
+	size      int64
+	lastCheck time.Time
+	logger    *slog.Logger
+}
+
+func NewFileKeyProvider(path string, interval time.Duration, logger *slog.Logger) (*FileKeyProvider, error) {
+	provider := &FileKeyProvider{path: path, interval: interval, logger: logger}
+	if err := provider.reload(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+func (provider *FileKeyProvider) KeySet() *KeySet {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+	if time.Since(provider.lastCheck) >= provider.interval {
+		if err := provider.reload(); err != nil {
+			// Keep the previous keys, a half written file must not lock everybody out
+			provider.logger.Warn("failed to reload authentication keys",
+				slog.String("path", provider.path),
+				slog.String("error", err.Error()))
+		}
+	}
+	return provider.keySet
+}
+
+func (provider *FileKeyProvider) reload() error {
+	provider.lastCheck = time.Now()
+	info, err := os.Stat(provider.path)
+	if err != nil {
+		return err
+	}
+	if provider.keySet != nil && info.ModTime().Equal(provider.modTime) && info.Size() == provider.size {
+		return nil
+	}
+	content, err := os.ReadFile(provider.path)
+	if err != nil {
+		return err
+	}
+	keySet, err := ParseKeys(content)
+	if err != nil {
+		return err
+	}
+	if provider.keySet != nil {
+		provider.logger.Info("reloaded authentication keys",
+			slog.String("path", provider.path),
+			slog.Int("keys", keySet.Len()))
+	}
+	provider.keySet = keySet
+	provider.modTime = info.ModTime()
+	provider.size = info.Size()
+	return nil
+}
+
+// ParseKeys parses either a JWKS document or PEM encoded public keys
+func ParseKeys(content []byte) (*KeySet, error) {
+	if block, _ := pem.Decode(content); block != nil {
+		return ParsePEM(content)
+	}
+	return ParseJWKS(content)
+}
+
+// ParsePEM parses one or more PEM encoded public keys, they are registered without a kid
+// unless the block has a "kid" header
+func ParsePEM(content []byte) (*KeySet, error) {
+	keySet := NewKeySet()
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			break
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		keySet.Add(block.Headers["kid"], key)
+	}
+	if keySet.Len() == 0 {
+		return nil, errors.New("no public key found in PEM data")
+	}
+	return keySet, nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// ParseJWKS parses a JSON Web Key Set (RFC 7517) with RSA, EC, OKP (Ed25519) and oct keys
+func ParseJWKS(content []byte) (*KeySet, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(content, &jwks); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+	keySet := NewKeySet()
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWK %q: %w", jwk.Kid, err)
+		}
+		keySet.Add(jwk.Kid, key)
+	}
+	if keySet.Len() == 0 {
+		return nil, errors.New("no signing key found in JWKS")
+	}
+	return keySet, nil
+}
+
+func (jwk *jsonWebKey) publicKey() (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		// ECDH conversion validates that the point is on the curve
+		if _, err = key.ECDH(); err != nil {
+			return nil, err
+		}
+		return key, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(jwk.K)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(decoded), nil
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+func TestUnitPasswordHashing(t *testing.T) {
+	t.Parallel()
+
+	hash, err := HashPassword("correct horse")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$"))
+
+	other, err := HashPassword("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "every hash gets its own salt")
+
+	valid, err := VerifyPassword("correct horse", hash)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = VerifyPassword("wrong", hash)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("legacy"), bcrypt.MinCost)
+	require.NoError(t, err)
+	valid, err = VerifyPassword("legacy", string(bcryptHash))
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = VerifyPassword("password", "plain")
+	assert.ErrorIs(t, err, ErrUnsupportedPasswordHash)
+}
@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	PermissionUsersRead  = "users:read"
+	PermissionUsersWrite = "users:write"
+	// PermissionUsersWriteSelf lets a caller modify only the user record it owns
+	PermissionUsersWriteSelf = "users:write:self"
+	PermissionAPIKeysAdmin   = "apikeys:admin"
+	// PermissionOpsAdmin grants the operational endpoints, e.g. query statistics
+	PermissionOpsAdmin = "ops:admin"
+
+	PermissionOrganizationsRead  = "organizations:read"
+	PermissionOrganizationsWrite = "organizations:write"
+	// PermissionOrganizationsReadSelf lets a caller read only the organizations it is a member of
+	PermissionOrganizationsReadSelf = "organizations:read:self"
+	// PermissionOrganizationsWriteSelf lets a caller create organizations and manage those it is an owner or
+	// admin of
+	PermissionOrganizationsWriteSelf = "organizations:write:self"
+
+	PermissionWebhooksRead  = "webhooks:read"
+	PermissionWebhooksWrite = "webhooks:write"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// DefaultRolePermissions is used when no role mapping is configured. The gen resource command grants the
+// permissions of the scaffolded resources above the gen: lines
+var DefaultRolePermissions = map[string][]string{
+	"admin": {PermissionUsersRead, PermissionUsersWrite, PermissionAPIKeysAdmin, PermissionOpsAdmin,
+		PermissionOrganizationsRead, PermissionOrganizationsWrite, PermissionWebhooksRead, PermissionWebhooksWrite,
+		// gen:admin-permissions
+	},
+	"viewer": {
+		PermissionUsersRead, PermissionOrganizationsRead,
+		// gen:viewer-permissions
+	},
+	"user": {PermissionUsersRead, PermissionUsersWriteSelf, PermissionOrganizationsReadSelf,
+		PermissionOrganizationsWriteSelf},
+}
+
+// Policy grants permissions to principals through their roles and scopes
+type Policy struct {
+	rolePermissions map[string][]string
+	// logger writes the audit records of denied requests
+	logger *slog.Logger
+}
+
+func NewPolicy(rolePermissions map[string][]string, logger *slog.Logger) *Policy {
+	return &Policy{rolePermissions: rolePermissions, logger: logger}
+}
+
+// ParseRolePermissions parses a semicolon separated list of "<role>=<permission>,<permission>" entries,
+// e.g. "admin=users:read,users:write;viewer=users:read"
+func ParseRolePermissions(value string) (map[string][]string, error) {
+	rolePermissions := make(map[string][]string)
+	for _, entry := range strings.Split(value, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		role, permissions, found := strings.Cut(entry, "=")
+		role = strings.TrimSpace(role)
+		if !found || role == "" {
+			return nil, fmt.Errorf("invalid role permissions %q, expected <role>=<permission>,<permission>", entry)
+		}
+		for _, permission := range strings.Split(permissions, ",") {
+			if permission = strings.TrimSpace(permission); permission != "" {
+				rolePermissions[role] = append(rolePermissions[role], permission)
+			}
+		}
+	}
+	return rolePermissions, nil
+}
+
+// HasPermission reports whether one of the principal's roles or scopes grants the permission
+func (policy *Policy) HasPermission(principal *Principal, permission string) bool {
+	if slices.Contains(principal.Scopes, permission) {
+		return true
+	}
+	for _, role := range principal.Roles {
+		if slices.Contains(policy.rolePermissions[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsKnown reports whether a role grants the permission, the permissions no role grants do not exist
+func (policy *Policy) IsKnown(permission string) bool {
+	for _, permissions := range policy.rolePermissions {
+		if slices.Contains(permissions, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize checks that the caller of ctx has the permission on the resource and writes an audit
+// log entry when it does not
+func (policy *Policy) Authorize(ctx context.Context, permission, resource string, resourceID any) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if ok && policy.HasPermission(principal, permission) {
+		return nil
+	}
+	policy.audit(ctx, principal, permission, resource, resourceID)
+	return fmt.Errorf("%w: missing permission %s", ErrForbidden, permission)
+}
+
+// AuthorizeOwner allows callers with the permission on any record and callers with the self
+// permission on the record they own
+func (policy *Policy) AuthorizeOwner(ctx context.Context, permission, selfPermission, resource string, ownerID int) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if ok && policy.HasPermission(principal, permission) {
+		return nil
+	}
+	if ok && policy.HasPermission(principal, selfPermission) {
+		if userID, isUser := principal.UserID(); isUser && userID == ownerID {
+			return nil
+		}
+	}
+	policy.audit(ctx, principal, permission, resource, ownerID)
+	return fmt.Errorf("%w: missing permission %s", ErrForbidden, permission)
+}
+
+// AuthorizeMember allows callers with the permission on any record and callers with the self permission on the
+// records isMember reports the user of the caller a member of, e.g. with a role granting the change
+func (policy *Policy) AuthorizeMember(ctx context.Context, permission, selfPermission, resource string, resourceID any,
+	isMember func(userID int) (bool, error)) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if ok && policy.HasPermission(principal, permission) {
+		return nil
+	}
+	if ok && policy.HasPermission(principal, selfPermission) {
+		if userID, isUser := principal.UserID(); isUser {
+			member, err := isMember(userID)
+			if err != nil {
+				return err
+			}
+			if member {
+				return nil
+			}
+		}
+	}
+	policy.audit(ctx, principal, permission, resource, resourceID)
+	return fmt.Errorf("%w: missing permission %s", ErrForbidden, permission)
+}
+
+func (policy *Policy) audit(ctx context.Context, principal *Principal, permission, resource string, resourceID any) {
+	subject := ""
+	if principal != nil {
+		subject = principal.Subject
+	}
+	policy.logger.WarnContext(ctx, "access denied",
+		slog.Bool("audit", true),
+		slog.String("subject", subject),
+		slog.String("permission", permission),
+		slog.String("resource", resource),
+		slog.Any("resource_id", resourceID))
+}
+
+// UserID returns the id of the user record the principal owns, taken from the user_id claim or a numeric
+// subject. Only tokens and sessions identify users, API keys never own a user record.
+func (principal *Principal) UserID() (int, bool) {
+	if principal.Method != "jwt" && principal.Method != "session" {
+		return 0, false
+	}
+	if value, ok := principal.Claims["user_id"]; ok {
+		switch userID := value.(type) {
+		case float64:
+			// JSON numbers decode to float64, only whole numbers in the range of int are user ids
+			if userID == math.Trunc(userID) && userID >= math.MinInt && userID < math.MaxInt {
+				return int(userID), true
+			}
+		case string:
+			if id, err := strconv.Atoi(userID); err == nil {
+				return id, true
+			}
+		}
+		return 0, false
+	}
+	if id, err := strconv.Atoi(principal.Subject); err == nil {
+		return id, true
+	}
+	return 0, false
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"math"
+	"testing"
+)
+
+func TestUnitPrincipalUserID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		principal Principal
+		userID    int
+		ok        bool
+	}{
+		{"Numeric claim", Principal{Method: "jwt", Claims: map[string]any{"user_id": float64(7)}}, 7, true},
+		{"String claim", Principal{Method: "jwt", Claims: map[string]any{"user_id": "7"}}, 7, true},
+		{"Fractional claim", Principal{Method: "jwt", Claims: map[string]any{"user_id": 7.5}}, 0, false},
+		{"Infinite claim", Principal{Method: "jwt", Claims: map[string]any{"user_id": math.Inf(1)}}, 0, false},
+		{"Numeric subject", Principal{Method: "session", Subject: "9"}, 9, true},
+		{"API key", Principal{Method: "api_key", Subject: "9"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, ok := tt.principal.UserID()
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.userID, userID)
+		})
+	}
+}
+
+func TestUnitPolicyAuthorizeMember(t *testing.T) {
+	t.Parallel()
+	policy := NewPolicy(DefaultRolePermissions, slog.New(slog.DiscardHandler))
+	isMember := func(userID int) (bool, error) { return userID == 5, nil }
+	authorize := func(principal *Principal) error {
+		return policy.AuthorizeMember(WithPrincipal(context.Background(), principal), PermissionOrganizationsWrite,
+			PermissionOrganizationsWriteSelf, "organization", 3, isMember)
+	}
+
+	assert.NoError(t, authorize(&Principal{Method: "jwt", Subject: "9", Roles: []string{"admin"}}))
+	assert.NoError(t, authorize(&Principal{Method: "jwt", Subject: "5", Roles: []string{"user"}}))
+	assert.ErrorIs(t, authorize(&Principal{Method: "jwt", Subject: "6", Roles: []string{"user"}}), ErrForbidden)
+	assert.ErrorIs(t, authorize(&Principal{Method: "api_key", Subject: "5", Roles: []string{"user"}}), ErrForbidden,
+		"API keys are no members")
+	assert.ErrorIs(t, authorize(&Principal{Method: "jwt", Subject: "5", Roles: []string{"viewer"}}), ErrForbidden)
+}
+
+func TestUnitPolicyIsKnown(t *testing.T) {
+	t.Parallel()
+	policy := NewPolicy(DefaultRolePermissions, slog.New(slog.DiscardHandler))
+
+	assert.True(t, policy.IsKnown(PermissionUsersRead))
+	assert.True(t, policy.IsKnown(PermissionOpsAdmin))
+	assert.False(t, policy.IsKnown("root"))
+}
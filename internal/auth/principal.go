@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Subject string
+	// Method is how the caller authenticated, e.g. "jwt"
+	Method string
+	Roles  []string
+	Scopes []string
+	Claims map[string]any
+	// ExpiresAt is when the credentials expire, it is zero when they do not
+	ExpiresAt time.Time
+}
+
+// HasRole reports whether the principal was granted the role
+func (principal *Principal) HasRole(role string) bool {
+	return slices.Contains(principal.Roles, role)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx that carries the authenticated principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal of the request, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// Authenticator validates the credentials of one Authorization header scheme
+type Authenticator interface {
+	Authenticate(ctx context.Context, credentials string) (*Principal, error)
+}
@@ -0,0 +1,105 @@
+package buildinfo
+
+import (
+	"expvar"
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"sync"
+)
+
+// Set at build time, e.g.
+// go build -ldflags "-X crud/internal/buildinfo.Version=v1.2.0 -X crud/internal/buildinfo.Commit=$(git rev-parse HEAD)"
+// Values left empty are read from the build information the go command embeds
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+	// Dirty is "true" when the binary was built from a tree with uncommitted changes
+	Dirty string
+)
+
+const unknown = "unknown"
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	Dirty     bool   `json:"dirty"`
+}
+
+var (
+	info     Info
+	infoOnce sync.Once
+)
+
+// Get returns the build information of the binary
+func Get() Info {
+	infoOnce.Do(func() {
+		info = read(Version, Commit, BuildTime, Dirty, debug.ReadBuildInfo)
+	})
+	return info
+}
+
+func read(version, commit, buildTime, dirty string, readBuildInfo func() (*debug.BuildInfo, bool)) Info {
+	result := Info{Version: version, Commit: commit, BuildTime: buildTime, GoVersion: runtime.Version()}
+	result.Dirty, _ = strconv.ParseBool(dirty)
+	if buildInfo, ok := readBuildInfo(); ok {
+		if result.Version == "" && buildInfo.Main.Version != "(devel)" {
+			result.Version = buildInfo.Main.Version
+		}
+		for _, setting := range buildInfo.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && result.Commit == "":
+				result.Commit = setting.Value
+			case setting.Key == "vcs.time" && result.BuildTime == "":
+				result.BuildTime = setting.Value
+			case setting.Key == "vcs.modified" && dirty == "":
+				result.Dirty = setting.Value == "true"
+			}
+		}
+	}
+	for _, value := range []*string{&result.Version, &result.Commit, &result.BuildTime} {
+		if *value == "" {
+			*value = unknown
+		}
+	}
+	return result
+}
+
+// String is the version with the short commit, e.g. "v1.2.0 (3f9a1c2b7d4e, dirty)"
+func (info Info) String() string {
+	commit := info.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	if info.Dirty {
+		commit += ", dirty"
+	}
+	return info.Version + " (" + commit + ")"
+}
+
+// LogAttrs are the attributes of the startup log line
+func (info Info) LogAttrs() []any {
+	return []any{
+		slog.String("version", info.Version),
+		slog.String("commit", info.Commit),
+		slog.String("build_time", info.BuildTime),
+		slog.String("go_version", info.GoVersion),
+		slog.Bool("dirty", info.Dirty),
+	}
+}
+
+var publishOnce sync.Once
+
+// Publish exposes the build information as the build_info expvar metric
+func Publish() {
+	publishOnce.Do(func() {
+		expvar.Publish("build_info", expvar.Func(func() any {
+			return Get()
+		}))
+	})
+}
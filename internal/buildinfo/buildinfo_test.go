@@ -0,0 +1,40 @@
+package buildinfo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+func TestUnitRead(t *testing.T) {
+	t.Parallel()
+
+	readBuildInfo := func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Main: debug.Module{Version: "(devel)"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "3f9a1c2b7d4e5f60718293a4b5c6d7e8f9012345"},
+				{Key: "vcs.time", Value: "2026-10-01T12:00:00Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+
+	info := read("", "", "", "", readBuildInfo)
+	assert.Equal(t, Info{
+		Version:   unknown,
+		Commit:    "3f9a1c2b7d4e5f60718293a4b5c6d7e8f9012345",
+		BuildTime: "2026-10-01T12:00:00Z",
+		GoVersion: runtime.Version(),
+		Dirty:     true,
+	}, info, "the embedded build information is the fallback")
+	assert.Equal(t, "unknown (3f9a1c2b7d4e, dirty)", info.String())
+
+	info = read("v1.2.0", "abc123", "2026-10-02T08:00:00Z", "false", readBuildInfo)
+	assert.Equal(t, "v1.2.0", info.Version)
+	assert.Equal(t, "abc123", info.Commit, "ldflags take precedence")
+	assert.Equal(t, "2026-10-02T08:00:00Z", info.BuildTime)
+	assert.False(t, info.Dirty)
+	assert.Equal(t, "v1.2.0 (abc123)", info.String())
+}
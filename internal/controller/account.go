@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"crud/internal/model"
+	"crud/internal/service"
+	responseUtil "crud/internal/util/response"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AccountController struct {
+	accountService service.IAccountService
+}
+
+func NewAccountController(accountService service.IAccountService) *AccountController {
+	return &AccountController{accountService: accountService}
+}
+
+// SetupRoutes registers login on the public routes and the remaining endpoints on the authenticated ones,
+// setting another user's password also needs the admin middlewares
+func (controller *AccountController) SetupRoutes(publicRoute *gin.RouterGroup, authenticatedRoute *gin.RouterGroup,
+	adminMiddlewares ...gin.HandlerFunc) {
+	publicRoute.POST("/auth/login", controller.Login)
+	authenticatedRoute.POST("/auth/logout", controller.Logout)
+	authenticatedRoute.PUT("/auth/password", controller.ChangePassword)
+	authenticatedRoute.PUT("/user/:id/password", append(adminMiddlewares, controller.SetPassword)...)
+}
+
+// accountErrorStatus maps account errors to HTTP statuses
+func accountErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidCredentials):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrAccountLocked):
+		return http.StatusLocked
+	case errors.Is(err, service.ErrNotSessionCaller), errors.Is(err, service.ErrNotUserCaller):
+		return http.StatusBadRequest
+	default:
+		return errorStatus(err, fallback)
+	}
+}
+
+// Login exchanges email and password for a session token
+//
+// @Summary		Logs a user in
+// @Description	Exchanges email and password for a session token, accounts are locked after repeated failures
+// @Accept		json
+// @Produce		json
+// @Param		credentials	body		model.LoginRequest	true	"Credentials"
+// @Success		200			{object}	model.LoginResponse
+// @Failure		400			{object}	response.HTTPStatusMessage
+// @Failure		401			{object}	response.HTTPStatusMessage
+// @Failure		423			{object}	response.HTTPStatusMessage
+// @Router		/auth/login [post]
+func (controller *AccountController) Login(context *gin.Context) {
+	request := model.LoginRequest{}
+	if err := context.ShouldBindJSON(&request); err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	loginResponse, err := controller.accountService.Login(&request, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, accountErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	context.Header("Cache-Control", "no-store")
+	context.JSON(http.StatusOK, loginResponse)
+}
+
+// Logout revokes the session token of the caller
+//
+// @Summary		Logs a user out
+// @Description	Revokes the session token used to call this endpoint
+// @Produce		json
+// @Success		200		{object}	response.HTTPStatusMessage
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		401		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/auth/logout [post]
+func (controller *AccountController) Logout(context *gin.Context) {
+	ctx := context.Request.Context()
+	if err := controller.accountService.Logout(&ctx); err != nil {
+		responseUtil.NewError(context, accountErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	context.JSON(http.StatusOK, responseUtil.HTTPStatusMessage{Code: http.StatusOK, Message: "logged out"})
+}
+
+// ChangePassword changes the password of the caller
+//
+// @Summary		Changes own password
+// @Description	Changes the password of the caller and revokes its other sessions
+// @Accept		json
+// @Produce		json
+// @Param		password	body		model.ChangePasswordRequest	true	"Passwords"
+// @Success		200			{object}	response.HTTPStatusMessage
+// @Failure		400			{object}	response.HTTPStatusMessage
+// @Failure		401			{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/auth/password [put]
+func (controller *AccountController) ChangePassword(context *gin.Context) {
+	request := model.ChangePasswordRequest{}
+	if err := context.ShouldBindJSON(&request); err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	if err := controller.accountService.ChangePassword(&request, &ctx); err != nil {
+		responseUtil.NewError(context, accountErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	context.JSON(http.StatusOK, responseUtil.HTTPStatusMessage{Code: http.StatusOK, Message: "password changed"})
+}
+
+// SetPassword sets the password of a user
+//
+// @Summary		Sets a user password
+// @Description	Sets the password of any user and revokes all of its sessions
+// @Accept		json
+// @Produce		json
+// @Param		id			path		int							true	"User ID"
+// @Param		password	body		model.SetPasswordRequest	true	"Password"
+// @Success		200			{object}	response.HTTPStatusMessage
+// @Failure		400			{object}	response.HTTPStatusMessage
+// @Failure		403			{object}	response.HTTPStatusMessage
+// @Failure		404			{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/user/{id}/password [put]
+func (controller *AccountController) SetPassword(context *gin.Context) {
+	id, err := responseUtil.GetIntParam(context, "id")
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+	request := model.SetPasswordRequest{}
+	if err = context.ShouldBindJSON(&request); err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	if err = controller.accountService.SetPassword(id, &request, &ctx); err != nil {
+		responseUtil.NewError(context, accountErrorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	context.JSON(http.StatusOK, responseUtil.HTTPStatusMessage{Code: http.StatusOK, Message: "password set"})
+}
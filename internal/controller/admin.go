@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"crud/internal/model"
+	"crud/internal/service"
+	responseUtil "crud/internal/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// AdminController serves the operational endpoints, they are meant for administrators only
+type AdminController struct {
+	queryStatsService service.IQueryStatsService
+	logLevelService   service.ILogLevelService
+}
+
+func NewAdminController(queryStatsService service.IQueryStatsService, logLevelService service.ILogLevelService) *AdminController {
+	return &AdminController{queryStatsService: queryStatsService, logLevelService: logLevelService}
+}
+
+func (controller *AdminController) SetupRoutes(superRoute *gin.RouterGroup, middlewares ...gin.HandlerFunc) {
+	adminRouter := superRoute.Group("admin", middlewares...)
+	{
+		adminRouter.GET("/queries", controller.GetQueryStats)
+		adminRouter.DELETE("/queries", controller.ResetQueryStats)
+		adminRouter.GET("/loglevel", controller.GetLogLevel)
+		adminRouter.PUT("/loglevel", controller.SetLogLevel)
+		adminRouter.POST("/loglevel/debugtoken", controller.CreateDebugToken)
+	}
+}
+
+// GetQueryStats gets the query statistics
+//
+// @Summary		Gets the query statistics
+// @Description	Gets count, errors and p50/p95/max durations by statement, the most expensive statements first
+// @Produce		json
+// @Param		explain	query		bool		false	"Explain the last slow execution of each statement"
+// @Success		200		{object}	model.QueryStatsResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/admin/queries [get]
+func (controller *AdminController) GetQueryStats(context *gin.Context) {
+	explain, err := strconv.ParseBool(context.DefaultQuery("explain", "false"))
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	stats, err := controller.queryStatsService.GetQueryStats(explain, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	context.JSON(http.StatusOK, stats)
+}
+
+// ResetQueryStats resets the query statistics
+//
+// @Summary		Resets the query statistics
+// @Description	Resets the query statistics, e.g. after a deployment
+// @Success		204
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/admin/queries [delete]
+func (controller *AdminController) ResetQueryStats(context *gin.Context) {
+	ctx := context.Request.Context()
+	controller.queryStatsService.Reset(&ctx)
+	context.Status(http.StatusNoContent)
+}
+
+// GetLogLevel gets the log level
+//
+// @Summary		Gets the log level
+// @Description	Gets the current log level of the service
+// @Produce		json
+// @Success		200		{object}	model.LogLevelResponse
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/admin/loglevel [get]
+func (controller *AdminController) GetLogLevel(context *gin.Context) {
+	ctx := context.Request.Context()
+	context.JSON(http.StatusOK, controller.logLevelService.GetLogLevel(&ctx))
+}
+
+// SetLogLevel sets the log level
+//
+// @Summary		Sets the log level
+// @Description	Sets the log level until the next restart or configuration reload
+// @Accept		json
+// @Produce		json
+// @Param		level	body		model.SetLogLevelRequest	true	"Log level"
+// @Success		200		{object}	model.LogLevelResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/admin/loglevel [put]
+func (controller *AdminController) SetLogLevel(context *gin.Context) {
+	request := model.SetLogLevelRequest{}
+	if err := context.ShouldBindJSON(&request); err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	level, err := controller.logLevelService.SetLogLevel(&request, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	context.JSON(http.StatusOK, level)
+}
+
+// CreateDebugToken creates a debug token
+//
+// @Summary		Creates a debug token
+// @Description	Creates a token which logs the requests sending it in the returned header at debug level
+// @Accept		json
+// @Produce		json
+// @Param		token	body		model.DebugTokenRequest	true	"Token validity"
+// @Success		201		{object}	model.DebugTokenResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/admin/loglevel/debugtoken [post]
+func (controller *AdminController) CreateDebugToken(context *gin.Context) {
+	request := model.DebugTokenRequest{}
+	if err := context.ShouldBindJSON(&request); err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	token, err := controller.logLevelService.CreateDebugToken(&request, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	context.Header("Cache-Control", "no-store")
+	context.JSON(http.StatusCreated, token)
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"crud/internal/model"
+	"crud/internal/service"
+	responseUtil "crud/internal/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type APIKeyController struct {
+	apiKeyService service.IAPIKeyService
+}
+
+func NewAPIKeyController(apiKeyService service.IAPIKeyService) *APIKeyController {
+	return &APIKeyController{apiKeyService: apiKeyService}
+}
+
+func (controller *APIKeyController) SetupRoutes(superRoute *gin.RouterGroup, middlewares ...gin.HandlerFunc) {
+	apiKeyRouter := superRoute.Group("apikey", middlewares...)
+	{
+		apiKeyRouter.GET("/", controller.GetAPIKeys)
+		apiKeyRouter.POST("/", controller.CreateAPIKey)
+		apiKeyRouter.DELETE("/:id", controller.RevokeAPIKey)
+	}
+}
+
+// GetAPIKeys gets list of api keys
+//
+// @Summary		Gets list of api keys
+// @Description	Gets list of api keys without their secrets
+// @Produce		json
+// @Param		offset	query		int			false	"Offset"
+// @Param		limit	query		int			false	"Limit"
+// @Success		200		{array}		model.APIKeyResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/apikey/ [get]
+func (controller *APIKeyController) GetAPIKeys(context *gin.Context) {
+	offset, err := responseUtil.GetIntQueryParamOrDefault(context, "offset", DefaultOffset)
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	limit, err := responseUtil.GetIntQueryParamOrDefault(context, "limit", DefaultLimit)
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	apiKeys, err := controller.apiKeyService.GetAPIKeys(offset, limit, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	context.JSON(http.StatusOK, apiKeys)
+}
+
+// CreateAPIKey creates an api key
+//
+// @Summary		Creates an api key
+// @Description	Creates an api key, the plaintext key is only returned by this call. The scopes have to be permissions
+// @Description	the caller holds
+// @Accept		json
+// @Produce		json
+// @Param		apiKey	body		model.CreateAPIKeyRequest	true	"Api key"
+// @Success		201		{object}	model.CreateAPIKeyResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/apikey/ [post]
+func (controller *APIKeyController) CreateAPIKey(context *gin.Context) {
+	request := model.CreateAPIKeyRequest{}
+	if err := context.ShouldBindJSON(&request); err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	apiKey, err := controller.apiKeyService.Create(&request, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	context.Header("Cache-Control", "no-store")
+	context.JSON(http.StatusCreated, apiKey)
+}
+
+// RevokeAPIKey revokes an api key
+//
+// @Summary		Revokes an api key
+// @Description	Revokes an api key, it cannot be used to authenticate anymore
+// @Produce		json
+// @Param		id		path		int			true	"Api key ID"
+// @Success		200		{object}	model.APIKeyResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Failure		404		{object}	response.HTTPStatusMessage
+// @Failure		500		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/apikey/{id} [delete]
+func (controller *APIKeyController) RevokeAPIKey(context *gin.Context) {
+	id, err := responseUtil.GetIntParam(context, "id")
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	apiKey, err := controller.apiKeyService.Revoke(id, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	context.JSON(http.StatusOK, apiKey)
+}
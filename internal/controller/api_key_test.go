@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitAPIKeyControllerRevoke(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	mockService := mocks.NewMockIAPIKeyService(t)
+	mockService.EXPECT().Revoke(1, mock.Anything).Return(&model.APIKeyResponse{ID: 1, Name: "billing"}, nil)
+	mockService.EXPECT().Revoke(2, mock.Anything).Return(nil, pgx.ErrNoRows)
+	mockService.EXPECT().Revoke(3, mock.Anything).Return(nil, errors.New("connection refused"))
+	router := gin.New()
+	NewAPIKeyController(mockService).SetupRoutes(router.Group("/api/v1"))
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/api/v1/apikey/1", http.StatusOK},
+		{"/api/v1/apikey/2", http.StatusNotFound},
+		{"/api/v1/apikey/3", http.StatusInternalServerError},
+		{"/api/v1/apikey/billing", http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		testRecorder := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, test.path, nil)
+		router.ServeHTTP(testRecorder, req)
+		assert.Equal(t, test.status, testRecorder.Code, test.path)
+	}
+}
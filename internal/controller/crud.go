@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"crud/internal/service"
+	responseUtil "crud/internal/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// Controller serves the CRUD routes of a resource under its path.
+//
+// Swagger reads the annotations of the handler methods, so a resource documents its routes by embedding
+// the controller and declaring annotated methods which call the embedded handlers, e.g.
+//
+//	// GetById gets a widget by id
+//	//
+//	// @Summary	Gets a widget by id
+//	// @Produce	json
+//	// @Param		id	path		int	true	"Widget ID"
+//	// @Success	200	{object}	model.WidgetResponse
+//	// @Security	BearerAuth
+//	// @Router		/widget/{id} [get]
+//	func (controller *WidgetController) GetById(context *gin.Context) {
+//		controller.Controller.GetById(context)
+//	}
+type Controller[ID any, Req any, Resp any] struct {
+	path    string
+	service service.IService[ID, Req, Resp]
+	parseID func(value string) (ID, error)
+}
+
+func NewController[ID any, Req any, Resp any](path string, service service.IService[ID, Req, Resp], parseID func(value string) (ID, error)) *Controller[ID, Req, Resp] {
+	return &Controller[ID, Req, Resp]{path: path, service: service, parseID: parseID}
+}
+
+// ParseIntID parses the id path parameter of resources with integer keys
+func ParseIntID(value string) (int, error) {
+	return strconv.Atoi(value)
+}
+
+func (controller *Controller[ID, Req, Resp]) SetupRoutes(superRoute *gin.RouterGroup, middlewares ...gin.HandlerFunc) {
+	router := superRoute.Group(controller.path, middlewares...)
+	{
+		router.GET("/", controller.GetAll)
+		router.GET("/:id", controller.GetById)
+		router.POST("/", controller.Create)
+		router.PUT("/:id", controller.Update)
+		router.DELETE("/:id", controller.Delete)
+	}
+}
+
+func (controller *Controller[ID, Req, Resp]) GetAll(context *gin.Context) {
+	offset, err := responseUtil.GetIntQueryParamOrDefault(context, "offset", DefaultOffset)
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := responseUtil.GetIntQueryParamOrDefault(context, "limit", DefaultLimit)
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	responses, err := controller.service.GetAll(offset, limit, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	context.JSON(http.StatusOK, responses)
+}
+
+func (controller *Controller[ID, Req, Resp]) GetById(context *gin.Context) {
+	id, err := controller.parseID(context.Param("id"))
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	response, err := controller.service.GetById(id, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusNotFound), err)
+		return
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+func (controller *Controller[ID, Req, Resp]) Create(context *gin.Context) {
+	request := new(Req)
+	if err := context.ShouldBindJSON(request); err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	response, err := controller.service.Create(request, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	context.JSON(http.StatusCreated, response)
+}
+
+func (controller *Controller[ID, Req, Resp]) Update(context *gin.Context) {
+	id, err := controller.parseID(context.Param("id"))
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+	request := new(Req)
+	if err = context.ShouldBindJSON(request); err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	response, err := controller.service.Update(id, request, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+func (controller *Controller[ID, Req, Resp]) Delete(context *gin.Context) {
+	id, err := controller.parseID(context.Param("id"))
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	response, err := controller.service.Delete(id, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	context.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"crud/internal/mocks"
+	"crud/internal/service"
+	"crud/internal/util/response"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testWidgetRequest struct {
+	Name string `json:"name"`
+}
+
+type testWidgetResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func newTestWidgetRouter(mockService *mocks.MockIService[int, testWidgetRequest, testWidgetResponse]) *gin.Engine {
+	router := gin.New()
+	controller := NewController[int, testWidgetRequest, testWidgetResponse]("widget", mockService, ParseIntID)
+	controller.SetupRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestUnitControllerUpdate(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	mockService := mocks.NewMockIService[int, testWidgetRequest, testWidgetResponse](t)
+	mockService.EXPECT().
+		Update(7, &testWidgetRequest{Name: "cog"}, mock.Anything).
+		Return(&testWidgetResponse{ID: 7, Name: "cog"}, nil)
+	router := newTestWidgetRouter(mockService)
+
+	testRecorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/api/v1/widget/7", strings.NewReader(`{"name":"cog"}`))
+	router.ServeHTTP(testRecorder, req)
+
+	require.Equal(t, http.StatusOK, testRecorder.Code)
+	widget := testWidgetResponse{}
+	assert.NoError(t, json.Unmarshal(testRecorder.Body.Bytes(), &widget))
+	assert.Equal(t, testWidgetResponse{ID: 7, Name: "cog"}, widget)
+}
+
+func TestUnitControllerErrors(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	mockService := mocks.NewMockIService[int, testWidgetRequest, testWidgetResponse](t)
+	mockService.EXPECT().
+		Create(&testWidgetRequest{}, mock.Anything).
+		Return(nil, fmt.Errorf("%w: name is required", service.ErrInvalidRequest))
+	router := newTestWidgetRouter(mockService)
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         string
+		errorMessage string
+	}{
+		{"Non integer id", http.MethodGet, "/api/v1/widget/seven", "",
+			"strconv.Atoi: parsing \"seven\": invalid syntax"},
+		{"Invalid request", http.MethodPost, "/api/v1/widget/", "{}",
+			"invalid request: name is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRecorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			router.ServeHTTP(testRecorder, req)
+
+			assert.Equal(t, http.StatusBadRequest, testRecorder.Code)
+			statusMessage := response.HTTPStatusMessage{}
+			assert.NoError(t, json.Unmarshal(testRecorder.Body.Bytes(), &statusMessage))
+			assert.Equal(t, tt.errorMessage, statusMessage.Message)
+		})
+	}
+}
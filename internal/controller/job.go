@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"crud/internal/service"
+	responseUtil "crud/internal/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// JobController serves the administration of the background jobs, they are meant for administrators only
+type JobController struct {
+	jobService service.IJobService
+}
+
+func NewJobController(jobService service.IJobService) *JobController {
+	return &JobController{jobService: jobService}
+}
+
+func (controller *JobController) SetupRoutes(superRoute *gin.RouterGroup, middlewares ...gin.HandlerFunc) {
+	jobRouter := superRoute.Group("admin/jobs", middlewares...)
+	{
+		jobRouter.GET("", controller.GetJobs)
+		jobRouter.POST("/:id/retry", controller.Retry)
+	}
+}
+
+// GetJobs gets list of background jobs
+//
+// @Summary		Gets list of background jobs
+// @Description	Gets the background jobs of the tenant, newest first, e.g. the failed ones with status=failed
+// @Produce		json
+// @Param		status	query		string		false	"Status"	Enums(pending, running, succeeded, failed)
+// @Param		offset	query		int			false	"Offset"
+// @Param		limit	query		int			false	"Limit"
+// @Success		200		{array}		model.JobResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/admin/jobs [get]
+func (controller *JobController) GetJobs(context *gin.Context) {
+	offset, limit, err := pageParams(context)
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	jobs, err := controller.jobService.GetJobs(context.Query("status"), offset, limit, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	context.JSON(http.StatusOK, jobs)
+}
+
+// Retry runs a failed background job again
+//
+// @Summary		Runs a failed background job again
+// @Description	Makes a failed job pending again with a fresh count of attempts, unless another job holding its unique
+// @Description	key is pending or running
+// @Produce		json
+// @Param		id		path		int		true	"Job ID"
+// @Success		202		{object}	model.JobResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		404		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Failure		409		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/admin/jobs/{id}/retry [post]
+func (controller *JobController) Retry(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	job, err := controller.jobService.Retry(id, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	context.JSON(http.StatusAccepted, job)
+}
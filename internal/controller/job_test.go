@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"crud/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitJobControllerRoutes(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	mockService := mocks.NewMockIJobService(t)
+	mockService.EXPECT().
+		GetJobs(model.JobFailed, 0, DefaultLimit, mock.Anything).
+		Return([]*model.JobResponse{{ID: 8, Type: "reports.send", Status: model.JobFailed}}, nil)
+	mockService.EXPECT().
+		GetJobs("dead", 0, DefaultLimit, mock.Anything).
+		Return(nil, fmt.Errorf("%w: unknown status %q", service.ErrInvalidRequest, "dead"))
+	mockService.EXPECT().
+		Retry(int64(8), mock.Anything).
+		Return(&model.JobResponse{ID: 8, Type: "reports.send", Status: model.JobPending}, nil)
+	mockService.EXPECT().
+		Retry(int64(9), mock.Anything).
+		Return(nil, pgx.ErrNoRows)
+	mockService.EXPECT().
+		Retry(int64(10), mock.Anything).
+		Return(nil, fmt.Errorf("%w: another job holding its unique key is pending or running", repository.ErrConflict))
+	mockService.EXPECT().
+		Retry(int64(11), mock.Anything).
+		Return(nil, errors.New("connection refused"))
+	router := gin.New()
+	NewJobController(mockService).SetupRoutes(router.Group(""))
+
+	testRecorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/admin/jobs?status=failed", nil)
+	router.ServeHTTP(testRecorder, req)
+	require.Equal(t, http.StatusOK, testRecorder.Code)
+	jobs := make([]model.JobResponse, 0)
+	assert.NoError(t, json.Unmarshal(testRecorder.Body.Bytes(), &jobs))
+	require.Len(t, jobs, 1)
+	assert.Equal(t, model.JobFailed, jobs[0].Status)
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/admin/jobs?status=dead", nil)
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusBadRequest, testRecorder.Code)
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/admin/jobs/8/retry", nil)
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusAccepted, testRecorder.Code)
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/admin/jobs/9/retry", nil)
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusNotFound, testRecorder.Code)
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/admin/jobs/10/retry", nil)
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusConflict, testRecorder.Code)
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/admin/jobs/11/retry", nil)
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusInternalServerError, testRecorder.Code)
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/admin/jobs/latest/retry", nil)
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusBadRequest, testRecorder.Code)
+}
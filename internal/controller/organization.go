@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"crud/internal/model"
+	"crud/internal/service"
+	responseUtil "crud/internal/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// OrganizationController serves the organization routes, the memberships of an organization and the
+// organizations of a user
+type OrganizationController struct {
+	*Controller[int, model.OrganizationRequest, model.OrganizationResponse]
+	organizationService service.IOrganizationService
+}
+
+func NewOrganizationController(organizationService service.IOrganizationService) *OrganizationController {
+	return &OrganizationController{
+		Controller:          NewController[int, model.OrganizationRequest, model.OrganizationResponse]("organization", organizationService, ParseIntID),
+		organizationService: organizationService,
+	}
+}
+
+func (controller *OrganizationController) SetupRoutes(superRoute *gin.RouterGroup, middlewares ...gin.HandlerFunc) {
+	organizationRouter := superRoute.Group("organization", middlewares...)
+	{
+		organizationRouter.GET("/", controller.GetAll)
+		organizationRouter.GET("/:id", controller.GetById)
+		organizationRouter.POST("/", controller.Create)
+		organizationRouter.PUT("/:id", controller.Update)
+		organizationRouter.DELETE("/:id", controller.Delete)
+		organizationRouter.GET("/:id/members", controller.GetMembers)
+		organizationRouter.POST("/:id/members", controller.AddMember)
+		organizationRouter.DELETE("/:id/members/:userId", controller.RemoveMember)
+	}
+	superRoute.Group("user", middlewares...).GET("/:id/organizations", controller.GetUserOrganizations)
+}
+
+// GetAll gets list of organizations
+//
+// @Summary		Gets list of organizations
+// @Produce		json
+// @Param		offset	query		int			false	"Offset"
+// @Param		limit	query		int			false	"Limit"
+// @Success		200		{array}		model.OrganizationResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/organization/ [get]
+func (controller *OrganizationController) GetAll(context *gin.Context) {
+	controller.Controller.GetAll(context)
+}
+
+// GetById gets organization by id
+//
+// @Summary		Gets organization by id
+// @Produce		json
+// @Param		id		path		int		true	"Organization ID"
+// @Success		200		{object}	model.OrganizationResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		404		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/organization/{id} [get]
+func (controller *OrganizationController) GetById(context *gin.Context) {
+	controller.Controller.GetById(context)
+}
+
+// Create creates an organization, the calling user becomes its owner
+//
+// @Summary		Creates an organization
+// @Description	Creates an organization, the calling user becomes its owner
+// @Accept		json
+// @Produce		json
+// @Param		organization	body		model.OrganizationRequest	true	"New organization"
+// @Success		201		{object}	model.OrganizationResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/organization/ [post]
+func (controller *OrganizationController) Create(context *gin.Context) {
+	controller.Controller.Create(context)
+}
+
+// Update updates an organization
+//
+// @Summary		Updates an organization
+// @Accept		json
+// @Produce		json
+// @Param		id				path		int							true	"Organization ID"
+// @Param		organization	body		model.OrganizationRequest	true	"Organization new data"
+// @Success		200		{object}	model.OrganizationResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/organization/{id} [put]
+func (controller *OrganizationController) Update(context *gin.Context) {
+	controller.Controller.Update(context)
+}
+
+// Delete deletes an organization together with its memberships
+//
+// @Summary		Deletes an organization
+// @Description	Deletes an organization together with its memberships
+// @Produce		json
+// @Param		id		path		int		true	"Organization ID"
+// @Success		200		{object}	model.OrganizationResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/organization/{id} [delete]
+func (controller *OrganizationController) Delete(context *gin.Context) {
+	controller.Controller.Delete(context)
+}
+
+// GetMembers gets list of the members of an organization
+//
+// @Summary		Gets list of the members of an organization
+// @Produce		json
+// @Param		id		path		int			true	"Organization ID"
+// @Param		offset	query		int			false	"Offset"
+// @Param		limit	query		int			false	"Limit"
+// @Success		200		{array}		model.MemberResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/organization/{id}/members [get]
+func (controller *OrganizationController) GetMembers(context *gin.Context) {
+	id, err := responseUtil.GetIntParam(context, "id")
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+	offset, limit, err := pageParams(context)
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	members, err := controller.organizationService.GetMembers(id, offset, limit, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	context.JSON(http.StatusOK, members)
+}
+
+// AddMember adds a user to an organization
+//
+// @Summary		Adds a user to an organization
+// @Accept		json
+// @Produce		json
+// @Param		id			path		int						true	"Organization ID"
+// @Param		membership	body		model.AddMemberRequest	true	"New member"
+// @Success		201		{object}	model.MembershipResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Failure		404		{object}	response.HTTPStatusMessage
+// @Failure		409		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/organization/{id}/members [post]
+func (controller *OrganizationController) AddMember(context *gin.Context) {
+	id, err := responseUtil.GetIntParam(context, "id")
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+	request := model.AddMemberRequest{}
+	if err = context.ShouldBindJSON(&request); err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	membership, err := controller.organizationService.AddMember(id, &request, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	context.JSON(http.StatusCreated, membership)
+}
+
+// RemoveMember removes a user from an organization, the last owner cannot be removed
+//
+// @Summary		Removes a user from an organization
+// @Description	Removes a user from an organization, only owners remove owners and the last owner cannot be removed
+// @Produce		json
+// @Param		id		path		int		true	"Organization ID"
+// @Param		userId	path		int		true	"User ID"
+// @Success		200		{object}	model.MembershipResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/organization/{id}/members/{userId} [delete]
+func (controller *OrganizationController) RemoveMember(context *gin.Context) {
+	id, err := responseUtil.GetIntParam(context, "id")
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+	userID, err := responseUtil.GetIntParam(context, "userId")
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	membership, err := controller.organizationService.RemoveMember(id, userID, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	context.JSON(http.StatusOK, membership)
+}
+
+// GetUserOrganizations gets list of the organizations of a user
+//
+// @Summary		Gets list of the organizations of a user
+// @Produce		json
+// @Param		id		path		int			true	"User ID"
+// @Param		offset	query		int			false	"Offset"
+// @Param		limit	query		int			false	"Limit"
+// @Success		200		{array}		model.UserOrganizationResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/user/{id}/organizations [get]
+func (controller *OrganizationController) GetUserOrganizations(context *gin.Context) {
+	id, err := responseUtil.GetIntParam(context, "id")
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+	offset, limit, err := pageParams(context)
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	organizations, err := controller.organizationService.GetUserOrganizations(id, offset, limit, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	context.JSON(http.StatusOK, organizations)
+}
+
+// pageParams reads the offset and limit query parameters of list routes
+func pageParams(context *gin.Context) (int, int, error) {
+	offset, err := responseUtil.GetIntQueryParamOrDefault(context, "offset", DefaultOffset)
+	if err != nil {
+		return 0, 0, err
+	}
+	limit, err := responseUtil.GetIntQueryParamOrDefault(context, "limit", DefaultLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	return offset, limit, nil
+}
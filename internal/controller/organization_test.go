@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"crud/internal/service"
+	"crud/internal/util/response"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUnitOrganizationControllerRoutes(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	mockService := mocks.NewMockIOrganizationService(t)
+	mockService.EXPECT().
+		GetUserOrganizations(5, 0, DefaultLimit, mock.Anything).
+		Return([]*model.UserOrganizationResponse{{OrganizationResponse: model.OrganizationResponse{ID: 3, Name: "acme"}, Role: "owner"}}, nil)
+	mockService.EXPECT().
+		AddMember(3, &model.AddMemberRequest{UserID: 6, Role: "member"}, mock.Anything).
+		Return(nil, fmt.Errorf("%w: the user is a member of the organization already", repository.ErrConflict))
+	mockService.EXPECT().
+		AddMember(3, &model.AddMemberRequest{UserID: 7, Role: "member"}, mock.Anything).
+		Return(nil, fmt.Errorf("%w: unknown user or organization", pgx.ErrNoRows))
+	mockService.EXPECT().
+		RemoveMember(3, 5, mock.Anything).
+		Return(nil, fmt.Errorf("%w: the last owner of an organization cannot be removed", service.ErrInvalidRequest))
+	router := gin.New()
+	v1Router := router.Group("/api/v1")
+	NewUserController(mocks.NewMockIUserService(t), nil).SetupRoutes(v1Router)
+	NewOrganizationController(mockService).SetupRoutes(v1Router)
+
+	testRecorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/user/5/organizations", nil)
+	router.ServeHTTP(testRecorder, req)
+	require.Equal(t, http.StatusOK, testRecorder.Code)
+	organizations := make([]model.UserOrganizationResponse, 0)
+	assert.NoError(t, json.Unmarshal(testRecorder.Body.Bytes(), &organizations))
+	require.Len(t, organizations, 1)
+	assert.Equal(t, "owner", organizations[0].Role)
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodDelete, "/api/v1/organization/3/members/5", nil)
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusBadRequest, testRecorder.Code)
+	statusMessage := response.HTTPStatusMessage{}
+	assert.NoError(t, json.Unmarshal(testRecorder.Body.Bytes(), &statusMessage))
+	assert.Equal(t, "invalid request: the last owner of an organization cannot be removed", statusMessage.Message)
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/organization/3/members", strings.NewReader(`{"user_id":6,"role":"member"}`))
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusConflict, testRecorder.Code, "duplicate memberships conflict")
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/organization/3/members", strings.NewReader(`{"user_id":7,"role":"member"}`))
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusNotFound, testRecorder.Code, "unknown users are not found")
+}
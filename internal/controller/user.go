@@ -1,29 +1,46 @@
 package controller
 
 import (
+	"crud/internal/auth"
 	"crud/internal/model"
+	"crud/internal/repository"
 	"crud/internal/service"
 	responseUtil "crud/internal/util/response"
+	"errors"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"net/http"
+	"slices"
 )
 
 const DefaultOffset = 0
 const DefaultLimit = 10
 
 type UserController struct {
-	userService service.IUserService
+	userService    service.IUserService
+	allowedOrigins []string
 }
 
-func NewUserController(userService service.IUserService) *UserController {
-	return &UserController{userService: userService}
+// NewUserController serves the user routes to browsers on the allowedOrigins, on all origins when there are none
+func NewUserController(userService service.IUserService, allowedOrigins []string) *UserController {
+	return &UserController{userService: userService, allowedOrigins: allowedOrigins}
+}
+
+// corsMiddleware allows the allowedOrigins, all origins when there are none
+func corsMiddleware(allowedOrigins []string) gin.HandlerFunc {
+	if len(allowedOrigins) == 0 || slices.Contains(allowedOrigins, "*") {
+		return cors.Default()
+	}
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOrigins = allowedOrigins
+	return cors.New(corsConfig)
 }
 
 func (controller *UserController) SetupRoutes(superRoute *gin.RouterGroup) {
 	userRouter := superRoute.Group("user")
 	{
-		userRouter.Use(cors.Default())
+		userRouter.Use(corsMiddleware(controller.allowedOrigins))
 		// Keeping it here for preflight requests because of https://github.com/gin-gonic/gin/issues/3546
 		userRouter.OPTIONS("/*any", func(c *gin.Context) {})
 		userRouter.GET("/", controller.GetUsers)
@@ -34,6 +51,21 @@ func (controller *UserController) SetupRoutes(superRoute *gin.RouterGroup) {
 	}
 }
 
+// errorStatus maps errors that have a dedicated HTTP status, other errors get the fallback status
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, auth.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, pgx.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrConflict):
+		return http.StatusConflict
+	}
+	return fallback
+}
+
 // GetUsers gets list of users
 //
 // @Summary		Gets list of users summary
@@ -42,6 +74,8 @@ func (controller *UserController) SetupRoutes(superRoute *gin.RouterGroup) {
 // @Param		offset	query		int			false	"Offset"
 // @Param		limit	query		int			false	"Limit"
 // @Success		200		{object}	model.UserResponse
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
 // @Router		/user/ [get]
 func (controller *UserController) GetUsers(context *gin.Context) {
 	offset, err := responseUtil.GetIntQueryParamOrDefault(context, "offset", DefaultOffset)
@@ -59,7 +93,7 @@ func (controller *UserController) GetUsers(context *gin.Context) {
 	ctx := context.Request.Context()
 	users, err := controller.userService.GetUsers(offset, limit, &ctx)
 	if err != nil {
-		responseUtil.NewError(context, http.StatusInternalServerError, err)
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
 		return
 	}
 	context.JSON(http.StatusOK, users)
@@ -74,6 +108,8 @@ func (controller *UserController) GetUsers(context *gin.Context) {
 // @Success		200		{object}	model.UserResponse
 // @Failure		400		{object}	response.HTTPStatusMessage
 // @Failure		404		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
 // @Router		/user/{id} [get]
 func (controller *UserController) GetUserById(context *gin.Context) {
 	id, err := responseUtil.GetIntParam(context, "id")
@@ -85,7 +121,7 @@ func (controller *UserController) GetUserById(context *gin.Context) {
 	ctx := context.Request.Context()
 	user, err := controller.userService.GetById(id, &ctx)
 	if err != nil {
-		responseUtil.NewError(context, http.StatusNotFound, err)
+		responseUtil.NewError(context, errorStatus(err, http.StatusNotFound), err)
 		return
 	}
 	context.JSON(http.StatusOK, user)
@@ -100,6 +136,8 @@ func (controller *UserController) GetUserById(context *gin.Context) {
 // @Param		user	body		model.CreateUserRequest	true	"Add user"
 // @Success		201		{object}	model.UserResponse
 // @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
 // @Router		/user/ [post]
 func (controller *UserController) CreateUser(context *gin.Context) {
 	request := model.CreateUserRequest{}
@@ -111,7 +149,7 @@ func (controller *UserController) CreateUser(context *gin.Context) {
 	ctx := context.Request.Context()
 	userResponse, err := controller.userService.Create(&request, &ctx)
 	if err != nil {
-		responseUtil.NewError(context, http.StatusInternalServerError, err)
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
 		return
 	}
 	context.JSON(http.StatusCreated, userResponse)
@@ -127,6 +165,8 @@ func (controller *UserController) CreateUser(context *gin.Context) {
 // @Success		200		{object}	model.UserResponse
 // @Failure		400		{object}	response.HTTPStatusMessage
 // @Failure		404		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
 // @Router		/user/{id} [put]
 func (controller *UserController) UpdateUser(context *gin.Context) {
 	updateUserRequest := model.UpdateUserRequest{}
@@ -138,7 +178,7 @@ func (controller *UserController) UpdateUser(context *gin.Context) {
 	ctx := context.Request.Context()
 	user, err := controller.userService.Update(&updateUserRequest, &ctx)
 	if err != nil {
-		responseUtil.NewError(context, http.StatusBadRequest, err)
+		responseUtil.NewError(context, errorStatus(err, http.StatusBadRequest), err)
 		return
 	}
 	context.JSON(http.StatusOK, user)
@@ -153,6 +193,8 @@ func (controller *UserController) UpdateUser(context *gin.Context) {
 // @Param		id		path		int			true	"User ID"
 // @Success		200		{object}	response.HTTPStatusMessage
 // @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
 // @Router		/user/{id} [delete]
 func (controller *UserController) DeleteUser(context *gin.Context) {
 	id, err := responseUtil.GetIntParam(context, "id")
@@ -164,7 +206,7 @@ func (controller *UserController) DeleteUser(context *gin.Context) {
 	ctx := context.Request.Context()
 	user, err := controller.userService.Delete(id, &ctx)
 	if err != nil {
-		responseUtil.NewError(context, http.StatusBadRequest, err)
+		responseUtil.NewError(context, errorStatus(err, http.StatusBadRequest), err)
 		return
 	}
 	context.JSON(http.StatusOK, user)
@@ -0,0 +1,315 @@
+package controller
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/model"
+	"crud/internal/service"
+	"crud/internal/stream"
+	responseUtil "crud/internal/util/response"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxSubscriptions is how many queries a connection subscribes to at most
+	DefaultMaxSubscriptions = 10
+	// socketReadLimit bounds the messages of the clients, requests are small
+	socketReadLimit = 4096
+)
+
+var (
+	errCredentialsExpired = errors.New("credentials expired")
+	errCredentialsRevoked = errors.New("credentials revoked")
+)
+
+// UserSocketController serves the WebSocket API of live user queries. Clients subscribe to queries and receive their
+// snapshot followed by the changes of their result. The connection is pinged every heartbeat interval and closed with
+// 1013 (try again later) when it does not keep up with the changes, the client reconnects and subscribes again. It is
+// closed with 1008 (policy violation) when the credentials of the client expire or, checked on every heartbeat, are
+// revoked
+type UserSocketController struct {
+	liveService       service.IUserLiveService
+	heartbeatInterval time.Duration
+	maxSubscriptions  int
+	// reauthenticate is nil when authentication is disabled
+	reauthenticate func(req *http.Request) (*auth.Principal, error)
+	upgrader       websocket.Upgrader
+	logger         *slog.Logger
+}
+
+func NewUserSocketController(liveService service.IUserLiveService, heartbeatInterval time.Duration, maxSubscriptions int,
+	allowedOrigins []string, reauthenticate func(req *http.Request) (*auth.Principal, error), logger *slog.Logger) *UserSocketController {
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = DefaultHeartbeatInterval
+	}
+	if maxSubscriptions <= 0 {
+		maxSubscriptions = DefaultMaxSubscriptions
+	}
+	return &UserSocketController{
+		liveService:       liveService,
+		heartbeatInterval: heartbeatInterval,
+		maxSubscriptions:  maxSubscriptions,
+		reauthenticate:    reauthenticate,
+		logger:            logger,
+		upgrader:          websocket.Upgrader{CheckOrigin: checkOrigin(allowedOrigins)},
+	}
+}
+
+// checkOrigin accepts the handshakes from the allowedOrigins and from the origin of the server, so that other sites
+// cannot open sockets with the credentials of the browser. Clients other than browsers send no Origin header
+func checkOrigin(allowedOrigins []string) func(req *http.Request) bool {
+	return func(req *http.Request) bool {
+		origin := req.Header.Get("Origin")
+		if origin == "" || slices.Contains(allowedOrigins, "*") {
+			return true
+		}
+		for _, allowed := range allowedOrigins {
+			if strings.EqualFold(origin, allowed) {
+				return true
+			}
+		}
+		originURL, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(originURL.Host, req.Host)
+	}
+}
+
+func (controller *UserSocketController) SetupRoutes(superRoute *gin.RouterGroup, middlewares ...gin.HandlerFunc) {
+	superRoute.GET("/ws", append(middlewares, controller.Connect)...)
+}
+
+// Connect upgrades to the WebSocket API of live user queries
+//
+// @Summary		Live user queries over WebSocket
+// @Description	Clients send {"type":"subscribe","id":"a","query":{"user_id":5}} or {"type":"subscribe","id":"b",
+// @Description	"query":{"filter":{"name":"ada","min_age":30},"limit":20}} and receive a "snapshot" message followed by
+// @Description	"added", "updated" and "removed" messages with the id of their subscription; {"type":"unsubscribe",
+// @Description	"id":"a"} ends a subscription. Browsers pass the bearer token in access_token. The socket is closed with
+// @Description	1008 when the credentials expire or are revoked.
+// @Param		access_token	query		string	false	"Bearer token, for clients which cannot set headers"
+// @Success		101		{string}	string	"Switching Protocols"
+// @Failure		401		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/ws [get]
+func (controller *UserSocketController) Connect(context *gin.Context) {
+	ctx := context.Request.Context()
+	// The caller is authorized before the upgrade, so that failures are plain HTTP responses
+	subscription, err := controller.liveService.Watch(&ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	defer subscription.Close()
+	conn, err := controller.upgrader.Upgrade(context.Writer, context.Request, nil)
+	if err != nil {
+		// The upgrader answered the handshake with the error
+		return
+	}
+	defer conn.Close()
+
+	socket := &userSocket{
+		controller:   controller,
+		conn:         conn,
+		request:      context.Request,
+		subscription: subscription,
+		queries:      make(map[string]*service.UserLiveQuery),
+	}
+	if err = socket.serve(ctx); err != nil {
+		controller.logger.DebugContext(ctx, "closed user socket", slog.String("reason", err.Error()))
+	}
+}
+
+// userSocket is a connection of the WebSocket API. Only serve writes to the connection, the changes wait in the
+// subscription while it writes, so a slow client ends up dropped by the broker
+type userSocket struct {
+	controller *UserSocketController
+	conn       *websocket.Conn
+	// request is the handshake, its credentials are authenticated again on every heartbeat
+	request      *http.Request
+	subscription *stream.Subscription
+	queries      map[string]*service.UserLiveQuery
+}
+
+// serve answers the requests of the client and sends the changes of its queries until the connection ends
+func (socket *userSocket) serve(ctx context.Context) error {
+	requests := make(chan []byte)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		readErr <- socket.read(requests, done)
+	}()
+
+	heartbeat := time.NewTicker(socket.controller.heartbeatInterval)
+	defer heartbeat.Stop()
+	var expired <-chan time.Time
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && !principal.ExpiresAt.IsZero() {
+		expiry := time.NewTimer(time.Until(principal.ExpiresAt))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+	for {
+		var err error
+		select {
+		case request := <-requests:
+			err = socket.handle(request, ctx)
+		case event, ok := <-socket.subscription.Events():
+			if !ok {
+				return socket.closeWith(socket.subscription.Err())
+			}
+			err = socket.apply(event)
+		case <-heartbeat.C:
+			if err = socket.reauthenticate(); err != nil {
+				return socket.closePolicy(err)
+			}
+			err = socket.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout))
+		case <-expired:
+			return socket.closePolicy(errCredentialsExpired)
+		case err = <-readErr:
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// read passes the messages of the client to serve. A client which answers no ping within two heartbeats is gone
+func (socket *userSocket) read(requests chan<- []byte, done <-chan struct{}) error {
+	timeout := 2 * socket.controller.heartbeatInterval
+	socket.conn.SetReadLimit(socketReadLimit)
+	socket.conn.SetPongHandler(func(string) error {
+		return socket.conn.SetReadDeadline(time.Now().Add(timeout))
+	})
+	for {
+		if err := socket.conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+		_, message, err := socket.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		select {
+		case requests <- message:
+		case <-done:
+			return nil
+		}
+	}
+}
+
+// reauthenticate checks that the credentials of the handshake were not revoked since, e.g. the session or the api key
+func (socket *userSocket) reauthenticate() error {
+	if socket.controller.reauthenticate == nil {
+		return nil
+	}
+	if _, ok := auth.PrincipalFromContext(socket.request.Context()); !ok {
+		return nil
+	}
+	if _, err := socket.controller.reauthenticate(socket.request); err != nil {
+		return fmt.Errorf("%w: %w", errCredentialsRevoked, err)
+	}
+	return nil
+}
+
+func (socket *userSocket) handle(message []byte, ctx context.Context) error {
+	request := &model.LiveRequest{}
+	if err := json.Unmarshal(message, request); err != nil {
+		return socket.write(&model.LiveMessage{Type: model.LiveError, Error: "invalid message: " + err.Error()})
+	}
+	if request.ID == "" {
+		return socket.write(&model.LiveMessage{Type: model.LiveError, Error: "id is required"})
+	}
+	switch request.Type {
+	case model.LiveSubscribe:
+		return socket.subscribe(request, ctx)
+	case model.LiveUnsubscribe:
+		if _, ok := socket.queries[request.ID]; !ok {
+			return socket.writeError(request.ID, fmt.Errorf("no subscription %q", request.ID))
+		}
+		delete(socket.queries, request.ID)
+		return socket.write(&model.LiveMessage{Type: model.LiveUnsubscribed, ID: request.ID})
+	default:
+		return socket.writeError(request.ID, fmt.Errorf("unknown message type %q, use %s or %s", request.Type,
+			model.LiveSubscribe, model.LiveUnsubscribe))
+	}
+}
+
+func (socket *userSocket) subscribe(request *model.LiveRequest, ctx context.Context) error {
+	if _, ok := socket.queries[request.ID]; ok {
+		return socket.writeError(request.ID, fmt.Errorf("subscription %q exists", request.ID))
+	}
+	if len(socket.queries) >= socket.controller.maxSubscriptions {
+		return socket.writeError(request.ID, fmt.Errorf("at most %d subscriptions per connection", socket.controller.maxSubscriptions))
+	}
+	snapshot, err := socket.controller.liveService.Snapshot(request.Query, &ctx)
+	if err != nil {
+		return socket.writeError(request.ID, err)
+	}
+	socket.queries[request.ID] = service.NewUserLiveQuery(request.ID, request.Query, snapshot)
+	return socket.write(&model.LiveMessage{Type: model.LiveSnapshot, ID: request.ID, Users: snapshot})
+}
+
+func (socket *userSocket) apply(event *model.EventMessage) error {
+	for _, query := range socket.queries {
+		message, err := query.Apply(event)
+		if err != nil {
+			return err
+		}
+		if message == nil {
+			continue
+		}
+		if err = socket.write(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (socket *userSocket) writeError(id string, err error) error {
+	return socket.write(&model.LiveMessage{Type: model.LiveError, ID: id, Error: err.Error()})
+}
+
+// write fails when the client does not take the message within streamWriteTimeout
+func (socket *userSocket) write(message *model.LiveMessage) error {
+	if err := socket.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return err
+	}
+	return socket.conn.WriteJSON(message)
+}
+
+// closeWith closes the connection after the subscription ended with err, a client which was too slow or may have
+// missed changes subscribes again
+func (socket *userSocket) closeWith(err error) error {
+	code, reason := websocket.CloseTryAgainLater, "changes may have been missed, subscribe again"
+	switch {
+	case errors.Is(err, stream.ErrClosed):
+		code, reason = websocket.CloseGoingAway, "server shutting down"
+	case errors.Is(err, stream.ErrSlowConsumer):
+		reason = "too slow, subscribe again"
+	}
+	return errors.Join(err, socket.close(code, reason))
+}
+
+// closePolicy closes the connection with 1008 (policy violation) after the credentials of the client expired or were
+// revoked, the client authenticates again
+func (socket *userSocket) closePolicy(err error) error {
+	reason := errCredentialsExpired.Error()
+	if errors.Is(err, errCredentialsRevoked) {
+		reason = errCredentialsRevoked.Error()
+	}
+	return errors.Join(err, socket.close(websocket.ClosePolicyViolation, reason))
+}
+
+func (socket *userSocket) close(code int, reason string) error {
+	return socket.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(streamWriteTimeout))
+}
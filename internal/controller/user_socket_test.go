@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/stream"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dialUserSocket serves the controller and connects to it, the connection and the server are closed when the test ends
+func dialUserSocket(t *testing.T, liveService *mocks.MockIUserLiveService, maxSubscriptions int) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	return dialUserSocketController(t, NewUserSocketController(liveService, time.Minute, maxSubscriptions, nil, nil,
+		slog.New(slog.DiscardHandler)), nil)
+}
+
+// dialUserSocketController serves the controller behind the middlewares and connects to it with the header
+func dialUserSocketController(t *testing.T, controller *UserSocketController, header http.Header, middlewares ...gin.HandlerFunc) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	router := gin.New()
+	controller.SetupRoutes(router.Group("/api/v1"), middlewares...)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v1/ws", header)
+	if err == nil {
+		t.Cleanup(func() { _ = conn.Close() })
+	}
+	return conn, resp, err
+}
+
+func watchBroker(broker *stream.Broker) func(*context.Context) (*stream.Subscription, error) {
+	return func(*context.Context) (*stream.Subscription, error) {
+		return broker.Subscribe("acme", nil)
+	}
+}
+
+func readLiveMessage(t *testing.T, conn *websocket.Conn) model.LiveMessage {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var message model.LiveMessage
+	require.NoError(t, conn.ReadJSON(&message))
+	return message
+}
+
+func TestUnitUserSocketController(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	broker := stream.NewBroker(0)
+	mockService := mocks.NewMockIUserLiveService(t)
+	mockService.EXPECT().Watch(mock.Anything).RunAndReturn(watchBroker(broker))
+	mockService.EXPECT().Snapshot(mock.Anything, mock.Anything).
+		RunAndReturn(func(query *model.UserQuery, _ *context.Context) ([]*model.UserResponse, error) {
+			if query.UserID != nil {
+				return []*model.UserResponse{{ID: *query.UserID, Name: "Ada", Age: 36}}, nil
+			}
+			return []*model.UserResponse{}, nil
+		})
+	conn, _, err := dialUserSocket(t, mockService, 2)
+	require.NoError(t, err)
+	send := func(message string) model.LiveMessage {
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(message)))
+		return readLiveMessage(t, conn)
+	}
+
+	snapshot := send(`{"type":"subscribe","id":"ada","query":{"user_id":5}}`)
+	assert.Equal(t, model.LiveMessage{Type: model.LiveSnapshot, ID: "ada", Users: []*model.UserResponse{{ID: 5, Name: "Ada", Age: 36}}}, snapshot)
+	assert.Equal(t, model.LiveMessage{Type: model.LiveError, ID: "ada", Error: `subscription "ada" exists`},
+		send(`{"type":"subscribe","id":"ada","query":{"user_id":5}}`))
+	assert.Equal(t, model.LiveMessage{Type: model.LiveSnapshot, ID: "bobs"},
+		send(`{"type":"subscribe","id":"bobs","query":{"filter":{"name":"bob"}}}`))
+	assert.Equal(t, model.LiveMessage{Type: model.LiveError, ID: "more", Error: "at most 2 subscriptions per connection"},
+		send(`{"type":"subscribe","id":"more","query":{}}`))
+
+	broker.Publish(&model.EventMessage{ID: 9, TenantID: "acme", Type: model.UserUpdatedEvent,
+		Payload: json.RawMessage(`{"id":5,"name":"Ada Bobson","email":"ada@example.com","age":36}`)})
+	changes := map[string]model.LiveMessage{}
+	for range 2 {
+		change := readLiveMessage(t, conn)
+		changes[change.ID] = change
+	}
+	user := &model.UserResponse{ID: 5, Name: "Ada Bobson", Email: "ada@example.com", Age: 36}
+	assert.Equal(t, model.LiveMessage{Type: model.LiveUpdated, ID: "ada", User: user, EventID: 9}, changes["ada"])
+	assert.Equal(t, model.LiveMessage{Type: model.LiveAdded, ID: "bobs", User: user, EventID: 9}, changes["bobs"])
+
+	assert.Equal(t, model.LiveMessage{Type: model.LiveUnsubscribed, ID: "ada"}, send(`{"type":"unsubscribe","id":"ada"}`))
+	assert.Equal(t, model.LiveMessage{Type: model.LiveError, ID: "ada", Error: `no subscription "ada"`},
+		send(`{"type":"unsubscribe","id":"ada"}`))
+	assert.Equal(t, model.LiveMessage{Type: model.LiveError, ID: "x", Error: `unknown message type "ping", use subscribe or unsubscribe`},
+		send(`{"type":"ping","id":"x"}`))
+	assert.Equal(t, model.LiveMessage{Type: model.LiveError, Error: "id is required"}, send(`{"type":"subscribe"}`))
+	assert.Equal(t, model.LiveError, send(`{"type":`).Type)
+
+	broker.Publish(&model.EventMessage{ID: 10, TenantID: "acme", Type: model.UserDeletedEvent,
+		Payload: json.RawMessage(`{"id":5,"name":"Ada Bobson","email":"ada@example.com","age":36}`)})
+	assert.Equal(t, model.LiveMessage{Type: model.LiveRemoved, ID: "bobs", User: user, EventID: 10}, readLiveMessage(t, conn),
+		"unsubscribed queries get no changes")
+}
+
+func TestUnitUserSocketControllerClose(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		end  func(broker *stream.Broker)
+		code int
+	}{
+		{"slow consumer", func(broker *stream.Broker) { broker.DropAll(stream.ErrSlowConsumer) }, websocket.CloseTryAgainLater},
+		{"resync", func(broker *stream.Broker) { broker.DropAll(stream.ErrResync) }, websocket.CloseTryAgainLater},
+		{"shutdown", func(broker *stream.Broker) { broker.Close() }, websocket.CloseGoingAway},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			broker := stream.NewBroker(0)
+			mockService := mocks.NewMockIUserLiveService(t)
+			mockService.EXPECT().Watch(mock.Anything).RunAndReturn(watchBroker(broker))
+			conn, _, err := dialUserSocket(t, mockService, 0)
+			require.NoError(t, err)
+			require.Eventually(t, func() bool { return broker.Len() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+			test.end(broker)
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+			_, _, err = conn.ReadMessage()
+			assert.True(t, websocket.IsCloseError(err, test.code), "%v", err)
+		})
+	}
+}
+
+func TestUnitUserSocketControllerForbidden(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	mockService := mocks.NewMockIUserLiveService(t)
+	mockService.EXPECT().Watch(mock.Anything).Return(nil, fmt.Errorf("%w: missing permission users:read", auth.ErrForbidden))
+	_, resp, err := dialUserSocket(t, mockService, 0)
+	assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "the caller is rejected before the upgrade")
+}
+
+// withPrincipal authenticates the requests as the principal
+func withPrincipal(principal *auth.Principal) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+	}
+}
+
+func TestUnitUserSocketControllerCredentials(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		expiresAt      time.Time
+		reauthenticate func(req *http.Request) (*auth.Principal, error)
+		reason         string
+	}{
+		{name: "expired", expiresAt: time.Now().Add(100 * time.Millisecond), reason: "credentials expired"},
+		{name: "revoked", reason: "credentials revoked",
+			reauthenticate: func(*http.Request) (*auth.Principal, error) { return nil, auth.ErrInvalidToken }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			broker := stream.NewBroker(0)
+			mockService := mocks.NewMockIUserLiveService(t)
+			mockService.EXPECT().Watch(mock.Anything).RunAndReturn(watchBroker(broker))
+			controller := NewUserSocketController(mockService, 50*time.Millisecond, 0, nil, test.reauthenticate,
+				slog.New(slog.DiscardHandler))
+			principal := &auth.Principal{Subject: "5", Method: "session", ExpiresAt: test.expiresAt}
+			conn, _, err := dialUserSocketController(t, controller, nil, withPrincipal(principal))
+			require.NoError(t, err)
+
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+			_, _, err = conn.ReadMessage()
+			var closeErr *websocket.CloseError
+			require.ErrorAs(t, err, &closeErr)
+			assert.Equal(t, websocket.ClosePolicyViolation, closeErr.Code)
+			assert.Equal(t, test.reason, closeErr.Text)
+		})
+	}
+}
+
+func TestUnitUserSocketControllerOrigin(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		allowedOrigins []string
+		origin         string
+		allowed        bool
+	}{
+		{name: "No origin", allowed: true},
+		{name: "Allowed origin", allowedOrigins: []string{"https://app.example.com"}, origin: "https://app.example.com", allowed: true},
+		{name: "All origins", allowedOrigins: []string{"*"}, origin: "https://evil.example.com", allowed: true},
+		{name: "Other origin", allowedOrigins: []string{"https://app.example.com"}, origin: "https://evil.example.com"},
+		{name: "Other origin without allowed origins", origin: "https://evil.example.com"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			mockService := mocks.NewMockIUserLiveService(t)
+			mockService.EXPECT().Watch(mock.Anything).RunAndReturn(watchBroker(stream.NewBroker(0)))
+			controller := NewUserSocketController(mockService, time.Minute, 0, test.allowedOrigins, nil,
+				slog.New(slog.DiscardHandler))
+			header := http.Header{}
+			if test.origin != "" {
+				header.Set("Origin", test.origin)
+			}
+			_, resp, err := dialUserSocketController(t, controller, header)
+			if test.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+			require.NotNil(t, resp)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		})
+	}
+}
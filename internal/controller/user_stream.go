@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"crud/internal/model"
+	"crud/internal/service"
+	responseUtil "crud/internal/util/response"
+	"errors"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultHeartbeatInterval is how often idle streams send a comment, so that proxies keep them open
+	DefaultHeartbeatInterval = 15 * time.Second
+	// streamWriteTimeout ends streams whose client does not read what is sent to it
+	streamWriteTimeout = 10 * time.Second
+	// ResetEvent tells a client that changes after its last event id were missed and it has to reload the users
+	ResetEvent = "reset"
+)
+
+// UserStreamController serves the server-sent events stream of the user changes
+type UserStreamController struct {
+	streamService     service.IUserStreamService
+	heartbeatInterval time.Duration
+	allowedOrigins    []string
+	logger            *slog.Logger
+}
+
+func NewUserStreamController(streamService service.IUserStreamService, heartbeatInterval time.Duration, allowedOrigins []string, logger *slog.Logger) *UserStreamController {
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = DefaultHeartbeatInterval
+	}
+	return &UserStreamController{streamService: streamService, heartbeatInterval: heartbeatInterval,
+		allowedOrigins: allowedOrigins, logger: logger}
+}
+
+func (controller *UserStreamController) SetupRoutes(superRoute *gin.RouterGroup, middlewares ...gin.HandlerFunc) {
+	// Same CORS as the other user routes, browsers open the stream with an EventSource
+	superRoute.Group("user", middlewares...).GET("/stream", corsMiddleware(controller.allowedOrigins), controller.Stream)
+}
+
+// Stream streams the user changes as server-sent events
+//
+// @Summary		Streams the user changes
+// @Description	Streams the created, updated and deleted users as server-sent events named after the event type, the data
+// @Description	is the event message. Clients resume after the id in the Last-Event-ID header, or in last_event_id, from
+// @Description	the log of the latest changes; a "reset" event tells them that changes were missed. Idle streams send a
+// @Description	heartbeat comment, clients which do not keep up are disconnected and resume.
+// @Produce		text/event-stream
+// @Param		Last-Event-ID	header		int		false	"ID of the last event received"
+// @Param		last_event_id	query		int		false	"ID of the last event received, for clients which cannot set headers"
+// @Param		types			query		string	false	"Comma separated event types, e.g. user.created,user.deleted"
+// @Success		200		{object}	model.EventMessage
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/user/stream [get]
+func (controller *UserStreamController) Stream(context *gin.Context) {
+	lastEventID, err := lastEventIDParam(context)
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+	var types []string
+	if value := context.Query("types"); value != "" {
+		for _, eventType := range strings.Split(value, ",") {
+			types = append(types, strings.TrimSpace(eventType))
+		}
+	}
+
+	ctx := context.Request.Context()
+	userStream, err := controller.streamService.Open(lastEventID, types, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	defer userStream.Close()
+
+	header := context.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Buffering proxies, e.g. nginx, would hold the events back
+	header.Set("X-Accel-Buffering", "no")
+	context.Status(http.StatusOK)
+	writer := &eventWriter{writer: context.Writer, controller: http.NewResponseController(context.Writer)}
+
+	if userStream.Reset {
+		writer.write(func(w io.Writer) error {
+			return sse.Encode(w, sse.Event{Event: ResetEvent, Data: "{}"})
+		})
+	}
+	for _, event := range userStream.Backlog {
+		writer.write(encodeEvent(event))
+	}
+	writer.write(func(w io.Writer) error {
+		_, err := io.WriteString(w, ": connected\n\n")
+		return err
+	})
+
+	heartbeat := time.NewTicker(controller.heartbeatInterval)
+	defer heartbeat.Stop()
+	for writer.err == nil {
+		select {
+		case event, ok := <-userStream.Events():
+			if !ok {
+				if err = userStream.Err(); err != nil {
+					controller.logger.DebugContext(ctx, "ended user stream", slog.String("reason", err.Error()))
+				}
+				return
+			}
+			if !userStream.InBacklog(event) {
+				writer.write(encodeEvent(event))
+			}
+		case <-heartbeat.C:
+			writer.write(func(w io.Writer) error {
+				_, err := io.WriteString(w, ": heartbeat\n\n")
+				return err
+			})
+		case <-ctx.Done():
+			return
+		}
+	}
+	controller.logger.DebugContext(ctx, "ended user stream", slog.String("reason", writer.err.Error()))
+}
+
+// lastEventIDParam reads the Last-Event-ID header, which browsers send when they reconnect, or the last_event_id
+// query parameter, nil when there is neither
+func lastEventIDParam(context *gin.Context) (*int64, error) {
+	value := context.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = context.Query("last_event_id")
+	}
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return nil, errors.New("last event id must be a non-negative integer")
+	}
+	return &id, nil
+}
+
+func encodeEvent(event *model.EventMessage) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		return sse.Encode(w, sse.Event{Id: strconv.FormatInt(event.ID, 10), Event: event.Type, Data: event})
+	}
+}
+
+// eventWriter flushes every write and fails it when the client does not take it within streamWriteTimeout. The first
+// error sticks, later writes are skipped
+type eventWriter struct {
+	writer     gin.ResponseWriter
+	controller *http.ResponseController
+	err        error
+}
+
+func (writer *eventWriter) write(encode func(w io.Writer) error) {
+	if writer.err != nil {
+		return
+	}
+	// Writers without deadlines, e.g. in tests, block until the client reads
+	if err := writer.controller.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		writer.err = err
+		return
+	}
+	if writer.err = encode(writer.writer); writer.err == nil {
+		writer.err = writer.controller.Flush()
+	}
+}
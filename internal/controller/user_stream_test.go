@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/service"
+	"crud/internal/stream"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readEvent reads the lines of the next event or comment of a server-sent events stream
+func readEvent(t *testing.T, reader *bufio.Reader) []string {
+	t.Helper()
+	lines := make([]string, 0)
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func streamEvent(id int64, eventType string) *model.EventMessage {
+	return &model.EventMessage{ID: id, TenantID: "acme", AggregateType: model.UserAggregate, AggregateID: "5", Type: eventType,
+		Payload: json.RawMessage(`{"id":5}`), OccurredAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func openStream(broker *stream.Broker, reset bool, backlog ...*model.EventMessage) func(*int64, []string, *context.Context) (*stream.Stream, error) {
+	return func(_ *int64, types []string, _ *context.Context) (*stream.Stream, error) {
+		subscription, err := broker.Subscribe("acme", types)
+		if err != nil {
+			return nil, err
+		}
+		userStream := stream.NewStream(subscription, backlog)
+		userStream.Reset = reset
+		return userStream, nil
+	}
+}
+
+func TestUnitUserStreamController(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	broker := stream.NewBroker(0)
+	mockService := mocks.NewMockIUserStreamService(t)
+	lastEventID := int64(5)
+	mockService.EXPECT().
+		Open(&lastEventID, []string{model.UserCreatedEvent, model.UserDeletedEvent}, mock.Anything).
+		RunAndReturn(openStream(broker, false, streamEvent(6, model.UserCreatedEvent)))
+	router := gin.New()
+	routerGroup := router.Group("/api/v1")
+	// The stream route lives next to the user routes
+	NewUserController(nil, nil).SetupRoutes(routerGroup)
+	NewUserStreamController(mockService, 50*time.Millisecond, nil, slog.New(slog.DiscardHandler)).SetupRoutes(routerGroup)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/user/stream?types=user.created,%20user.deleted", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "5")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+	reader := bufio.NewReader(resp.Body)
+
+	backlogEvent := readEvent(t, reader)
+	require.Len(t, backlogEvent, 3)
+	assert.Equal(t, []string{"id:6", "event:user.created"}, backlogEvent[:2])
+	assert.JSONEq(t, `{"id":6,"tenant_id":"acme","aggregate_type":"user","aggregate_id":"5","type":"user.created",
+		"payload":{"id":5},"occurred_at":"2025-03-01T12:00:00Z"}`, strings.TrimPrefix(backlogEvent[2], "data:"))
+	assert.Equal(t, []string{": connected"}, readEvent(t, reader))
+
+	broker.Publish(streamEvent(6, model.UserCreatedEvent))
+	broker.Publish(streamEvent(7, model.UserUpdatedEvent))
+	broker.Publish(streamEvent(8, model.UserDeletedEvent))
+	assert.Equal(t, []string{"id:8", "event:user.deleted"}, readEvent(t, reader)[:2],
+		"live events already sent with the backlog and of other types are skipped")
+	assert.Equal(t, []string{": heartbeat"}, readEvent(t, reader))
+
+	broker.Close()
+	for {
+		if _, err = reader.ReadString('\n'); err != nil {
+			break
+		}
+	}
+	assert.ErrorIs(t, err, io.EOF, "the stream ends when the broker closes")
+}
+
+func TestUnitUserStreamControllerReset(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	broker := stream.NewBroker(0)
+	mockService := mocks.NewMockIUserStreamService(t)
+	mockService.EXPECT().Open(mock.Anything, []string(nil), mock.Anything).RunAndReturn(openStream(broker, true))
+	router := gin.New()
+	NewUserStreamController(mockService, time.Minute, nil, slog.New(slog.DiscardHandler)).SetupRoutes(router.Group("/api/v1"))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/user/stream?last_event_id=1")
+	require.NoError(t, err)
+	reader := bufio.NewReader(resp.Body)
+	assert.Equal(t, []string{"event:" + ResetEvent, "data:{}"}, readEvent(t, reader))
+	assert.Equal(t, []string{": connected"}, readEvent(t, reader))
+	assert.Equal(t, 1, broker.Len())
+
+	require.NoError(t, resp.Body.Close())
+	assert.Eventually(t, func() bool {
+		return broker.Len() == 0
+	}, 5*time.Second, 10*time.Millisecond, "the subscription is closed when the client disconnects")
+}
+
+func TestUnitUserStreamControllerBadRequest(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	mockService := mocks.NewMockIUserStreamService(t)
+	mockService.EXPECT().Open(mock.Anything, []string{"user.renamed"}, mock.Anything).
+		Return(nil, fmt.Errorf("%w: unknown event type", service.ErrInvalidRequest))
+	router := gin.New()
+	NewUserStreamController(mockService, 0, nil, slog.New(slog.DiscardHandler)).SetupRoutes(router.Group("/api/v1"))
+
+	for _, url := range []string{"/api/v1/user/stream?last_event_id=latest", "/api/v1/user/stream?last_event_id=-1",
+		"/api/v1/user/stream?types=user.renamed"} {
+		testRecorder := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		router.ServeHTTP(testRecorder, req)
+		assert.Equal(t, http.StatusBadRequest, testRecorder.Code, url)
+		assert.Equal(t, "application/json; charset=utf-8", testRecorder.Header().Get("Content-Type"), url)
+	}
+}
@@ -1,10 +1,12 @@
 package controller
 
 import (
+	"crud/internal/auth"
 	"crud/internal/mocks"
 	"crud/internal/util/response"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -35,7 +37,7 @@ func TestUnitBadRequestGetUsers(t *testing.T) {
 
 			router := gin.Default()
 			routerGroup := router.Group("/api/v1")
-			controller := NewUserController(nil)
+			controller := NewUserController(nil, nil)
 			controller.SetupRoutes(routerGroup)
 			router.ServeHTTP(testRecorder, req)
 
@@ -66,7 +68,7 @@ func TestUnitInternalErrorGetUsers(t *testing.T) {
 		GetUsers(expectedOffset, expectedLimit, mock.Anything).
 		Return(nil, errors.New(expectedErrorMessage))
 
-	controller := NewUserController(mockService)
+	controller := NewUserController(mockService, nil)
 	controller.SetupRoutes(routerGroup)
 	router.ServeHTTP(testRecorder, req)
 
@@ -76,3 +78,24 @@ func TestUnitInternalErrorGetUsers(t *testing.T) {
 	assert.NoError(t, json.Unmarshal([]byte(responseBody), &statusMessage))
 	assert.Equal(t, expectedErrorMessage, statusMessage.Message)
 }
+
+func TestUnitForbiddenGetUserById(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	routerGroup := router.Group("/api/v1")
+
+	testRecorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/user/5", nil)
+
+	mockService := mocks.NewMockIUserService(t)
+	mockService.EXPECT().
+		GetById(5, mock.Anything).
+		Return(nil, fmt.Errorf("%w: missing permission %s", auth.ErrForbidden, auth.PermissionUsersRead))
+
+	controller := NewUserController(mockService, nil)
+	controller.SetupRoutes(routerGroup)
+	router.ServeHTTP(testRecorder, req)
+
+	assert.Equal(t, http.StatusForbidden, testRecorder.Code)
+}
@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"crud/internal/model"
+	"crud/internal/service"
+	responseUtil "crud/internal/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// WebhookController serves the webhook subscription routes and the delivery log of a subscription
+type WebhookController struct {
+	*Controller[int, model.WebhookSubscriptionRequest, model.WebhookSubscriptionResponse]
+	webhookService service.IWebhookService
+}
+
+func NewWebhookController(webhookService service.IWebhookService) *WebhookController {
+	return &WebhookController{
+		Controller:     NewController[int, model.WebhookSubscriptionRequest, model.WebhookSubscriptionResponse]("webhook", webhookService, ParseIntID),
+		webhookService: webhookService,
+	}
+}
+
+func (controller *WebhookController) SetupRoutes(superRoute *gin.RouterGroup, middlewares ...gin.HandlerFunc) {
+	webhookRouter := superRoute.Group("webhook", middlewares...)
+	{
+		webhookRouter.GET("/", controller.GetAll)
+		webhookRouter.GET("/:id", controller.GetById)
+		webhookRouter.POST("/", controller.Create)
+		webhookRouter.PUT("/:id", controller.Update)
+		webhookRouter.DELETE("/:id", controller.Delete)
+		webhookRouter.GET("/:id/deliveries", controller.GetDeliveries)
+		webhookRouter.POST("/:id/deliveries/:deliveryId/replay", controller.ReplayDelivery)
+	}
+}
+
+// GetAll gets list of webhook subscriptions
+//
+// @Summary		Gets list of webhook subscriptions
+// @Produce		json
+// @Param		offset	query		int			false	"Offset"
+// @Param		limit	query		int			false	"Limit"
+// @Success		200		{array}		model.WebhookSubscriptionResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/webhook/ [get]
+func (controller *WebhookController) GetAll(context *gin.Context) {
+	controller.Controller.GetAll(context)
+}
+
+// GetById gets webhook subscription by id
+//
+// @Summary		Gets webhook subscription by id
+// @Produce		json
+// @Param		id		path		int		true	"Subscription ID"
+// @Success		200		{object}	model.WebhookSubscriptionResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		404		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/webhook/{id} [get]
+func (controller *WebhookController) GetById(context *gin.Context) {
+	controller.Controller.GetById(context)
+}
+
+// Create creates a webhook subscription
+//
+// @Summary		Creates a webhook subscription
+// @Description	Creates a webhook subscription, the events of the listed types are posted to the URL signed with the secret.
+// @Description	The secret is never returned
+// @Accept		json
+// @Produce		json
+// @Param		subscription	body		model.WebhookSubscriptionRequest	true	"New subscription"
+// @Success		201		{object}	model.WebhookSubscriptionResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/webhook/ [post]
+func (controller *WebhookController) Create(context *gin.Context) {
+	controller.Controller.Create(context)
+}
+
+// Update updates a webhook subscription
+//
+// @Summary		Updates a webhook subscription
+// @Description	Updates a webhook subscription, the secret is replaced too
+// @Accept		json
+// @Produce		json
+// @Param		id				path		int									true	"Subscription ID"
+// @Param		subscription	body		model.WebhookSubscriptionRequest	true	"Subscription new data"
+// @Success		200		{object}	model.WebhookSubscriptionResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/webhook/{id} [put]
+func (controller *WebhookController) Update(context *gin.Context) {
+	controller.Controller.Update(context)
+}
+
+// Delete deletes a webhook subscription together with its deliveries
+//
+// @Summary		Deletes a webhook subscription
+// @Description	Deletes a webhook subscription together with its deliveries
+// @Produce		json
+// @Param		id		path		int		true	"Subscription ID"
+// @Success		200		{object}	model.WebhookSubscriptionResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/webhook/{id} [delete]
+func (controller *WebhookController) Delete(context *gin.Context) {
+	controller.Controller.Delete(context)
+}
+
+// GetDeliveries gets the delivery log of a webhook subscription
+//
+// @Summary		Gets the delivery log of a webhook subscription
+// @Description	Gets the deliveries of a webhook subscription, newest first
+// @Produce		json
+// @Param		id		path		int			true	"Subscription ID"
+// @Param		offset	query		int			false	"Offset"
+// @Param		limit	query		int			false	"Limit"
+// @Success		200		{array}		model.WebhookDeliveryResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/webhook/{id}/deliveries [get]
+func (controller *WebhookController) GetDeliveries(context *gin.Context) {
+	id, err := responseUtil.GetIntParam(context, "id")
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+	offset, limit, err := pageParams(context)
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	deliveries, err := controller.webhookService.GetDeliveries(id, offset, limit, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	context.JSON(http.StatusOK, deliveries)
+}
+
+// ReplayDelivery sends a webhook delivery again
+//
+// @Summary		Sends a webhook delivery again
+// @Description	Makes a delivery pending again with a fresh count of attempts, also when it succeeded or is dead
+// @Produce		json
+// @Param		id			path		int		true	"Subscription ID"
+// @Param		deliveryId	path		int		true	"Delivery ID"
+// @Success		202		{object}	model.WebhookDeliveryResponse
+// @Failure		400		{object}	response.HTTPStatusMessage
+// @Failure		404		{object}	response.HTTPStatusMessage
+// @Failure		403		{object}	response.HTTPStatusMessage
+// @Security	BearerAuth
+// @Router		/webhook/{id}/deliveries/{deliveryId}/replay [post]
+func (controller *WebhookController) ReplayDelivery(context *gin.Context) {
+	id, err := responseUtil.GetIntParam(context, "id")
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+	deliveryID, err := strconv.ParseInt(context.Param("deliveryId"), 10, 64)
+	if err != nil {
+		responseUtil.NewError(context, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Request.Context()
+	delivery, err := controller.webhookService.ReplayDelivery(id, deliveryID, &ctx)
+	if err != nil {
+		responseUtil.NewError(context, errorStatus(err, http.StatusNotFound), err)
+		return
+	}
+	context.JSON(http.StatusAccepted, delivery)
+}
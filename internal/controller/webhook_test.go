@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitWebhookControllerRoutes(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	mockService := mocks.NewMockIWebhookService(t)
+	mockService.EXPECT().
+		GetDeliveries(3, 0, DefaultLimit, mock.Anything).
+		Return([]*model.WebhookDeliveryResponse{{ID: 8, SubscriptionID: 3, Status: model.WebhookDeliveryDead}}, nil)
+	mockService.EXPECT().
+		ReplayDelivery(3, int64(8), mock.Anything).
+		Return(&model.WebhookDeliveryResponse{ID: 8, SubscriptionID: 3, Status: model.WebhookDeliveryPending}, nil)
+	mockService.EXPECT().
+		ReplayDelivery(3, int64(9), mock.Anything).
+		Return(nil, pgx.ErrNoRows)
+	router := gin.New()
+	NewWebhookController(mockService).SetupRoutes(router.Group("/api/v1"))
+
+	testRecorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/webhook/3/deliveries", nil)
+	router.ServeHTTP(testRecorder, req)
+	require.Equal(t, http.StatusOK, testRecorder.Code)
+	deliveries := make([]model.WebhookDeliveryResponse, 0)
+	assert.NoError(t, json.Unmarshal(testRecorder.Body.Bytes(), &deliveries))
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, model.WebhookDeliveryDead, deliveries[0].Status)
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/webhook/3/deliveries/8/replay", nil)
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusAccepted, testRecorder.Code)
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/webhook/3/deliveries/9/replay", nil)
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusNotFound, testRecorder.Code)
+
+	testRecorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/webhook/3/deliveries/latest/replay", nil)
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusBadRequest, testRecorder.Code)
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitRequireAdminTokenMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/admin/loglevel", RequireAdminTokenMiddleware("0123456789abcdef"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{"Token", "Bearer 0123456789abcdef", http.StatusOK},
+		{"Wrong token", "Bearer 0123456789abcdeg", http.StatusUnauthorized},
+		{"Prefix of the token", "Bearer 0123", http.StatusUnauthorized},
+		{"Other scheme", "ApiKey 0123456789abcdef", http.StatusUnauthorized},
+		{"Anonymous", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRecorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/admin/loglevel", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			router.ServeHTTP(testRecorder, req)
+			assert.Equal(t, tt.status, testRecorder.Code)
+		})
+	}
+}
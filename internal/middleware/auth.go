@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"crud/internal/auth"
+	responseUtil "crud/internal/util/response"
+	"errors"
+	"github.com/gin-gonic/gin"
+	slogctx "github.com/veqryn/slog-context"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// AccessTokenParam carries the bearer token of WebSocket handshakes, browsers cannot set their headers
+const AccessTokenParam = "access_token"
+
+var (
+	ErrUnauthenticated       = errors.New("authentication required")
+	ErrUnsupportedAuthScheme = errors.New("unsupported authorization scheme")
+)
+
+// AuthenticateMiddleware validates the Authorization header with the authenticator registered for
+// its scheme (case-insensitive) and stores the principal in the request context. Requests without
+// the header pass through anonymously, RequireAuthMiddleware rejects them where needed. WebSocket handshakes
+// without the header may pass a bearer token in AccessTokenParam instead.
+func AuthenticateMiddleware(authenticators map[string]auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := Authenticate(authenticators, c.Request)
+		if err != nil {
+			abortUnauthenticated(c, err)
+			return
+		}
+		if principal == nil {
+			c.Next()
+			return
+		}
+
+		ctx := auth.WithPrincipal(c.Request.Context(), principal)
+		ctx = slogctx.Append(ctx, slog.String("subject", principal.Subject))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// Authenticate validates the credentials of req like AuthenticateMiddleware, it returns no principal for anonymous
+// requests. Long-lived connections call it again to notice revoked sessions and api keys
+func Authenticate(authenticators map[string]auth.Authenticator, req *http.Request) (*auth.Principal, error) {
+	header := req.Header.Get("Authorization")
+	if token := req.URL.Query().Get(AccessTokenParam); header == "" && token != "" && isWebSocketHandshake(req) {
+		header = "Bearer " + token
+	}
+	if header == "" {
+		return nil, nil
+	}
+	scheme, credentials, _ := strings.Cut(header, " ")
+	authenticator, ok := authenticators[strings.ToLower(scheme)]
+	if !ok {
+		return nil, ErrUnsupportedAuthScheme
+	}
+	return authenticator.Authenticate(req.Context(), strings.TrimSpace(credentials))
+}
+
+// RequireAuthMiddleware rejects requests that were not authenticated by AuthenticateMiddleware
+func RequireAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := auth.PrincipalFromContext(c.Request.Context()); !ok {
+			abortUnauthenticated(c, ErrUnauthenticated)
+			return
+		}
+		c.Next()
+	}
+}
+
+func isWebSocketHandshake(req *http.Request) bool {
+	return strings.EqualFold(req.Header.Get("Upgrade"), "websocket")
+}
+
+func abortUnauthenticated(c *gin.Context, err error) {
+	c.Header("WWW-Authenticate", `Bearer realm="api", ApiKey realm="api"`)
+	responseUtil.AbortWithError(c, http.StatusUnauthorized, err)
+}
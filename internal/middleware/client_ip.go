@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"crud/internal/util/request"
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIPMiddleware resolves the client IP once per request and stores it in the request context
+func ClientIPMiddleware(resolver *request.ClientIPResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := request.WithClientIP(c.Request.Context(), resolver.Resolve(c.Request))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"crud/internal/util/log"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// DebugLogHeader carries a token signed with the debug log secret, see log.SignDebugToken
+const DebugLogHeader = "X-Debug-Log"
+
+// DebugLogMiddleware logs requests with a valid debug token at debug level, so that one request
+// can be traced without lowering the level of the whole service
+func DebugLogMiddleware(secret []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(DebugLogHeader)
+		if token != "" && log.VerifyDebugToken(secret, token, time.Now()) {
+			c.Request = c.Request.WithContext(log.WithDebug(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
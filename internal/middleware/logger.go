@@ -3,20 +3,19 @@ package middleware
 import (
 	"crud/internal/util/request"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	slogctx "github.com/veqryn/slog-context"
 	"log/slog"
 	"time"
 )
 
-// JSONLogMiddleware logs a gin HTTP request in JSON format, with some additional custom key/values
-func JSONLogMiddleware() gin.HandlerFunc {
+// JSONLogMiddleware logs a gin HTTP request in JSON format, with some additional custom key/values.
+// The message names the route, so that sampling keeps the lines of every route
+func JSONLogMiddleware(logger *slog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Start timer
 		start := time.Now()
 
 		ctx := slogctx.Append(c.Request.Context(),
-			slog.String("request_id", uuid.NewString()),
 			slog.String("client_ip", request.GetClientIP(c)),
 			slog.String("method", c.Request.Method),
 			slog.String("path", c.Request.URL.Path),
@@ -30,14 +29,19 @@ func JSONLogMiddleware() gin.HandlerFunc {
 		// Stop timer
 		duration := request.GetDurationInMilliseconds(start)
 
-		logger := slog.With(
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched route"
+		}
+		attrs := []any{
 			slog.Float64("duration", duration),
-			slog.Int("status", c.Writer.Status()))
-
+			slog.Int("status", c.Writer.Status()),
+		}
 		if c.Writer.Status() >= 500 {
-			logger.ErrorContext(ctx, c.Errors.String())
+			logger.ErrorContext(ctx, "request failed: "+c.Request.Method+" "+route,
+				append(attrs, slog.String("error", c.Errors.String()))...)
 		} else {
-			logger.InfoContext(ctx, "")
+			logger.InfoContext(ctx, "request completed: "+c.Request.Method+" "+route, attrs...)
 		}
 	}
 }
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitJSONLogMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	buffer := &bytes.Buffer{}
+	router := gin.New()
+	router.Use(JSONLogMiddleware(slog.New(slog.NewJSONHandler(buffer, nil))))
+	router.GET("/api/v1/user/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/v1/fail", func(c *gin.Context) {
+		_ = c.Error(assert.AnError)
+		c.Status(http.StatusInternalServerError)
+	})
+
+	lines := make([]map[string]any, 0)
+	for _, path := range []string{"/api/v1/user/7", "/api/v1/fail", "/missing"} {
+		buffer.Reset()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &line), "the injected logger writes the access line")
+		lines = append(lines, line)
+	}
+
+	assert.Equal(t, "request completed: GET /api/v1/user/:id", lines[0]["msg"], "the message names the route template")
+	assert.Equal(t, "INFO", lines[0]["level"])
+	assert.EqualValues(t, http.StatusOK, lines[0]["status"])
+	assert.Equal(t, "request failed: GET /api/v1/fail", lines[1]["msg"])
+	assert.Equal(t, "ERROR", lines[1]["level"])
+	assert.Contains(t, lines[1]["error"], assert.AnError.Error())
+	assert.Equal(t, "request completed: GET unmatched route", lines[2]["msg"])
+}
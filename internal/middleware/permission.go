@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"crud/internal/auth"
+	responseUtil "crud/internal/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// RequirePermissionMiddleware rejects callers without the permission with 403, it guards whole route groups
+// such as the admin endpoints
+func RequirePermissionMiddleware(policy *auth.Policy, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := policy.Authorize(c.Request.Context(), permission, c.FullPath(), nil); err != nil {
+			responseUtil.AbortWithError(c, http.StatusForbidden, err)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"crud/internal/auth"
+	"crud/internal/ratelimit"
+	"crud/internal/tenant"
+	"crud/internal/util/log"
+	"crud/internal/util/request"
+	responseUtil "crud/internal/util/response"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+
+// RateLimitKey returns the identity a client is rate limited by
+type RateLimitKey func(c *gin.Context) string
+
+// ClientIPRateLimitKey limits clients by the IP resolved by ClientIPMiddleware
+func ClientIPRateLimitKey(c *gin.Context) string {
+	return "ip:" + request.GetClientIP(c)
+}
+
+// AuthenticationRateLimitKey limits clients by IP ahead of the authentication, apart from the buckets of
+// ClientIPRateLimitKey
+func AuthenticationRateLimitKey(c *gin.Context) string {
+	return "authentication:" + ClientIPRateLimitKey(c)
+}
+
+// ClientRateLimitKey limits authenticated callers by tenant and subject and anonymous ones by client IP.
+// Only verified identities are used, made-up credentials are rejected before they get a bucket.
+func ClientRateLimitKey(c *gin.Context) string {
+	if principal, ok := auth.PrincipalFromContext(c.Request.Context()); ok {
+		tenantID, _ := tenant.FromContext(c.Request.Context())
+		return principal.Method + ":" + tenantID + ":" + principal.Subject
+	}
+	return ClientIPRateLimitKey(c)
+}
+
+// RateLimitMiddleware rejects requests over the limit with 429 and reports the bucket state in
+// RateLimit-* headers. Store failures are logged and let the request through.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, key RateLimitKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, result, err := limiter.Take(c.Request.Context(), key(c), c.Request.Method, c.FullPath())
+		if err != nil {
+			log.WarnContext(c.Request.Context(), "rate limit store failed",
+				slog.String("error", err.Error()))
+			c.Next()
+			return
+		}
+		if limit.Burst == 0 {
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Header("RateLimit-Policy", limit.Policy())
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+			responseUtil.AbortWithProblem(c, http.StatusTooManyRequests, ErrRateLimitExceeded)
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/ratelimit"
+	"crud/internal/tenant"
+	responseUtil "crud/internal/util/response"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitRateLimitMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	limit := ratelimit.Limit{Burst: 1, Period: time.Minute}
+	router := gin.New()
+	router.Use(RateLimitMiddleware(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), &limit, nil), ClientIPRateLimitKey))
+	router.GET("/api/v1/user", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	testRecorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/user", nil)
+	router.ServeHTTP(testRecorder, req)
+	assert.Equal(t, http.StatusOK, testRecorder.Code)
+	assert.Equal(t, "0", testRecorder.Header().Get("RateLimit-Remaining"))
+
+	testRecorder = httptest.NewRecorder()
+	router.ServeHTTP(testRecorder, req)
+	require.Equal(t, http.StatusTooManyRequests, testRecorder.Code)
+	assert.Equal(t, responseUtil.ProblemContentType, testRecorder.Header().Get("Content-Type"))
+	assert.NotEmpty(t, testRecorder.Header().Get("Retry-After"))
+	var problem responseUtil.ProblemDetails
+	require.NoError(t, json.Unmarshal(testRecorder.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusTooManyRequests, problem.Status)
+	assert.Equal(t, "Too Many Requests", problem.Title)
+	assert.Equal(t, ErrRateLimitExceeded.Error(), problem.Detail)
+	assert.Equal(t, "/api/v1/user", problem.Instance)
+}
+
+func TestUnitClientRateLimitKey(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	key := func(tenantID string) string {
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Method: "jwt", Subject: "7"})
+		if tenantID != "" {
+			ctx = tenant.WithTenant(ctx, tenantID)
+		}
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequestWithContext(ctx, http.MethodGet, "/api/v1/user", nil)
+		return ClientRateLimitKey(c)
+	}
+
+	assert.Equal(t, "jwt:acme:7", key("acme"))
+	assert.NotEqual(t, key("acme"), key("globex"), "the same subject has a bucket per tenant")
+	assert.Equal(t, "jwt::7", key(""))
+}
+
+func TestUnitAuthenticationRateLimitKey(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/user", nil)
+	assert.NotEqual(t, ClientIPRateLimitKey(c), AuthenticationRateLimitKey(c))
+}
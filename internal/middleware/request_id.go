@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"crud/internal/util/request"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	slogctx "github.com/veqryn/slog-context"
+	"log/slog"
+)
+
+// RequestIDMiddleware takes the request id from the first valid inbound header or generates a new one.
+// The id is stored in the request context and echoed back in the first configured header.
+func RequestIDMiddleware(headers []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := ""
+		for _, header := range headers {
+			if value := c.GetHeader(header); request.IsValidRequestID(value) {
+				requestID = value
+				break
+			}
+		}
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		ctx := request.WithRequestID(c.Request.Context(), requestID)
+		ctx = slogctx.Append(ctx, slog.String("request_id", requestID))
+		c.Request = c.Request.WithContext(ctx)
+		if len(headers) > 0 {
+			c.Header(headers[0], requestID)
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"crud/internal/util/request"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUnitRequestIDMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	headers := []string{"X-Request-ID", "X-Correlation-ID"}
+	tests := []struct {
+		name       string
+		header     string
+		value      string
+		expectedID string
+	}{
+		{"Request id is taken from primary header", "X-Request-ID", "abc-123", "abc-123"},
+		{"Request id is taken from secondary header", "X-Correlation-ID", "corr.42", "corr.42"},
+		{"Invalid request id is replaced", "X-Request-ID", "bad id\n", ""},
+		{"Too long request id is replaced", "X-Request-ID", strings.Repeat("a", request.MaxRequestIDLength+1), ""},
+		{"Missing request id is generated", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contextID string
+			router := gin.New()
+			router.Use(RequestIDMiddleware(headers))
+			router.GET("/", func(c *gin.Context) {
+				contextID = request.GetRequestID(c.Request.Context())
+			})
+
+			testRecorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			router.ServeHTTP(testRecorder, req)
+
+			responseID := testRecorder.Header().Get("X-Request-ID")
+			assert.Equal(t, contextID, responseID)
+			if tt.expectedID != "" {
+				assert.Equal(t, tt.expectedID, responseID)
+			} else {
+				assert.NotEqual(t, tt.value, responseID)
+				assert.True(t, request.IsValidRequestID(responseID))
+			}
+		})
+	}
+}
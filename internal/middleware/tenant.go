@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/tenant"
+	responseUtil "crud/internal/util/response"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	slogctx "github.com/veqryn/slog-context"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TenantResolver finds the tenant of a request. The claim of the authenticated caller wins, a request naming
+// another tenant in the header or the subdomain is rejected, and so are credentials without the claim.
+// Anonymous requests are scoped to the tenant they name
+type TenantResolver struct {
+	// Claim is the principal claim naming the tenant, claims are ignored when it is empty
+	Claim string
+	// Header is the request header naming the tenant, e.g. X-Tenant-ID, it is ignored when empty
+	Header string
+	// BaseDomain resolves the tenant from the first label of the hosts below it, e.g. acme for acme.example.com
+	BaseDomain string
+	// Default is the tenant of requests naming none, they are rejected when it is empty
+	Default string
+	// Exists reports whether the tenant was provisioned, unknown tenants are rejected. Every tenant is accepted
+	// when it is nil
+	Exists func(ctx context.Context, tenantID string) (bool, error)
+}
+
+// Resolve returns the tenant of the request
+func (resolver *TenantResolver) Resolve(c *gin.Context) (string, error) {
+	requested := ""
+	if resolver.Header != "" {
+		requested = strings.TrimSpace(c.GetHeader(resolver.Header))
+	}
+	if requested == "" && resolver.BaseDomain != "" {
+		requested = subdomain(c.Request.Host, resolver.BaseDomain)
+	}
+
+	if principal, ok := auth.PrincipalFromContext(c.Request.Context()); ok && resolver.Claim != "" {
+		if value, found := principal.Claims[resolver.Claim]; found {
+			claimed, isString := value.(string)
+			if !isString || !tenant.IsValid(claimed) {
+				return "", fmt.Errorf("%w in the %s claim", tenant.ErrInvalidTenant, resolver.Claim)
+			}
+			if requested != "" && requested != claimed {
+				return "", tenant.ErrTenantMismatch
+			}
+			return claimed, nil
+		}
+		return "", fmt.Errorf("%w: the %s claim is missing", tenant.ErrTenantMismatch, resolver.Claim)
+	}
+
+	if requested == "" {
+		requested = resolver.Default
+	}
+	if requested == "" {
+		return "", tenant.ErrMissingTenant
+	}
+	if !tenant.IsValid(requested) {
+		return "", fmt.Errorf("%w %q", tenant.ErrInvalidTenant, requested)
+	}
+	return requested, nil
+}
+
+// resolveKnown is Resolve followed by the Exists check
+func (resolver *TenantResolver) resolveKnown(c *gin.Context) (string, error) {
+	tenantID, err := resolver.Resolve(c)
+	if err != nil || resolver.Exists == nil {
+		return tenantID, err
+	}
+	exists, err := resolver.Exists(c.Request.Context(), tenantID)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("%w %q", tenant.ErrUnknownTenant, tenantID)
+	}
+	return tenantID, nil
+}
+
+// subdomain returns the first label of host when host is directly below baseDomain
+func subdomain(host, baseDomain string) string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	label, found := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(baseDomain))
+	if !found || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
+
+// TenantMiddleware scopes the request context to the tenant of the request, see TenantResolver.
+// It runs after the authentication middleware so that the claims of the caller are known
+func TenantMiddleware(resolver *TenantResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, err := resolver.resolveKnown(c)
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, tenant.ErrTenantMismatch):
+				status = http.StatusForbidden
+			case errors.Is(err, tenant.ErrUnknownTenant):
+				status = http.StatusNotFound
+			case errors.Is(err, tenant.ErrMissingTenant), errors.Is(err, tenant.ErrInvalidTenant):
+				status = http.StatusBadRequest
+			}
+			responseUtil.AbortWithError(c, status, err)
+			return
+		}
+
+		ctx := tenant.WithTenant(c.Request.Context(), tenantID)
+		ctx = slogctx.Append(ctx, slog.String("tenant", tenantID))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// RequestedTenantMiddleware scopes the request context to the tenant the request names ahead of the authentication,
+// so that credentials stored per tenant, e.g. sessions in the schema per tenant mode, are looked up in the tenant.
+// Requests naming no tenant, or an invalid or unknown one, continue unscoped and TenantMiddleware decides on them
+func RequestedTenantMiddleware(resolver *TenantResolver) gin.HandlerFunc {
+	requested := *resolver
+	requested.Claim = ""
+	return func(c *gin.Context) {
+		if tenantID, err := requested.resolveKnown(c); err == nil {
+			c.Request = c.Request.WithContext(tenant.WithTenant(c.Request.Context(), tenantID))
+		}
+		c.Next()
+	}
+}
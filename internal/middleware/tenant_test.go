@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/tenant"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitTenantMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	resolver := &TenantResolver{Claim: tenant.ClaimName, Header: "X-Tenant-ID", BaseDomain: "example.com"}
+	// withoutClaim authenticates the request with a principal lacking the tenant claim
+	withoutClaim := struct{}{}
+	tests := []struct {
+		name           string
+		host           string
+		header         string
+		claim          any
+		expectedStatus int
+		expectedTenant string
+	}{
+		{"Tenant is taken from the header", "localhost:8080", "acme", nil, http.StatusOK, "acme"},
+		{"Tenant is taken from the subdomain", "globex.example.com:8080", "", nil, http.StatusOK, "globex"},
+		{"Header wins over the subdomain", "globex.example.com", "acme", nil, http.StatusOK, "acme"},
+		{"Nested subdomains are ignored", "a.globex.example.com", "", nil, http.StatusBadRequest, ""},
+		{"Tenant is taken from the claim", "localhost", "", "acme", http.StatusOK, "acme"},
+		{"Claim matching the header", "localhost", "acme", "acme", http.StatusOK, "acme"},
+		{"Claim differing from the header", "localhost", "globex", "acme", http.StatusForbidden, ""},
+		{"Claim differing from the subdomain", "globex.example.com", "", "acme", http.StatusForbidden, ""},
+		{"Invalid claim", "localhost", "", 7, http.StatusBadRequest, ""},
+		{"Principal without the claim", "localhost", "acme", withoutClaim, http.StatusForbidden, ""},
+		{"Invalid header", "localhost", "Acme Corp", nil, http.StatusBadRequest, ""},
+		{"Missing tenant", "localhost", "", nil, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contextTenant string
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.claim != nil {
+					principal := &auth.Principal{Subject: "7", Method: "jwt", Claims: map[string]any{}}
+					if tt.claim != withoutClaim {
+						principal.Claims[tenant.ClaimName] = tt.claim
+					}
+					c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+				}
+			})
+			router.Use(TenantMiddleware(resolver))
+			router.GET("/", func(c *gin.Context) {
+				contextTenant, _ = tenant.FromContext(c.Request.Context())
+			})
+
+			testRecorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tt.host
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			router.ServeHTTP(testRecorder, req)
+
+			assert.Equal(t, tt.expectedStatus, testRecorder.Code)
+			assert.Equal(t, tt.expectedTenant, contextTenant)
+		})
+	}
+}
+
+func TestUnitTenantMiddlewareDefault(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	var contextTenant string
+	router := gin.New()
+	router.Use(TenantMiddleware(&TenantResolver{Default: tenant.DefaultTenant}))
+	router.GET("/", func(c *gin.Context) {
+		contextTenant, _ = tenant.FromContext(c.Request.Context())
+	})
+
+	testRecorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	router.ServeHTTP(testRecorder, req)
+
+	assert.Equal(t, http.StatusOK, testRecorder.Code)
+	assert.Equal(t, tenant.DefaultTenant, contextTenant, "without a header configured the header is ignored")
+}
+
+func TestUnitTenantMiddlewareProvisionedTenants(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	resolver := &TenantResolver{
+		Claim:  tenant.ClaimName,
+		Header: "X-Tenant-ID",
+		Exists: func(_ context.Context, tenantID string) (bool, error) {
+			if tenantID == "broken" {
+				return false, errors.New("connection refused")
+			}
+			return tenantID == "acme", nil
+		},
+	}
+	tests := []struct {
+		name           string
+		header         string
+		expectedStatus int
+		expectedBefore string
+		expectedTenant string
+	}{
+		{"Provisioned tenant", "acme", http.StatusOK, "acme", "acme"},
+		{"Unknown tenant", "globex", http.StatusNotFound, "", ""},
+		{"Failing lookup", "broken", http.StatusInternalServerError, "", ""},
+		{"Missing tenant", "", http.StatusBadRequest, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var beforeAuthentication, contextTenant string
+			router := gin.New()
+			router.Use(RequestedTenantMiddleware(resolver))
+			router.Use(func(c *gin.Context) {
+				beforeAuthentication, _ = tenant.FromContext(c.Request.Context())
+			})
+			router.Use(TenantMiddleware(resolver))
+			router.GET("/", func(c *gin.Context) {
+				contextTenant, _ = tenant.FromContext(c.Request.Context())
+			})
+
+			testRecorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			router.ServeHTTP(testRecorder, req)
+
+			assert.Equal(t, tt.expectedStatus, testRecorder.Code)
+			assert.Equal(t, tt.expectedBefore, beforeAuthentication)
+			assert.Equal(t, tt.expectedTenant, contextTenant)
+		})
+	}
+}
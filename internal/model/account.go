@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required,max=128"`
+}
+
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"token_type" example:"Bearer"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required,max=128"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,max=128"`
+}
+
+type SetPasswordRequest struct {
+	Password string `json:"password" binding:"required,min=8,max=128"`
+}
+
+// CredentialModel holds the password of a user, it is never part of a response
+type CredentialModel struct {
+	UserID         int
+	PasswordHash   string
+	FailedAttempts int
+	LockedUntil    *time.Time
+}
+
+type SessionModel struct {
+	ID     int
+	UserID int
+	// TenantID is the tenant the user logged in to, requests with the session are scoped to it
+	TenantID  string
+	TokenHash []byte
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+type APIKeyResponse struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse is the only response that contains the plaintext key
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key" example:"crud_3f9a1c2b_Zm9vYmFyYmF6cXV4cXV1eGZvb2Jhcg"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKeyModel struct {
+	ID         int
+	TenantID   string
+	Name       string
+	Prefix     string
+	SecretHash []byte
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func APIKeyModelToAPIKeyResponse(apiKeyModel *APIKeyModel) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         apiKeyModel.ID,
+		Name:       apiKeyModel.Name,
+		Prefix:     apiKeyModel.Prefix,
+		Scopes:     apiKeyModel.Scopes,
+		ExpiresAt:  apiKeyModel.ExpiresAt,
+		LastUsedAt: apiKeyModel.LastUsedAt,
+		RevokedAt:  apiKeyModel.RevokedAt,
+		CreatedAt:  apiKeyModel.CreatedAt,
+	}
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// JobStatuses lists the statuses of the jobs, the jobs can be listed by status
+var JobStatuses = []string{JobPending, JobRunning, JobSucceeded, JobFailed}
+
+// EnqueueJobRequest adds a background job, the type names its handler
+type EnqueueJobRequest struct {
+	Type    string
+	Payload json.RawMessage
+	// RunAt delays the job, it runs as soon as possible when it is zero
+	RunAt time.Time
+	// UniqueKey skips the job while a pending or running job of the type has the same key
+	UniqueKey string
+}
+
+type JobResponse struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload" swaggertype:"object"`
+	Status      string          `json:"status" enums:"pending,running,succeeded,failed"`
+	UniqueKey   *string         `json:"unique_key,omitempty"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	TimeoutMs   int64           `json:"timeout_ms"`
+	RunAt       time.Time       `json:"run_at"`
+	LastError   *string         `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+}
+
+type JobModel struct {
+	ID       int64
+	TenantID string
+	Type     string
+	Payload  json.RawMessage
+	Status   string
+	// UniqueKey is held while the job is pending or running
+	UniqueKey *string
+	// Attempts counts the runs, including the one in progress while the job is running
+	Attempts    int
+	MaxAttempts int
+	// Timeout bounds a run, the job is leased to its worker until LockedUntil
+	Timeout     time.Duration
+	RunAt       time.Time
+	LockedUntil *time.Time
+	LastError   *string
+	CreatedAt   time.Time
+	FinishedAt  *time.Time
+}
+
+func JobModelToJobResponse(job *JobModel) *JobResponse {
+	return &JobResponse{
+		ID:          job.ID,
+		Type:        job.Type,
+		Payload:     job.Payload,
+		Status:      job.Status,
+		UniqueKey:   job.UniqueKey,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		TimeoutMs:   job.Timeout.Milliseconds(),
+		RunAt:       job.RunAt,
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt,
+		FinishedAt:  job.FinishedAt,
+	}
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type LogLevelResponse struct {
+	Level string `json:"level" example:"INFO"`
+}
+
+type SetLogLevelRequest struct {
+	Level string `json:"level" binding:"required" example:"debug"`
+}
+
+type DebugTokenRequest struct {
+	// TTL is how long the token is valid, e.g. "15m", at most one hour
+	TTL string `json:"ttl" binding:"required" example:"15m"`
+}
+
+type DebugTokenResponse struct {
+	Header    string    `json:"header" example:"X-Debug-Log"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
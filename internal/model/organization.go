@@ -0,0 +1,111 @@
+package model
+
+import "time"
+
+// Roles of the members of an organization, every organization keeps at least one owner
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleAdmin  = "admin"
+	OrganizationRoleMember = "member"
+)
+
+type OrganizationResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type OrganizationModel struct {
+	ID        int
+	Name      string
+	CreatedAt time.Time
+}
+
+func OrganizationRequestToOrganizationModel(id int, request *OrganizationRequest) *OrganizationModel {
+	return &OrganizationModel{ID: id, Name: request.Name}
+}
+
+func OrganizationModelToOrganizationResponse(organizationModel *OrganizationModel) *OrganizationResponse {
+	return &OrganizationResponse{
+		ID:        organizationModel.ID,
+		Name:      organizationModel.Name,
+		CreatedAt: organizationModel.CreatedAt,
+	}
+}
+
+type AddMemberRequest struct {
+	UserID int    `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required" enums:"owner,admin,member"`
+}
+
+type MembershipResponse struct {
+	OrganizationID int       `json:"organization_id"`
+	UserID         int       `json:"user_id"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type MembershipModel struct {
+	OrganizationID int
+	UserID         int
+	Role           string
+	CreatedAt      time.Time
+}
+
+func MembershipModelToMembershipResponse(membershipModel *MembershipModel) *MembershipResponse {
+	return &MembershipResponse{
+		OrganizationID: membershipModel.OrganizationID,
+		UserID:         membershipModel.UserID,
+		Role:           membershipModel.Role,
+		CreatedAt:      membershipModel.CreatedAt,
+	}
+}
+
+// MemberResponse is a user listed as member of an organization
+type MemberResponse struct {
+	UserID    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MemberModel struct {
+	UserID    int
+	Name      string
+	Email     string
+	Role      string
+	CreatedAt time.Time
+}
+
+func MemberModelToMemberResponse(memberModel *MemberModel) *MemberResponse {
+	return &MemberResponse{
+		UserID:    memberModel.UserID,
+		Name:      memberModel.Name,
+		Email:     memberModel.Email,
+		Role:      memberModel.Role,
+		CreatedAt: memberModel.CreatedAt,
+	}
+}
+
+// UserOrganizationResponse is an organization listed with the role of the user in it
+type UserOrganizationResponse struct {
+	OrganizationResponse
+	Role string `json:"role"`
+}
+
+type UserOrganizationModel struct {
+	OrganizationModel
+	Role string
+}
+
+func UserOrganizationModelToUserOrganizationResponse(userOrganizationModel *UserOrganizationModel) *UserOrganizationResponse {
+	return &UserOrganizationResponse{
+		OrganizationResponse: *OrganizationModelToOrganizationResponse(&userOrganizationModel.OrganizationModel),
+		Role:                 userOrganizationModel.Role,
+	}
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	UserAggregate = "user"
+
+	UserCreatedEvent = "user.created"
+	UserUpdatedEvent = "user.updated"
+	UserDeletedEvent = "user.deleted"
+)
+
+// EventTypes lists the types of the events recorded in the outbox
+var EventTypes = []string{UserCreatedEvent, UserUpdatedEvent, UserDeletedEvent}
+
+// EventMessage is a domain event as it is delivered to the sinks. Delivery is at least once, consumers
+// recognize redelivered events by their id
+type EventMessage struct {
+	ID            int64           `json:"id"`
+	TenantID      string          `json:"tenant_id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Type          string          `json:"type"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+type OutboxEventModel struct {
+	ID            int64
+	TenantID      string
+	AggregateType string
+	AggregateID   string
+	Type          string
+	Payload       json.RawMessage
+	CreatedAt     time.Time
+	// Attempts counts the failed deliveries, the next one is due at NextAttemptAt
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     *string
+	PublishedAt   *time.Time
+	// FailedAt is when the event failed its last attempt, it is not retried anymore
+	FailedAt *time.Time
+	// RequestID is the request which recorded the event, if any
+	RequestID *string
+}
+
+func OutboxEventModelToEventMessage(event *OutboxEventModel) *EventMessage {
+	return &EventMessage{
+		ID:            event.ID,
+		TenantID:      event.TenantID,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		Type:          event.Type,
+		Payload:       event.Payload,
+		OccurredAt:    event.CreatedAt,
+	}
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"crud/internal/querystats"
+	"encoding/json"
+	"time"
+)
+
+type QueryStatsResponse struct {
+	Statements []*StatementStatsResponse `json:"statements"`
+	// Dropped counts queries of statements which were not tracked because too many statements are tracked
+	Dropped int64 `json:"dropped"`
+}
+
+type StatementStatsResponse struct {
+	Statement  string     `json:"statement"`
+	Count      int64      `json:"count"`
+	Errors     int64      `json:"errors"`
+	Slow       int64      `json:"slow"`
+	TotalMs    float64    `json:"total_ms"`
+	P50Ms      float64    `json:"p50_ms"`
+	P95Ms      float64    `json:"p95_ms"`
+	MaxMs      float64    `json:"max_ms"`
+	LastSlowAt *time.Time `json:"last_slow_at,omitempty"`
+	LastSlowMs *float64   `json:"last_slow_ms,omitempty"`
+	// Plan is the EXPLAIN output of the last slow execution, only returned when requested
+	Plan      json.RawMessage `json:"plan,omitempty" swaggertype:"object"`
+	PlanError string          `json:"plan_error,omitempty"`
+}
+
+func toMilliseconds(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
+
+func QueryStatsToStatementStatsResponse(stats *querystats.Stats) *StatementStatsResponse {
+	response := &StatementStatsResponse{
+		Statement: stats.Statement,
+		Count:     stats.Count,
+		Errors:    stats.Errors,
+		Slow:      stats.Slow,
+		TotalMs:   toMilliseconds(stats.Total),
+		P50Ms:     toMilliseconds(stats.P50),
+		P95Ms:     toMilliseconds(stats.P95),
+		MaxMs:     toMilliseconds(stats.Max),
+	}
+	if stats.LastSample != nil {
+		lastSlowMs := toMilliseconds(stats.LastSample.Duration)
+		response.LastSlowAt = &stats.LastSample.At
+		response.LastSlowMs = &lastSlowMs
+	}
+	return response
+}
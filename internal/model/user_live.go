@@ -0,0 +1,69 @@
+package model
+
+import "strings"
+
+// The messages of the WebSocket API, clients send the request types and receive the others
+const (
+	LiveSubscribe   = "subscribe"
+	LiveUnsubscribe = "unsubscribe"
+
+	LiveSnapshot     = "snapshot"
+	LiveAdded        = "added"
+	LiveUpdated      = "updated"
+	LiveRemoved      = "removed"
+	LiveUnsubscribed = "unsubscribed"
+	LiveError        = "error"
+)
+
+// UserFilter selects users, empty fields match all users
+type UserFilter struct {
+	// Name matches the users whose name contains it, ignoring case
+	Name string `json:"name,omitempty"`
+	// Email matches the user with the email, ignoring case
+	Email  string `json:"email,omitempty"`
+	MinAge *int   `json:"min_age,omitempty"`
+	MaxAge *int   `json:"max_age,omitempty"`
+}
+
+// Matches reports whether the user is selected by the filter, the same way the repository selects them
+func (filter *UserFilter) Matches(user *UserModel) bool {
+	return (filter.Name == "" || strings.Contains(strings.ToLower(user.Name), strings.ToLower(filter.Name))) &&
+		(filter.Email == "" || strings.EqualFold(user.Email, filter.Email)) &&
+		(filter.MinAge == nil || user.Age >= *filter.MinAge) &&
+		(filter.MaxAge == nil || user.Age <= *filter.MaxAge)
+}
+
+// UserQuery is a live query, either of a single user or of the users matching the filter
+type UserQuery struct {
+	UserID *int        `json:"user_id,omitempty"`
+	Filter *UserFilter `json:"filter,omitempty"`
+	// Limit caps the users of the snapshot of a filtered query
+	Limit int `json:"limit,omitempty"`
+}
+
+// Matches reports whether the user is in the result of the query
+func (query *UserQuery) Matches(user *UserModel) bool {
+	if query.UserID != nil {
+		return user.ID == *query.UserID
+	}
+	return query.Filter == nil || query.Filter.Matches(user)
+}
+
+// LiveRequest subscribes to or unsubscribes from a query, the client chooses the id its messages are sent with
+type LiveRequest struct {
+	Type  string     `json:"type"`
+	ID    string     `json:"id"`
+	Query *UserQuery `json:"query,omitempty"`
+}
+
+// LiveMessage is the snapshot of a query, a change of its result or an error
+type LiveMessage struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+	// Users is the snapshot, it is left out when no user matches
+	Users []*UserResponse `json:"users,omitempty"`
+	// User is the added, updated or removed user, EventID the id of the change, as in the user stream
+	User    *UserResponse `json:"user,omitempty"`
+	EventID int64         `json:"event_id,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
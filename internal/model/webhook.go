@@ -0,0 +1,112 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookAllEvents subscribes to every event type
+const WebhookAllEvents = "*"
+
+// Statuses of the webhook deliveries, a delivery is dead once it failed too often and is only sent again when replayed
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookSubscriptionResponse never contains the secret of the subscription
+type WebhookSubscriptionResponse struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required"`
+	// Secret signs the deliveries, it is required on update too and replaces the previous secret
+	Secret string `json:"secret" binding:"required"`
+}
+
+type WebhookSubscriptionModel struct {
+	ID         int
+	URL        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  time.Time
+}
+
+func WebhookSubscriptionRequestToWebhookSubscriptionModel(id int, request *WebhookSubscriptionRequest) *WebhookSubscriptionModel {
+	return &WebhookSubscriptionModel{ID: id, URL: request.URL, EventTypes: request.EventTypes, Secret: request.Secret}
+}
+
+func WebhookSubscriptionModelToWebhookSubscriptionResponse(subscription *WebhookSubscriptionModel) *WebhookSubscriptionResponse {
+	return &WebhookSubscriptionResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+// WebhookDeliveryResponse is an entry of the delivery log of a subscription
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int             `json:"subscription_id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status" enums:"pending,succeeded,dead"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+type WebhookDeliveryModel struct {
+	ID             int64
+	TenantID       string
+	SubscriptionID int
+	EventID        int64
+	EventType      string
+	// Payload is the body of the requests, the EventMessage of the event
+	Payload json.RawMessage
+	Status  string
+	// Attempts counts the requests sent, the next one is due at NextAttemptAt while the delivery is pending
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      *string
+	LastStatusCode *int
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+	// RequestID is the request which recorded the event, it is sent along with the delivery
+	RequestID *string
+	// URL and Secret are the ones of the subscription, they are only loaded for sending
+	URL    string
+	Secret string
+}
+
+func WebhookDeliveryModelToWebhookDeliveryResponse(delivery *WebhookDeliveryModel) *WebhookDeliveryResponse {
+	response := &WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastError:      delivery.LastError,
+		LastStatusCode: delivery.LastStatusCode,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+	if delivery.Status == WebhookDeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		response.NextAttemptAt = &nextAttemptAt
+	}
+	return response
+}
@@ -0,0 +1,184 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crud/internal/model"
+	"crud/internal/util/request"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink delivers domain events to their consumers. An event whose delivery fails is retried, so sinks
+// may deliver an event more than once
+type Sink interface {
+	Publish(ctx context.Context, event *model.EventMessage) error
+}
+
+// WriterSink writes every event as a line of JSON, e.g. to stdout or to a file
+type WriterSink struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func NewWriterSink(writer io.Writer) *WriterSink {
+	return &WriterSink{writer: writer}
+}
+
+func (sink *WriterSink) Publish(_ context.Context, event *model.EventMessage) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	_, err = sink.writer.Write(append(line, '\n'))
+	return err
+}
+
+// DefaultWebhookTimeout bounds a webhook delivery, the event is retried when it is exceeded
+const DefaultWebhookTimeout = 10 * time.Second
+
+// WebhookSink posts every event as JSON to an URL, any status but 2xx fails the delivery
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	return &WebhookSink{url: url, client: client}
+}
+
+func (sink *WebhookSink) Publish(ctx context.Context, event *model.EventMessage) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", fmt.Sprint(event.ID))
+	req.Header.Set("X-Event-Type", event.Type)
+	if requestID := request.GetRequestID(ctx); requestID != "" {
+		req.Header.Set(request.HeaderRequestID, requestID)
+	}
+	resp, err := sink.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s responded %s", sink.url, resp.Status)
+	}
+	return nil
+}
+
+// maxNotifyPayload is the limit of PostgreSQL on NOTIFY payloads, less one byte
+const maxNotifyPayload = 7999
+
+// NotifySink sends every event as JSON with pg_notify on a channel. Events exceeding the payload limit
+// of NOTIFY are sent without their payload
+type NotifySink struct {
+	dbPool  *pgxpool.Pool
+	channel string
+}
+
+func NewNotifySink(pool *pgxpool.Pool, channel string) *NotifySink {
+	return &NotifySink{dbPool: pool, channel: channel}
+}
+
+func (sink *NotifySink) Publish(ctx context.Context, event *model.EventMessage) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxNotifyPayload {
+		withoutPayload := *event
+		withoutPayload.Payload = nil
+		if payload, err = json.Marshal(&withoutPayload); err != nil {
+			return err
+		}
+	}
+	_, err = sink.dbPool.Exec(ctx, "SELECT pg_notify($1, $2)", sink.channel, string(payload))
+	return err
+}
+
+type closers []io.Closer
+
+func (closers closers) Close() error {
+	var err error
+	for _, closer := range closers {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
+
+// OpenSinks opens the sinks of a semicolon separated list of outputs, e.g.
+// "stdout;file:///var/lib/crud/events.jsonl;https://hooks.example.com/users;notify:user_events".
+// The outputs are stdout, file:<path> appending JSON lines, http(s) URLs receiving a POST per event and
+// notify:<channel> for PostgreSQL NOTIFY. The closer closes the files
+func OpenSinks(spec string, pool *pgxpool.Pool) ([]Sink, io.Closer, error) {
+	sinks := make([]Sink, 0)
+	files := make(closers, 0)
+	client := &http.Client{Timeout: DefaultWebhookTimeout}
+	for _, item := range strings.Split(spec, ";") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		sink, file, err := openSink(item, pool, client)
+		if err != nil {
+			_ = files.Close()
+			return nil, nil, fmt.Errorf("invalid outbox sink %q: %w", item, err)
+		}
+		if file != nil {
+			files = append(files, file)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, files, nil
+}
+
+func openSink(item string, pool *pgxpool.Pool, client *http.Client) (Sink, io.Closer, error) {
+	sinkURL, err := url.Parse(item)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case sinkURL.Scheme == "" && sinkURL.Path == "stdout":
+		return NewWriterSink(os.Stdout), nil, nil
+	case sinkURL.Scheme == "file":
+		path := sinkURL.Path
+		if sinkURL.Opaque != "" {
+			path = sinkURL.Opaque
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewWriterSink(file), file, nil
+	case sinkURL.Scheme == "http" || sinkURL.Scheme == "https":
+		if sinkURL.Host == "" {
+			return nil, nil, errors.New("missing webhook host")
+		}
+		return NewWebhookSink(sinkURL.String(), client), nil, nil
+	case sinkURL.Scheme == "notify":
+		if sinkURL.Opaque == "" {
+			return nil, nil, errors.New("missing channel, use notify:<channel>")
+		}
+		return NewNotifySink(pool, sinkURL.Opaque), nil, nil
+	default:
+		return nil, nil, errors.New("unknown output, use stdout, file:<path>, an http(s) URL or notify:<channel>")
+	}
+}
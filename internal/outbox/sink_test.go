@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crud/internal/model"
+	"crud/internal/util/request"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testEvent() *model.EventMessage {
+	return &model.EventMessage{
+		ID:            7,
+		TenantID:      "acme",
+		AggregateType: model.UserAggregate,
+		AggregateID:   "5",
+		Type:          model.UserCreatedEvent,
+		Payload:       json.RawMessage(`{"id":5,"name":"Ada"}`),
+		OccurredAt:    time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestUnitWriterSink(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, NewWriterSink(buffer).Publish(context.Background(), testEvent()))
+	assert.JSONEq(t, `{"id":7,"tenant_id":"acme","aggregate_type":"user","aggregate_id":"5","type":"user.created",
+		"payload":{"id":5,"name":"Ada"},"occurred_at":"2025-03-01T12:00:00Z"}`, buffer.String())
+	assert.Equal(t, byte('\n'), buffer.Bytes()[buffer.Len()-1])
+}
+
+func TestUnitWebhookSink(t *testing.T) {
+	t.Parallel()
+
+	status := http.StatusNoContent
+	var received model.EventMessage
+	var eventType, requestID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		eventType, requestID = r.Header.Get("X-Event-Type"), r.Header.Get(request.HeaderRequestID)
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	sink := NewWebhookSink(server.URL, server.Client())
+
+	require.NoError(t, sink.Publish(request.WithRequestID(context.Background(), "req-1"), testEvent()))
+	assert.Equal(t, int64(7), received.ID)
+	assert.Equal(t, model.UserCreatedEvent, eventType)
+	assert.Equal(t, "req-1", requestID)
+
+	status = http.StatusServiceUnavailable
+	assert.ErrorContains(t, sink.Publish(context.Background(), testEvent()), "503")
+}
+
+func TestUnitOpenSinks(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sinks, closer, err := OpenSinks("stdout; file://"+path+";https://hooks.example.com/users;notify:user_events", nil)
+	require.NoError(t, err)
+	require.Len(t, sinks, 4)
+	assert.IsType(t, &WriterSink{}, sinks[0])
+	assert.IsType(t, &WebhookSink{}, sinks[2])
+	assert.Equal(t, "user_events", sinks[3].(*NotifySink).channel)
+
+	require.NoError(t, sinks[1].Publish(context.Background(), testEvent()))
+	require.NoError(t, closer.Close())
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(written), `"type":"user.created"`)
+
+	for _, spec := range []string{"stderr", "notify:", "https://", "kafka://broker:9092"} {
+		_, _, err = OpenSinks(spec, nil)
+		assert.Error(t, err, spec)
+	}
+}
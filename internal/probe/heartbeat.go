@@ -0,0 +1,48 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Heartbeat detects a stuck loop without depending on the database. The loop calls Beat on every iteration,
+// or Run beats on a ticker to watch the scheduler itself; the check fails once no beat came within maxAge
+type Heartbeat struct {
+	maxAge time.Duration
+	last   atomic.Int64
+	now    func() time.Time
+}
+
+func NewHeartbeat(maxAge time.Duration) *Heartbeat {
+	heartbeat := &Heartbeat{maxAge: maxAge, now: time.Now}
+	heartbeat.Beat()
+	return heartbeat
+}
+
+func (heartbeat *Heartbeat) Beat() {
+	heartbeat.last.Store(heartbeat.now().UnixNano())
+}
+
+// Run beats every interval until ctx is done
+func (heartbeat *Heartbeat) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			heartbeat.Beat()
+		}
+	}
+}
+
+func (heartbeat *Heartbeat) Check(context.Context) error {
+	age := heartbeat.now().Sub(time.Unix(0, heartbeat.last.Load()))
+	if age > heartbeat.maxAge {
+		return fmt.Errorf("no heartbeat for %s", age.Round(time.Millisecond))
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kind is the probe a check belongs to
+type Kind string
+
+const (
+	// Liveness checks detect a process which has to be restarted, they must not depend on the database
+	Liveness Kind = "livez"
+	// Readiness checks decide whether the instance receives traffic
+	Readiness Kind = "readyz"
+	// Startup checks pass once the instance finished starting, e.g. ran the migrations
+	Startup Kind = "startupz"
+)
+
+// DefaultTimeout is used for checks without a timeout
+const DefaultTimeout = 2 * time.Second
+
+const (
+	StatusOK     = "ok"
+	StatusFailed = "failed"
+)
+
+var (
+	ErrShuttingDown = errors.New("shutting down")
+	ErrNotStarted   = errors.New("not started")
+)
+
+// Check is a named component check of a probe
+type Check struct {
+	Name    string
+	Timeout time.Duration
+	Func    func(ctx context.Context) error
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the outcome of all checks of a probe, it is ok when every check passed
+type Report struct {
+	Status     string   `json:"status"`
+	Components []Result `json:"components"`
+}
+
+// Registry holds the checks of each probe. Modules register their own checks, the registry adds
+// the startup and shutdown state of the process
+type Registry struct {
+	mutex        sync.RWMutex
+	checks       map[Kind][]Check
+	started      atomic.Bool
+	shuttingDown atomic.Bool
+}
+
+func NewRegistry() *Registry {
+	registry := &Registry{checks: make(map[Kind][]Check)}
+	registry.Register(Check{Name: "started", Func: func(context.Context) error {
+		if !registry.started.Load() {
+			return ErrNotStarted
+		}
+		return nil
+	}}, Startup)
+	registry.Register(Check{Name: "shutdown", Func: func(context.Context) error {
+		if registry.shuttingDown.Load() {
+			return ErrShuttingDown
+		}
+		return nil
+	}}, Readiness)
+	return registry
+}
+
+// Register adds a check to the probes of the given kinds
+func (registry *Registry) Register(check Check, kinds ...Kind) {
+	if check.Timeout <= 0 {
+		check.Timeout = DefaultTimeout
+	}
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	for _, kind := range kinds {
+		registry.checks[kind] = append(registry.checks[kind], check)
+	}
+}
+
+// MarkStarted makes the startup probe pass, it is called once the server accepts connections
+func (registry *Registry) MarkStarted() {
+	registry.started.Store(true)
+}
+
+// MarkShuttingDown makes the readiness probe fail, so load balancers stop sending new requests
+func (registry *Registry) MarkShuttingDown() {
+	registry.shuttingDown.Store(true)
+}
+
+// Run runs the checks of the probe concurrently, each with its own timeout
+func (registry *Registry) Run(ctx context.Context, kind Kind) Report {
+	registry.mutex.RLock()
+	checks := slices.Clone(registry.checks[kind])
+	registry.mutex.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wait sync.WaitGroup
+	for i, check := range checks {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			results[i] = runCheck(ctx, check)
+		}()
+	}
+	wait.Wait()
+
+	report := Report{Status: StatusOK, Components: results}
+	for _, result := range results {
+		if result.Status != StatusOK {
+			report.Status = StatusFailed
+		}
+	}
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+	start := time.Now()
+	errs := make(chan error, 1)
+	// A check ignoring ctx must not hold up the probe beyond its timeout
+	go func() {
+		errs <- check.Func(ctx)
+	}()
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result := Result{
+		Name:      check.Name,
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Handler serves the probe: 200 when every check passed and 503 otherwise. The body is "ok" or "failed",
+// with the verbose query parameter it is the JSON report listing every component and its latency
+func (registry *Registry) Handler(kind Kind) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		report := registry.Run(request.Context(), kind)
+		statusCode := http.StatusOK
+		if report.Status != StatusOK {
+			statusCode = http.StatusServiceUnavailable
+		}
+		writer.Header().Set("Cache-Control", "no-store")
+		if !request.URL.Query().Has("verbose") {
+			writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			writer.WriteHeader(statusCode)
+			_, _ = writer.Write([]byte(report.Status))
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		writer.WriteHeader(statusCode)
+		_ = json.NewEncoder(writer).Encode(report)
+	}
+}
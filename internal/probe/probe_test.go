@@ -0,0 +1,81 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitRegistry(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	registry.Register(Check{Name: "database", Func: func(context.Context) error {
+		return nil
+	}}, Readiness, Startup)
+	registry.Register(Check{Name: "stuck", Timeout: 10 * time.Millisecond, Func: func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}}, Liveness)
+
+	assert.Equal(t, StatusFailed, registry.Run(context.Background(), Startup).Status, "not started yet")
+	registry.MarkStarted()
+	assert.Equal(t, StatusOK, registry.Run(context.Background(), Startup).Status)
+
+	report := registry.Run(context.Background(), Readiness)
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Len(t, report.Components, 2)
+	registry.MarkShuttingDown()
+	report = registry.Run(context.Background(), Readiness)
+	assert.Equal(t, StatusFailed, report.Status, "readiness fails during shutdown")
+	assert.Equal(t, Result{Name: "shutdown", Status: StatusFailed, LatencyMs: report.Components[0].LatencyMs,
+		Error: ErrShuttingDown.Error()}, report.Components[0])
+
+	report = registry.Run(context.Background(), Liveness)
+	require.Len(t, report.Components, 1)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Components[0].Error, "a check ignoring ctx times out")
+}
+
+func TestUnitHandler(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	registry.Register(Check{Name: "database", Func: func(context.Context) error {
+		return errors.New("connection refused")
+	}}, Readiness)
+
+	recorder := httptest.NewRecorder()
+	registry.Handler(Liveness)(recorder, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, StatusOK, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	registry.Handler(Readiness)(recorder, httptest.NewRequest(http.MethodGet, "/readyz?verbose", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	var report Report
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	assert.Equal(t, StatusFailed, report.Status)
+	require.Len(t, report.Components, 2)
+	assert.Equal(t, "database", report.Components[1].Name)
+	assert.Equal(t, "connection refused", report.Components[1].Error)
+}
+
+func TestUnitHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	heartbeat := &Heartbeat{maxAge: 10 * time.Second, now: func() time.Time { return now }}
+	heartbeat.Beat()
+	now = now.Add(5 * time.Second)
+	assert.NoError(t, heartbeat.Check(context.Background()))
+	now = now.Add(6 * time.Second)
+	assert.EqualError(t, heartbeat.Check(context.Background()), "no heartbeat for 11s")
+	heartbeat.Beat()
+	assert.NoError(t, heartbeat.Check(context.Background()))
+}
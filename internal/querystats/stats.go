@@ -0,0 +1,153 @@
+package querystats
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxStatements bounds the number of tracked statements, queries of new statements are
+	// counted as dropped once it is reached
+	DefaultMaxStatements = 1000
+	// durationSamples is the number of recent durations kept per statement for the percentiles
+	durationSamples = 512
+)
+
+// Sample is the last slow execution of a statement, it is kept to explain the statement later
+type Sample struct {
+	SQL      string
+	Args     []any
+	Duration time.Duration
+	At       time.Time
+	// TenantID is the tenant the statement ran for, it is explained in the schema and with the policies of the tenant
+	TenantID string
+}
+
+// Stats is a point in time copy of the statistics of a statement
+type Stats struct {
+	Statement  string
+	Count      int64
+	Errors     int64
+	Slow       int64
+	Total      time.Duration
+	P50        time.Duration
+	P95        time.Duration
+	Max        time.Duration
+	LastSample *Sample
+}
+
+type statement struct {
+	count     int64
+	errors    int64
+	slow      int64
+	total     time.Duration
+	max       time.Duration
+	durations []time.Duration
+	next      int
+	sample    *Sample
+}
+
+// Collector aggregates query durations by normalized statement
+type Collector struct {
+	mutex         sync.Mutex
+	statements    map[string]*statement
+	maxStatements int
+	dropped       int64
+}
+
+func NewCollector(maxStatements int) *Collector {
+	return &Collector{statements: make(map[string]*statement), maxStatements: maxStatements}
+}
+
+// Record adds an execution of the statement, sample is nil unless the query was slow and its
+// arguments may be kept in memory
+func (collector *Collector) Record(fingerprint string, duration time.Duration, err error, slow bool, sample *Sample) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	stmt, ok := collector.statements[fingerprint]
+	if !ok {
+		if len(collector.statements) >= collector.maxStatements {
+			collector.dropped++
+			return
+		}
+		stmt = &statement{durations: make([]time.Duration, 0, durationSamples)}
+		collector.statements[fingerprint] = stmt
+	}
+	stmt.count++
+	stmt.total += duration
+	stmt.max = max(stmt.max, duration)
+	if len(stmt.durations) < durationSamples {
+		stmt.durations = append(stmt.durations, duration)
+	} else {
+		stmt.durations[stmt.next] = duration
+		stmt.next = (stmt.next + 1) % durationSamples
+	}
+	// A cancelled request is not a failing statement
+	if err != nil && !errors.Is(err, context.Canceled) {
+		stmt.errors++
+	}
+	if slow {
+		stmt.slow++
+	}
+	if sample != nil {
+		stmt.sample = sample
+	}
+}
+
+// Snapshot returns the statistics sorted by total time, the most expensive statements first
+func (collector *Collector) Snapshot() ([]*Stats, int64) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	stats := make([]*Stats, 0, len(collector.statements))
+	for fingerprint, stmt := range collector.statements {
+		durations := slices.Clone(stmt.durations)
+		slices.Sort(durations)
+		stats = append(stats, &Stats{
+			Statement:  fingerprint,
+			Count:      stmt.count,
+			Errors:     stmt.errors,
+			Slow:       stmt.slow,
+			Total:      stmt.total,
+			P50:        percentile(durations, 0.50),
+			P95:        percentile(durations, 0.95),
+			Max:        stmt.max,
+			LastSample: stmt.sample,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Total > stats[j].Total })
+	return stats, collector.dropped
+}
+
+func (collector *Collector) Reset() {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	collector.statements = make(map[string]*statement)
+	collector.dropped = 0
+}
+
+// percentile uses the nearest rank method on sorted durations
+func percentile(sorted []time.Duration, rank float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(rank*float64(len(sorted))+0.5) - 1
+	return sorted[min(max(index, 0), len(sorted)-1)]
+}
+
+type skipKey struct{}
+
+// WithoutStats excludes the queries run with ctx from the statistics, e.g. the EXPLAIN of a sample
+func WithoutStats(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipKey{}, true)
+}
+
+func IsSkipped(ctx context.Context) bool {
+	skipped, _ := ctx.Value(skipKey{}).(bool)
+	return skipped
+}
@@ -0,0 +1,61 @@
+package querystats
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestUnitCollector(t *testing.T) {
+	t.Parallel()
+
+	collector := NewCollector(2)
+	for i := 1; i <= 100; i++ {
+		collector.Record("SELECT 1", time.Duration(i)*time.Millisecond, nil, false, nil)
+	}
+	sample := &Sample{SQL: "SELECT pg_sleep($1)", Args: []any{1}, Duration: time.Second}
+	collector.Record("SELECT pg_sleep($1)", time.Second, nil, true, sample)
+	collector.Record("SELECT pg_sleep($1)", time.Millisecond, errors.New("timeout"), false, nil)
+	collector.Record("SELECT pg_sleep($1)", time.Millisecond, context.Canceled, false, nil)
+	collector.Record("SELECT 2", time.Millisecond, nil, false, nil)
+
+	stats, dropped := collector.Snapshot()
+	require.Len(t, stats, 2)
+	assert.Equal(t, int64(1), dropped, "statements over the limit are not tracked")
+
+	assert.Equal(t, "SELECT 1", stats[0].Statement, "the most expensive statement comes first")
+	assert.Equal(t, int64(100), stats[0].Count)
+	assert.Equal(t, 50*time.Millisecond, stats[0].P50)
+	assert.Equal(t, 95*time.Millisecond, stats[0].P95)
+	assert.Equal(t, 100*time.Millisecond, stats[0].Max)
+	assert.Nil(t, stats[0].LastSample)
+
+	assert.Equal(t, int64(3), stats[1].Count)
+	assert.Equal(t, int64(1), stats[1].Errors, "cancelled queries are not errors")
+	assert.Equal(t, int64(1), stats[1].Slow)
+	assert.Same(t, sample, stats[1].LastSample)
+
+	collector.Reset()
+	stats, dropped = collector.Snapshot()
+	assert.Empty(t, stats)
+	assert.Zero(t, dropped)
+}
+
+func TestUnitCollectorKeepsRecentDurations(t *testing.T) {
+	t.Parallel()
+
+	collector := NewCollector(DefaultMaxStatements)
+	for i := 0; i < durationSamples; i++ {
+		collector.Record("SELECT 1", time.Second, nil, false, nil)
+	}
+	for i := 0; i < durationSamples; i++ {
+		collector.Record("SELECT 1", time.Millisecond, nil, false, nil)
+	}
+
+	stats, _ := collector.Snapshot()
+	assert.Equal(t, time.Millisecond, stats[0].P95, "old durations are replaced")
+	assert.Equal(t, time.Second, stats[0].Max, "the maximum is kept since the last reset")
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit describes a token bucket: Burst requests are allowed at once and the bucket refills
+// completely over Period
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Rate returns how many tokens are added to the bucket every second
+func (limit Limit) Rate() float64 {
+	return float64(limit.Burst) / limit.Period.Seconds()
+}
+
+// Policy formats the limit for the RateLimit-Policy header, e.g. "100;w=60"
+func (limit Limit) Policy() string {
+	return fmt.Sprintf("%d;w=%d", limit.Burst, int(math.Ceil(limit.Period.Seconds())))
+}
+
+// ParseLimit parses limits in the "<requests>/<period>" form, e.g. "100/1m" or "5/1s"
+func ParseLimit(value string) (Limit, error) {
+	requests, period, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected <requests>/<period>", value)
+	}
+	burst, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || burst <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, requests must be a positive integer", value)
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || duration <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, period must be a positive duration", value)
+	}
+	return Limit{Burst: burst, Period: duration}, nil
+}
+
+// Result is the state of a bucket after taking a token from it
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next token is available, zero when the request is allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps the token buckets
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// resultFromTokens computes the response values from the tokens left in the bucket
+func resultFromTokens(allowed bool, tokens float64, limit Limit) Result {
+	rate := limit.Rate()
+	result := Result{
+		Allowed:   allowed,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     time.Duration((float64(limit.Burst) - tokens) / rate * float64(time.Second)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Limiter picks the limit for a route and takes tokens from the client's bucket
+type Limiter struct {
+	store        Store
+	defaultLimit *Limit
+	routeLimits  map[string]Limit
+}
+
+// NewLimiter creates a limiter, defaultLimit may be nil to only limit the configured routes
+func NewLimiter(store Store, defaultLimit *Limit, routeLimits map[string]Limit) *Limiter {
+	return &Limiter{store: store, defaultLimit: defaultLimit, routeLimits: routeLimits}
+}
+
+// LimitFor returns the limit of a route and the bucket scope it shares. Routes with their own
+// limit get their own bucket, all other routes share the default one.
+func (limiter *Limiter) LimitFor(method, route string) (Limit, string, bool) {
+	if limit, ok := limiter.routeLimits[method+" "+route]; ok {
+		return limit, method + " " + route, true
+	}
+	if limit, ok := limiter.routeLimits[route]; ok {
+		return limit, route, true
+	}
+	if limiter.defaultLimit != nil {
+		return *limiter.defaultLimit, "*", true
+	}
+	return Limit{}, "", false
+}
+
+// Take takes a token for the client on the given route. The returned limit is zero when the route is not limited.
+func (limiter *Limiter) Take(ctx context.Context, client, method, route string) (Limit, Result, error) {
+	limit, scope, ok := limiter.LimitFor(method, route)
+	if !ok {
+		return Limit{}, Result{Allowed: true}, nil
+	}
+	result, err := limiter.store.Take(ctx, scope+"|"+client, limit)
+	return limit, result, err
+}
+
+// ParseRouteLimits parses a semicolon separated list of "[METHOD ]<route>=<requests>/<period>" entries,
+// e.g. "POST /api/v1/user/=5/1m;/api/v1/user/:id=50/1m". Routes use the gin path template.
+func ParseRouteLimits(value string) (map[string]Limit, error) {
+	routeLimits := make(map[string]Limit)
+	for _, entry := range strings.Split(value, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		separator := strings.LastIndex(entry, "=")
+		if separator <= 0 {
+			return nil, fmt.Errorf("invalid route rate limit %q, expected [METHOD ]<route>=<requests>/<period>", entry)
+		}
+		limit, err := ParseLimit(entry[separator+1:])
+		if err != nil {
+			return nil, err
+		}
+		route := strings.Join(strings.Fields(entry[:separator]), " ")
+		if method, path, found := strings.Cut(route, " "); found {
+			route = strings.ToUpper(method) + " " + path
+		}
+		routeLimits[route] = limit
+	}
+	return routeLimits, nil
+}
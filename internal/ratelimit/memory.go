@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// DefaultSweepInterval is how often idle buckets are evicted from the memory store
+const DefaultSweepInterval = time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	period    time.Duration
+}
+
+// MemoryStore keeps buckets in process memory. Buckets that have been refilled completely
+// carry no state and are evicted on the next sweep.
+type MemoryStore struct {
+	mutex         sync.Mutex
+	buckets       map[string]*bucket
+	lastSweep     time.Time
+	sweepInterval time.Duration
+	now           func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:       make(map[string]*bucket),
+		lastSweep:     time.Now(),
+		sweepInterval: DefaultSweepInterval,
+		now:           time.Now,
+	}
+}
+
+func (store *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := store.now()
+	if now.Sub(store.lastSweep) >= store.sweepInterval {
+		store.sweep(now)
+	}
+
+	b, ok := store.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		store.buckets[key] = b
+	}
+	b.period = limit.Period
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate())
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return resultFromTokens(allowed, b.tokens, limit), nil
+}
+
+// Len returns the number of buckets currently kept in memory
+func (store *MemoryStore) Len() int {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return len(store.buckets)
+}
+
+// sweep removes buckets that have been idle long enough to be full again
+func (store *MemoryStore) sweep(now time.Time) {
+	for key, b := range store.buckets {
+		if now.Sub(b.updatedAt) >= b.period {
+			delete(store.buckets, key)
+		}
+	}
+	store.lastSweep = now
+}
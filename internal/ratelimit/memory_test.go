@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestUnitMemoryStoreTokenBucket(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Burst: 2, Period: 2 * time.Second}
+	ctx := context.Background()
+
+	result, err := store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+
+	result, _ = store.Take(ctx, "client", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result, _ = store.Take(ctx, "client", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 2*time.Second, result.Reset)
+
+	result, _ = store.Take(ctx, "other", limit)
+	assert.True(t, result.Allowed, "buckets are kept per key")
+
+	now = now.Add(time.Second)
+	result, _ = store.Take(ctx, "client", limit)
+	assert.True(t, result.Allowed, "one token is refilled after a second")
+}
+
+func TestUnitMemoryStoreEviction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	store.lastSweep = now
+	limit := Limit{Burst: 1, Period: time.Second}
+
+	_, _ = store.Take(context.Background(), "idle", limit)
+	assert.Equal(t, 1, store.Len())
+
+	now = now.Add(DefaultSweepInterval)
+	_, _ = store.Take(context.Background(), "active", limit)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestUnitParseRouteLimits(t *testing.T) {
+	t.Parallel()
+
+	routeLimits, err := ParseRouteLimits("post /api/v1/user/=5/1m; /api/v1/user/:id=50/1s")
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Burst: 5, Period: time.Minute}, routeLimits["POST /api/v1/user/"])
+	assert.Equal(t, Limit{Burst: 50, Period: time.Second}, routeLimits["/api/v1/user/:id"])
+
+	limiter := NewLimiter(NewMemoryStore(), &Limit{Burst: 100, Period: time.Minute}, routeLimits)
+	_, scope, _ := limiter.LimitFor("GET", "/api/v1/user/")
+	assert.Equal(t, "*", scope)
+	limit, scope, _ := limiter.LimitFor("POST", "/api/v1/user/")
+	assert.Equal(t, "POST /api/v1/user/", scope)
+	assert.Equal(t, 5, limit.Burst)
+
+	_, err = ParseRouteLimits("/api/v1/user/=five/1m")
+	assert.Error(t, err)
+	_, err = ParseLimit("10")
+	assert.Error(t, err)
+}
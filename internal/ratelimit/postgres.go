@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so that limits are shared by all replicas
+type PostgresStore struct {
+	dbPool        *pgxpool.Pool
+	mutex         sync.Mutex
+	lastSweep     time.Time
+	sweepInterval time.Duration
+	logger        *slog.Logger
+}
+
+func NewPostgresStore(pool *pgxpool.Pool, logger *slog.Logger) *PostgresStore {
+	return &PostgresStore{dbPool: pool, lastSweep: time.Now(), sweepInterval: DefaultSweepInterval, logger: logger}
+}
+
+// refilledTokens are the tokens of the bucket row being updated, refilled since its last update
+const refilledTokens = "LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM (now() - b.updated_at)) * $3::float8)"
+
+// Take refills and takes a token from the bucket in a single statement. The refill is computed in the SET
+// clause from the row ON CONFLICT locked, so concurrent requests on different replicas never see a stale bucket
+func (store *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	store.sweepIfDue(ctx)
+
+	row := store.dbPool.QueryRow(ctx, `
+		INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, expires_at, updated_at)
+		VALUES ($1, $2::float8 - 1, true, now() + make_interval(secs => $4::float8), now())
+		ON CONFLICT (key) DO UPDATE SET
+			allowed = `+refilledTokens+` >= 1,
+			tokens = CASE WHEN `+refilledTokens+` >= 1 THEN `+refilledTokens+` - 1 ELSE `+refilledTokens+` END,
+			expires_at = now() + make_interval(secs => $4::float8),
+			updated_at = now()
+		RETURNING allowed, tokens`,
+		key, float64(limit.Burst), limit.Rate(), limit.Period.Seconds())
+	var allowed bool
+	var tokens float64
+	if err := row.Scan(&allowed, &tokens); err != nil {
+		return Result{}, err
+	}
+	return resultFromTokens(allowed, tokens, limit), nil
+}
+
+// sweepIfDue deletes buckets that are full again, at most once per sweep interval per replica
+func (store *PostgresStore) sweepIfDue(ctx context.Context) {
+	store.mutex.Lock()
+	if time.Since(store.lastSweep) < store.sweepInterval {
+		store.mutex.Unlock()
+		return
+	}
+	store.lastSweep = time.Now()
+	store.mutex.Unlock()
+
+	if _, err := store.dbPool.Exec(ctx, "DELETE FROM rate_limit_buckets WHERE expires_at < now()"); err != nil {
+		store.logger.WarnContext(ctx, "failed to evict rate limit buckets", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"crud/internal/model"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type IAPIKeyRepository interface {
+	Create(apiKey *model.APIKeyModel, ctx *context.Context) (*model.APIKeyModel, error)
+	GetByPrefix(prefix string, ctx *context.Context) (*model.APIKeyModel, error)
+	GetAll(offset, limit int, ctx *context.Context) ([]*model.APIKeyModel, error)
+	Revoke(id int, ctx *context.Context) (*model.APIKeyModel, error)
+	UpdateLastUsed(lastUsed map[int]time.Time, ctx *context.Context) error
+}
+
+type APIKeyRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(pool *pgxpool.Pool) IAPIKeyRepository {
+	return &APIKeyRepository{dbPool: pool}
+}
+
+const apiKeyColumns = "id, tenant_id, name, prefix, secret_hash, scopes, expires_at, last_used_at, revoked_at, created_at"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (*model.APIKeyModel, error) {
+	apiKey := &model.APIKeyModel{}
+	err := row.Scan(&apiKey.ID, &apiKey.TenantID, &apiKey.Name, &apiKey.Prefix, &apiKey.SecretHash, &apiKey.Scopes,
+		&apiKey.ExpiresAt, &apiKey.LastUsedAt, &apiKey.RevokedAt, &apiKey.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+// Create stores the key in the tenant of ctx
+func (repository *APIKeyRepository) Create(apiKey *model.APIKeyModel, ctx *context.Context) (*model.APIKeyModel, error) {
+	var created *model.APIKeyModel
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		var err error
+		created, err = scanAPIKey(q.QueryRow(*ctx, `
+			INSERT INTO api_keys(tenant_id, name, prefix, secret_hash, scopes, expires_at) values($1, $2, $3, $4, $5, $6)
+			RETURNING `+apiKeyColumns,
+			tenantID, apiKey.Name, apiKey.Prefix, apiKey.SecretHash, apiKey.Scopes, apiKey.ExpiresAt))
+		return err
+	})
+	return created, err
+}
+
+// GetByPrefix looks the key up in every tenant, the key names the tenant of the caller
+func (repository *APIKeyRepository) GetByPrefix(prefix string, ctx *context.Context) (*model.APIKeyModel, error) {
+	var apiKey *model.APIKeyModel
+	err := unscoped(*ctx, repository.dbPool, func(q querier) error {
+		var err error
+		apiKey, err = scanAPIKey(q.QueryRow(*ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE prefix = $1", prefix))
+		return err
+	})
+	return apiKey, err
+}
+
+func (repository *APIKeyRepository) GetAll(offset, limit int, ctx *context.Context) ([]*model.APIKeyModel, error) {
+	apiKeys := make([]*model.APIKeyModel, 0)
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		rows, err := q.Query(*ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE tenant_id = $1 ORDER BY id LIMIT $2 OFFSET $3",
+			tenantID, limit, offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			apiKey, err := scanAPIKey(rows)
+			if err != nil {
+				return err
+			}
+			apiKeys = append(apiKeys, apiKey)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return apiKeys, nil
+}
+
+func (repository *APIKeyRepository) Revoke(id int, ctx *context.Context) (*model.APIKeyModel, error) {
+	var revoked *model.APIKeyModel
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		var err error
+		revoked, err = scanAPIKey(q.QueryRow(*ctx,
+			"UPDATE api_keys SET revoked_at = COALESCE(revoked_at, now()) WHERE id = $1 AND tenant_id = $2 RETURNING "+apiKeyColumns,
+			id, tenantID))
+		return err
+	})
+	return revoked, err
+}
+
+// UpdateLastUsed stores a batch of usage times of the keys of the tenant of ctx in one statement, it never moves
+// last_used_at backwards
+func (repository *APIKeyRepository) UpdateLastUsed(lastUsed map[int]time.Time, ctx *context.Context) error {
+	ids := make([]int, 0, len(lastUsed))
+	times := make([]time.Time, 0, len(lastUsed))
+	for id, usedAt := range lastUsed {
+		ids = append(ids, id)
+		times = append(times, usedAt)
+	}
+	return scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		_, err := q.Exec(*ctx, `
+			UPDATE api_keys SET last_used_at = GREATEST(COALESCE(api_keys.last_used_at, usage.used_at), usage.used_at)
+			FROM unnest($1::int[], $2::timestamptz[]) AS usage(id, used_at)
+			WHERE api_keys.id = usage.id AND api_keys.tenant_id = $3`,
+			ids, times, tenantID)
+		return err
+	})
+}
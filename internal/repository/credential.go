@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"crud/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type ICredentialRepository interface {
+	GetByEmail(email string, ctx *context.Context) (*model.CredentialModel, error)
+	GetByUserID(userID int, ctx *context.Context) (*model.CredentialModel, error)
+	SetPassword(userID int, passwordHash string, ctx *context.Context) error
+	RecordFailedLogin(userID int, maxAttempts int, lockDuration time.Duration, ctx *context.Context) (*model.CredentialModel, error)
+	ResetFailedLogins(userID int, ctx *context.Context) error
+}
+
+type CredentialRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewCredentialRepository(pool *pgxpool.Pool) ICredentialRepository {
+	return &CredentialRepository{dbPool: pool}
+}
+
+func scanCredential(row rowScanner) (*model.CredentialModel, error) {
+	credential := &model.CredentialModel{}
+	err := row.Scan(&credential.UserID, &credential.PasswordHash, &credential.FailedAttempts, &credential.LockedUntil)
+	if err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+// GetByEmail returns the credentials of the user with the email in the tenant of ctx
+func (repository *CredentialRepository) GetByEmail(email string, ctx *context.Context) (*model.CredentialModel, error) {
+	var credential *model.CredentialModel
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		var err error
+		credential, err = scanCredential(q.QueryRow(WithSensitiveArgs(*ctx), `
+			SELECT c.user_id, c.password_hash, c.failed_attempts, c.locked_until
+			FROM user_credentials c JOIN users u ON u.id = c.user_id
+			WHERE lower(u.email) = lower($1) AND u.tenant_id = $2`, email, tenantID))
+		return err
+	})
+	return credential, err
+}
+
+// GetByUserID returns the credentials of the user of the tenant of ctx
+func (repository *CredentialRepository) GetByUserID(userID int, ctx *context.Context) (*model.CredentialModel, error) {
+	var credential *model.CredentialModel
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		var err error
+		credential, err = scanCredential(q.QueryRow(*ctx, `
+			SELECT c.user_id, c.password_hash, c.failed_attempts, c.locked_until
+			FROM user_credentials c JOIN users u ON u.id = c.user_id
+			WHERE c.user_id = $1 AND u.tenant_id = $2`, userID, tenantID))
+		return err
+	})
+	return credential, err
+}
+
+// SetPassword sets the password of the user of the tenant of ctx, pgx.ErrNoRows is returned for unknown users
+func (repository *CredentialRepository) SetPassword(userID int, passwordHash string, ctx *context.Context) error {
+	return scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		tag, err := q.Exec(WithSensitiveArgs(*ctx), `
+			INSERT INTO user_credentials(user_id, password_hash) SELECT id, $2 FROM users WHERE id = $1 AND tenant_id = $3
+			ON CONFLICT (user_id) DO UPDATE SET password_hash = $2, failed_attempts = 0, locked_until = NULL, updated_at = now()`,
+			userID, passwordHash, tenantID)
+		if err == nil && tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+		return err
+	})
+}
+
+// RecordFailedLogin counts a failed attempt of the user of the tenant of ctx and locks the account for lockDuration
+// once maxAttempts is reached
+func (repository *CredentialRepository) RecordFailedLogin(userID int, maxAttempts int, lockDuration time.Duration, ctx *context.Context) (*model.CredentialModel, error) {
+	var credential *model.CredentialModel
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		var err error
+		credential, err = scanCredential(q.QueryRow(*ctx, `
+			UPDATE user_credentials c SET
+				failed_attempts = CASE WHEN c.failed_attempts + 1 >= $2 THEN 0 ELSE c.failed_attempts + 1 END,
+				locked_until = CASE WHEN c.failed_attempts + 1 >= $2 THEN now() + make_interval(secs => $3::float8) ELSE c.locked_until END,
+				updated_at = now()
+			FROM users u
+			WHERE c.user_id = $1 AND u.id = c.user_id AND u.tenant_id = $4
+			RETURNING c.user_id, c.password_hash, c.failed_attempts, c.locked_until`,
+			userID, maxAttempts, lockDuration.Seconds(), tenantID))
+		return err
+	})
+	return credential, err
+}
+
+func (repository *CredentialRepository) ResetFailedLogins(userID int, ctx *context.Context) error {
+	return scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		_, err := q.Exec(*ctx, `
+			UPDATE user_credentials c SET failed_attempts = 0, locked_until = NULL
+			FROM users u
+			WHERE c.user_id = $1 AND u.id = c.user_id AND u.tenant_id = $2
+			AND (c.failed_attempts > 0 OR c.locked_until IS NOT NULL)`,
+			userID, tenantID)
+		return err
+	})
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"strings"
+)
+
+// IRepository stores entities of type T with keys of type ID
+type IRepository[T any, ID any] interface {
+	Create(entity *T, ctx *context.Context) (*T, error)
+	GetById(id ID, ctx *context.Context) (*T, error)
+	Update(entity *T, ctx *context.Context) (*T, error)
+	Delete(id ID, ctx *context.Context) (*T, error)
+	GetAll(offset, limit int, ctx *context.Context) ([]*T, error)
+}
+
+// Column maps a table column to a field of T
+type Column[T any] struct {
+	Name string
+	// Field returns the pointer to the field of entity, it is scanned into and passed as query argument
+	Field func(entity *T) any
+	// Immutable columns are written on create only
+	Immutable bool
+	// Generated columns are set by the database, e.g. by a default, and never written
+	Generated bool
+}
+
+// TenantColumn is the column of tenant scoped tables naming the tenant of the row
+const TenantColumn = "tenant_id"
+
+// Table describes how entities of type T are stored. The key column is generated by the database
+type Table[T any] struct {
+	Name    string
+	ID      Column[T]
+	Columns []Column[T]
+	// TenantScoped tables have a TenantColumn, every query is filtered by the tenant of the context
+	// and runs with the tenant set for the row level security policies of the table
+	TenantScoped bool
+}
+
+// Repository implements IRepository for the entities described by a Table
+type Repository[T any, ID any] struct {
+	dbPool *pgxpool.Pool
+	table  Table[T]
+	// selectColumns is the key column followed by the other columns, the order entities are scanned in
+	selectColumns string
+}
+
+func NewRepository[T any, ID any](pool *pgxpool.Pool, table Table[T]) *Repository[T, ID] {
+	names := []string{table.ID.Name}
+	for _, column := range table.Columns {
+		names = append(names, column.Name)
+	}
+	return &Repository[T, ID]{dbPool: pool, table: table, selectColumns: strings.Join(names, ", ")}
+}
+
+func (repository *Repository[T, ID]) Create(entity *T, ctx *context.Context) (*T, error) {
+	var created *T
+	err := repository.run(*ctx, func(q querier, tenantID string) error {
+		names := make([]string, 0, len(repository.table.Columns)+1)
+		placeholders := make([]string, 0, len(repository.table.Columns)+1)
+		args := make([]any, 0, len(repository.table.Columns)+1)
+		for _, column := range repository.table.Columns {
+			if column.Generated {
+				continue
+			}
+			names = append(names, column.Name)
+			args = append(args, column.Field(entity))
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		if repository.table.TenantScoped {
+			names = append(names, TenantColumn)
+			args = append(args, tenantID)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		sql := fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s) RETURNING %s", repository.table.Name,
+			strings.Join(names, ", "), strings.Join(placeholders, ", "), repository.selectColumns)
+		var err error
+		created, err = repository.scanOne(q.QueryRow(*ctx, sql, args...))
+		return err
+	})
+	return created, err
+}
+
+func (repository *Repository[T, ID]) GetById(id ID, ctx *context.Context) (*T, error) {
+	var entity *T
+	err := repository.run(*ctx, func(q querier, tenantID string) error {
+		args := []any{id}
+		sql := fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1%s", repository.selectColumns, repository.table.Name,
+			repository.table.ID.Name, repository.tenantFilter(tenantID, &args))
+		var err error
+		entity, err = repository.scanOne(q.QueryRow(*ctx, sql, args...))
+		return err
+	})
+	return entity, err
+}
+
+func (repository *Repository[T, ID]) Update(entity *T, ctx *context.Context) (*T, error) {
+	var updated *T
+	err := repository.run(*ctx, func(q querier, tenantID string) error {
+		assignments := make([]string, 0, len(repository.table.Columns))
+		args := make([]any, 0, len(repository.table.Columns)+2)
+		for _, column := range repository.table.Columns {
+			if column.Immutable || column.Generated {
+				continue
+			}
+			args = append(args, column.Field(entity))
+			assignments = append(assignments, fmt.Sprintf("%s = $%d", column.Name, len(args)))
+		}
+		args = append(args, repository.table.ID.Field(entity))
+		sql := fmt.Sprintf("UPDATE %s SET %s WHERE %s = $%d%s RETURNING %s", repository.table.Name,
+			strings.Join(assignments, ", "), repository.table.ID.Name, len(args), repository.tenantFilter(tenantID, &args),
+			repository.selectColumns)
+		var err error
+		updated, err = repository.scanOne(q.QueryRow(*ctx, sql, args...))
+		return err
+	})
+	return updated, err
+}
+
+func (repository *Repository[T, ID]) Delete(id ID, ctx *context.Context) (*T, error) {
+	var deleted *T
+	err := repository.run(*ctx, func(q querier, tenantID string) error {
+		args := []any{id}
+		sql := fmt.Sprintf("DELETE FROM %s WHERE %s = $1%s RETURNING %s", repository.table.Name,
+			repository.table.ID.Name, repository.tenantFilter(tenantID, &args), repository.selectColumns)
+		var err error
+		deleted, err = repository.scanOne(q.QueryRow(*ctx, sql, args...))
+		return err
+	})
+	return deleted, err
+}
+
+func (repository *Repository[T, ID]) GetAll(offset, limit int, ctx *context.Context) ([]*T, error) {
+	entities := make([]*T, 0)
+	err := repository.run(*ctx, func(q querier, tenantID string) error {
+		args := []any{limit, offset}
+		where := ""
+		if filter := repository.tenantFilter(tenantID, &args); filter != "" {
+			where = " WHERE" + strings.TrimPrefix(filter, " AND")
+		}
+		sql := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s LIMIT $1 OFFSET $2", repository.selectColumns,
+			repository.table.Name, where, repository.table.ID.Name)
+		rows, err := q.Query(*ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			entity := new(T)
+			if err = rows.Scan(repository.fields(entity)...); err != nil {
+				return err
+			}
+			entities = append(entities, entity)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// run passes fn the connection of ctx, the queries of tenant scoped tables run scoped to the tenant of ctx
+func (repository *Repository[T, ID]) run(ctx context.Context, fn func(q querier, tenantID string) error) error {
+	if !repository.table.TenantScoped {
+		return fn(conn(ctx, repository.dbPool), "")
+	}
+	return scoped(ctx, repository.dbPool, fn)
+}
+
+// tenantFilter returns the condition restricting tenant scoped tables to the tenant, appending it to args
+func (repository *Repository[T, ID]) tenantFilter(tenantID string, args *[]any) string {
+	if !repository.table.TenantScoped {
+		return ""
+	}
+	*args = append(*args, tenantID)
+	return fmt.Sprintf(" AND %s = $%d", TenantColumn, len(*args))
+}
+
+func (repository *Repository[T, ID]) scanOne(row pgx.Row) (*T, error) {
+	entity := new(T)
+	if err := row.Scan(repository.fields(entity)...); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// fields returns the scan destinations of entity in the order of selectColumns
+func (repository *Repository[T, ID]) fields(entity *T) []any {
+	fields := []any{repository.table.ID.Field(entity)}
+	for _, column := range repository.table.Columns {
+		fields = append(fields, column.Field(entity))
+	}
+	return fields
+}
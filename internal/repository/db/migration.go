@@ -1,9 +1,19 @@
 package db
 
 import (
+	"context"
+	"crud/internal/tenant"
 	"embed"
+	"errors"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/source"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"io"
+	"io/fs"
+	"log/slog"
 )
 
 //go:embed migrations/*.sql
@@ -12,3 +22,85 @@ var dbMigrationFs embed.FS
 func GetMigrationDriver() (source.Driver, error) {
 	return iofs.New(dbMigrationFs, "migrations")
 }
+
+// seedMigrationVersion is 000002_add_entries_to_users_table, which adds sample users to the default tenant
+const seedMigrationVersion = 2
+
+// unseededMigrationDriver leaves the seed migration out, golang-migrate records its version without running it
+type unseededMigrationDriver struct {
+	source.Driver
+}
+
+func (driver *unseededMigrationDriver) ReadUp(version uint) (io.ReadCloser, string, error) {
+	if version == seedMigrationVersion {
+		return nil, "", fs.ErrNotExist
+	}
+	return driver.Driver.ReadUp(version)
+}
+
+func (driver *unseededMigrationDriver) ReadDown(version uint) (io.ReadCloser, string, error) {
+	if version == seedMigrationVersion {
+		return nil, "", fs.ErrNotExist
+	}
+	return driver.Driver.ReadDown(version)
+}
+
+// Migrate runs the embedded migrations in the current schema of the connection acquired with ctx, which is the
+// schema of the tenant of ctx in the schema per tenant mode, see TenantSchemas. The version is tracked per schema,
+// the schemas of tenants other than the default one are not seeded
+func Migrate(ctx context.Context, pool *pgxpool.Pool, logger *slog.Logger) error {
+	migrationDriver, err := GetMigrationDriver()
+	if err != nil {
+		return err
+	}
+	if tenantID, ok := tenant.FromContext(ctx); ok && tenantID != tenant.DefaultTenant {
+		migrationDriver = &unseededMigrationDriver{Driver: migrationDriver}
+	}
+	sqlDB := stdlib.OpenDBFromPool(pool)
+	defer sqlDB.Close()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logger.Warn("failed to close database connection", slog.String("error", err.Error()))
+		}
+	}()
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		return err
+	}
+	m, err := migrate.NewWithInstance("iofs", migrationDriver, "postgres", driver)
+	if err != nil {
+		return err
+	}
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// LatestMigrationVersion returns the version of the newest embedded migration, the version a migrated
+// database is at
+func LatestMigrationVersion() (uint, error) {
+	driver, err := GetMigrationDriver()
+	if err != nil {
+		return 0, err
+	}
+	defer driver.Close()
+	version, err := driver.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := driver.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key VARCHAR(255) primary key,
+    tokens DOUBLE PRECISION not null,
+    allowed BOOLEAN not null,
+    expires_at TIMESTAMPTZ not null,
+    updated_at TIMESTAMPTZ not null
+);
+
+CREATE INDEX IF NOT EXISTS rate_limit_buckets_expires_at_idx ON rate_limit_buckets (expires_at);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id int primary key generated always as identity,
+    name VARCHAR(255) not null,
+    prefix VARCHAR(32) not null unique,
+    secret_hash BYTEA not null,
+    scopes TEXT[] not null default '{}',
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ not null default now()
+);
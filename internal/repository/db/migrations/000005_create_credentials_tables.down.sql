@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_sessions;
+DROP TABLE IF EXISTS user_credentials;
+DROP INDEX IF EXISTS users_email_key;
@@ -0,0 +1,34 @@
+-- Emails are unique regardless of case from now on. Users whose emails differ only in case cannot be told apart
+-- at login, they are not merged silently: the migration stops until they are renamed or merged by hand
+DO $$
+DECLARE
+    duplicates INT;
+BEGIN
+    SELECT count(*) INTO duplicates FROM (SELECT 1 FROM users GROUP BY lower(email) HAVING count(*) > 1) emails;
+    IF duplicates > 0 THEN
+        RAISE EXCEPTION '% emails are used by several users differing only in case', duplicates
+            USING HINT = 'Find them with SELECT lower(email) FROM users GROUP BY 1 HAVING count(*) > 1, rename or merge '
+                'them and force the migration version back to 4 before migrating again';
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (lower(email));
+
+CREATE TABLE IF NOT EXISTS user_credentials (
+    user_id int primary key references users(id) on delete cascade,
+    password_hash VARCHAR(255) not null,
+    failed_attempts INT not null default 0,
+    locked_until TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ not null default now()
+);
+
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id int primary key generated always as identity,
+    user_id int not null references users(id) on delete cascade,
+    token_hash BYTEA not null unique,
+    expires_at TIMESTAMPTZ not null,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS user_sessions_user_id_idx ON user_sessions (user_id);
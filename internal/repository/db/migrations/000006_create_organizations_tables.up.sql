@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id int primary key generated always as identity,
+    name VARCHAR(255) not null,
+    created_at TIMESTAMPTZ not null default now()
+);
+
+CREATE TABLE IF NOT EXISTS organization_memberships (
+    organization_id int not null references organizations(id) on delete cascade,
+    user_id int not null references users(id) on delete cascade,
+    role VARCHAR(16) not null check (role in ('owner', 'admin', 'member')),
+    created_at TIMESTAMPTZ not null default now(),
+    primary key (organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS organization_memberships_user_id_idx ON organization_memberships (user_id);
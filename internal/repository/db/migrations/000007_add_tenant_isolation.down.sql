@@ -0,0 +1,25 @@
+DROP POLICY IF EXISTS organization_memberships_tenant_isolation ON organization_memberships;
+DROP POLICY IF EXISTS organizations_tenant_isolation ON organizations;
+DROP POLICY IF EXISTS users_tenant_isolation ON users;
+ALTER TABLE organization_memberships NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE organizations NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE users NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+
+ALTER TABLE organization_memberships
+    DROP CONSTRAINT IF EXISTS organization_memberships_organization_fkey,
+    DROP CONSTRAINT IF EXISTS organization_memberships_user_fkey,
+    ADD CONSTRAINT organization_memberships_organization_id_fkey FOREIGN KEY (organization_id)
+        REFERENCES organizations(id) ON DELETE CASCADE,
+    ADD CONSTRAINT organization_memberships_user_id_fkey FOREIGN KEY (user_id)
+        REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE organizations DROP CONSTRAINT IF EXISTS organizations_id_tenant_id_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_id_tenant_id_key;
+
+DROP INDEX IF EXISTS organizations_tenant_id_idx;
+DROP INDEX IF EXISTS users_tenant_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (lower(email));
+
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE organization_memberships DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE organizations DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE users DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,47 @@
+-- The rows written before tenancy belong to the default tenant
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) not null default 'default';
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) not null default 'default';
+ALTER TABLE organization_memberships ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) not null default 'default';
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) not null default 'default';
+
+-- From now on rows are written to the tenant of the transaction, without one the insert fails
+ALTER TABLE users ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+ALTER TABLE organizations ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+ALTER TABLE organization_memberships ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+ALTER TABLE user_sessions ALTER COLUMN tenant_id DROP DEFAULT;
+
+DROP INDEX IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_tenant_email_key ON users (tenant_id, lower(email));
+CREATE INDEX IF NOT EXISTS organizations_tenant_id_idx ON organizations (tenant_id);
+
+-- Memberships can only link a user and an organization of their own tenant
+ALTER TABLE users ADD CONSTRAINT users_id_tenant_id_key UNIQUE (id, tenant_id);
+ALTER TABLE organizations ADD CONSTRAINT organizations_id_tenant_id_key UNIQUE (id, tenant_id);
+ALTER TABLE organization_memberships
+    DROP CONSTRAINT IF EXISTS organization_memberships_organization_id_fkey,
+    DROP CONSTRAINT IF EXISTS organization_memberships_user_id_fkey,
+    ADD CONSTRAINT organization_memberships_organization_fkey FOREIGN KEY (organization_id, tenant_id)
+        REFERENCES organizations(id, tenant_id) ON DELETE CASCADE,
+    ADD CONSTRAINT organization_memberships_user_fkey FOREIGN KEY (user_id, tenant_id)
+        REFERENCES users(id, tenant_id) ON DELETE CASCADE;
+
+-- The policies hide the rows of other tenants even from queries missing the tenant condition. The application
+-- sets app.tenant_id with SET LOCAL semantics per transaction, FORCE applies the policies to the table owner too.
+-- Superusers and roles with BYPASSRLS are not subject to them
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+CREATE POLICY users_tenant_isolation ON users
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE organizations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE organizations FORCE ROW LEVEL SECURITY;
+CREATE POLICY organizations_tenant_isolation ON organizations
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE organization_memberships ENABLE ROW LEVEL SECURITY;
+ALTER TABLE organization_memberships FORCE ROW LEVEL SECURITY;
+CREATE POLICY organization_memberships_tenant_isolation ON organization_memberships
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,20 @@
+-- Domain events are written in the transaction of the change they describe and relayed to the sinks afterwards.
+-- The relay reads the events of every tenant, the table has no row level security
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id bigint primary key generated always as identity,
+    tenant_id VARCHAR(63) not null,
+    aggregate_type VARCHAR(63) not null,
+    aggregate_id VARCHAR(255) not null,
+    event_type VARCHAR(127) not null,
+    payload JSONB not null,
+    created_at TIMESTAMPTZ not null default now(),
+    attempts INT not null default 0,
+    next_attempt_at TIMESTAMPTZ not null default now(),
+    last_error TEXT,
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_events_pending_idx ON outbox_events (aggregate_type, aggregate_id, id)
+    WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_events_published_at_idx ON outbox_events (published_at)
+    WHERE published_at IS NOT NULL;
@@ -0,0 +1,34 @@
+-- Subscriptions receive the domain events of their tenant whose type they list, '*' matches every type.
+-- The secret signs the deliveries, it is stored in plain text since the signature is computed from it
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id int primary key generated always as identity,
+    tenant_id VARCHAR(63) not null,
+    url TEXT not null,
+    event_types TEXT[] not null,
+    secret VARCHAR(255) not null,
+    created_at TIMESTAMPTZ not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_tenant_id_idx ON webhook_subscriptions (tenant_id);
+
+-- A delivery is an event sent to a subscription, it is written once per event and subscription. The dispatcher
+-- sends the deliveries of every tenant, the tables have no row level security and are filtered by tenant_id
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id bigint primary key generated always as identity,
+    tenant_id VARCHAR(63) not null,
+    subscription_id int not null references webhook_subscriptions(id) on delete cascade,
+    event_id bigint not null,
+    event_type VARCHAR(127) not null,
+    payload JSONB not null,
+    status VARCHAR(16) not null default 'pending' check (status in ('pending', 'succeeded', 'dead')),
+    attempts INT not null default 0,
+    next_attempt_at TIMESTAMPTZ not null default now(),
+    last_error TEXT,
+    last_status_code INT,
+    created_at TIMESTAMPTZ not null default now(),
+    delivered_at TIMESTAMPTZ,
+    unique (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at)
+    WHERE status = 'pending';
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS users_log_change ON users;
+DROP FUNCTION IF EXISTS log_user_change();
+DROP TABLE IF EXISTS user_change_log;
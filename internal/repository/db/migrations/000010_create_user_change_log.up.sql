@@ -0,0 +1,46 @@
+-- Every change of a user is logged by a trigger and announced on the user_changes channel, so that the event streams
+-- of all replicas see it. The log is trimmed to its newest entries, streams resume from it after a reconnect
+CREATE TABLE IF NOT EXISTS user_change_log (
+    id bigint primary key generated always as identity,
+    tenant_id VARCHAR(63) not null,
+    event_type VARCHAR(127) not null,
+    user_id int not null,
+    payload JSONB not null,
+    created_at TIMESTAMPTZ not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS user_change_log_tenant_id_idx ON user_change_log (tenant_id, id);
+
+-- The notification is the log entry as an event message, users are small enough for the 8000 bytes of a payload.
+-- The function keeps the search path of the migration, the log of a tenant schema is written next to its users
+CREATE OR REPLACE FUNCTION log_user_change() RETURNS trigger LANGUAGE plpgsql SET search_path FROM CURRENT AS $$
+DECLARE
+    changed users;
+    logged user_change_log;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+    INSERT INTO user_change_log(tenant_id, event_type, user_id, payload)
+    VALUES (changed.tenant_id,
+            CASE TG_OP WHEN 'INSERT' THEN 'user.created' WHEN 'UPDATE' THEN 'user.updated' ELSE 'user.deleted' END,
+            changed.id,
+            jsonb_build_object('id', changed.id, 'name', changed.name, 'email', changed.email, 'age', changed.age))
+    RETURNING * INTO logged;
+    PERFORM pg_notify('user_changes', json_build_object(
+        'id', logged.id,
+        'tenant_id', logged.tenant_id,
+        'aggregate_type', 'user',
+        'aggregate_id', logged.user_id::text,
+        'type', logged.event_type,
+        'payload', logged.payload,
+        'occurred_at', logged.created_at)::text);
+    RETURN NULL;
+END
+$$;
+
+DROP TRIGGER IF EXISTS users_log_change ON users;
+CREATE TRIGGER users_log_change AFTER INSERT OR UPDATE OR DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION log_user_change();
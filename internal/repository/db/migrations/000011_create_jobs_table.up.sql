@@ -0,0 +1,29 @@
+-- Background jobs, claimed by the workers of every replica with FOR UPDATE SKIP LOCKED. A running job is leased
+-- until locked_until, a job whose worker died is claimed again once the lease expired. The workers run the jobs of
+-- every tenant, the table has no row level security and is filtered by tenant_id
+CREATE TABLE IF NOT EXISTS jobs (
+    id bigint primary key generated always as identity,
+    tenant_id VARCHAR(63) not null,
+    type VARCHAR(127) not null,
+    payload JSONB not null default '{}',
+    status VARCHAR(16) not null default 'pending' check (status in ('pending', 'running', 'succeeded', 'failed')),
+    unique_key VARCHAR(255),
+    attempts INT not null default 0,
+    max_attempts INT not null,
+    timeout_ms INT not null,
+    run_at TIMESTAMPTZ not null default now(),
+    locked_until TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ not null default now(),
+    finished_at TIMESTAMPTZ
+);
+
+-- A unique key admits one pending or running job of its type per tenant, finished jobs do not hold it
+CREATE UNIQUE INDEX IF NOT EXISTS jobs_unique_key_idx ON jobs (tenant_id, type, unique_key)
+    WHERE unique_key IS NOT NULL AND status IN ('pending', 'running');
+
+CREATE INDEX IF NOT EXISTS jobs_pending_idx ON jobs (run_at) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS jobs_running_idx ON jobs (locked_until) WHERE status = 'running';
+
+CREATE INDEX IF NOT EXISTS jobs_tenant_id_idx ON jobs (tenant_id, id);
@@ -0,0 +1,2 @@
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS request_id;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS request_id;
@@ -0,0 +1,3 @@
+-- The request which recorded an event or a delivery, it is sent along with the outbound requests
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS request_id VARCHAR(128);
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS request_id VARCHAR(128);
@@ -0,0 +1,11 @@
+DROP POLICY IF EXISTS user_change_log_tenant_isolation ON user_change_log;
+DROP POLICY IF EXISTS webhook_subscriptions_tenant_isolation ON webhook_subscriptions;
+DROP POLICY IF EXISTS user_sessions_tenant_isolation ON user_sessions;
+DROP POLICY IF EXISTS api_keys_tenant_isolation ON api_keys;
+ALTER TABLE user_change_log NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_subscriptions NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE user_sessions NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS api_keys_tenant_id_idx;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
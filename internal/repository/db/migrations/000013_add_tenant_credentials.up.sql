@@ -0,0 +1,33 @@
+-- API keys belong to the tenant they were created in. The keys written before are given to the tenant of their
+-- schema, tenant_<id> in the schema per tenant mode, and to the default tenant in the shared schema
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) not null
+    default coalesce(substring(current_schema() from '^tenant_(.+)$'), 'default');
+ALTER TABLE api_keys ALTER COLUMN tenant_id DROP DEFAULT;
+CREATE INDEX IF NOT EXISTS api_keys_tenant_id_idx ON api_keys (tenant_id);
+
+-- Like the policies of 000007_add_tenant_isolation. Credentials are looked up by their secret before the tenant of
+-- the caller is known, and the relay, the dispatcher and the trimming of the change log serve every tenant, these
+-- set app.all_tenants for their transaction instead of app.tenant_id
+ALTER TABLE api_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY api_keys_tenant_isolation ON api_keys
+    USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on');
+
+ALTER TABLE user_sessions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_sessions FORCE ROW LEVEL SECURITY;
+CREATE POLICY user_sessions_tenant_isolation ON user_sessions
+    USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on');
+
+ALTER TABLE webhook_subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_subscriptions FORCE ROW LEVEL SECURITY;
+CREATE POLICY webhook_subscriptions_tenant_isolation ON webhook_subscriptions
+    USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on');
+
+ALTER TABLE user_change_log ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_change_log FORCE ROW LEVEL SECURITY;
+CREATE POLICY user_change_log_tenant_isolation ON user_change_log
+    USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on');
@@ -0,0 +1,7 @@
+-- An event failing its last attempt is dead, failed_at is set and the relay stops retrying it. Dead events no longer
+-- hold back the later events of their aggregate and are kept for inspection
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS failed_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS outbox_events_pending_idx;
+CREATE INDEX IF NOT EXISTS outbox_events_pending_idx ON outbox_events (aggregate_type, aggregate_id, id)
+    WHERE published_at IS NULL AND failed_at IS NULL;
@@ -0,0 +1,236 @@
+package db
+
+import (
+	"context"
+	"crud/internal/tenant"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// TenantSchemaPrefix is prepended to the tenant to name its schema, e.g. tenant_acme
+const TenantSchemaPrefix = "tenant_"
+
+// maxSchemaName is the length PostgreSQL truncates identifiers to
+const maxSchemaName = 63
+
+// ErrBaseSchema is returned when dropping the schema of the default tenant
+var ErrBaseSchema = errors.New("the schema of the default tenant cannot be dropped")
+
+// TenantSchemas isolates tenants in schemas of their own. The default tenant and the connections acquired without
+// a tenant use the base schema, every other tenant uses TenantSchemaPrefix followed by the tenant. The search_path
+// of a connection is set when it is checked out of the pool, see BeforeAcquire
+type TenantSchemas struct {
+	base string
+	// searchPaths is the schema by connection, connections missing from it still use the base schema
+	searchPaths sync.Map
+	// provisioned caches the tenants whose schema is known to exist
+	provisioned sync.Map
+	logger      *slog.Logger
+}
+
+// NewTenantSchemas creates the schemas of a pool whose connections start in base, the DB_SCHEMA setting
+func NewTenantSchemas(base string, logger *slog.Logger) *TenantSchemas {
+	return &TenantSchemas{base: base, logger: logger}
+}
+
+// Schema returns the schema of the tenant
+func (schemas *TenantSchemas) Schema(tenantID string) string {
+	if tenantID == tenant.DefaultTenant {
+		return schemas.base
+	}
+	return TenantSchemaPrefix + tenantID
+}
+
+// Configure installs the hooks keeping the search_path of the pool connections in line with the tenant of
+// the context they are acquired with
+func (schemas *TenantSchemas) Configure(config *pgxpool.Config) {
+	config.ConnConfig.RuntimeParams["search_path"] = pgx.Identifier{schemas.base}.Sanitize()
+	config.BeforeAcquire = schemas.BeforeAcquire
+	config.BeforeClose = schemas.Release
+}
+
+// Release forgets the search_path of conn. The pool calls it when it closes a connection, a connection hijacked from
+// the pool is never closed by it and must be released by its new owner
+func (schemas *TenantSchemas) Release(conn *pgx.Conn) {
+	schemas.searchPaths.Delete(conn)
+}
+
+// BeforeAcquire sets the search_path of conn to the schema of the tenant of ctx, a connection which cannot be
+// switched is destroyed and the pool acquires another one. A tenant whose schema is missing, e.g. dropped by another
+// replica, or whose switch failed is no longer known to be provisioned, Exists looks it up again
+func (schemas *TenantSchemas) BeforeAcquire(ctx context.Context, conn *pgx.Conn) bool {
+	schema := schemas.base
+	tenantID, ok := tenant.FromContext(ctx)
+	if ok {
+		schema = schemas.Schema(tenantID)
+	}
+	current := schemas.base
+	if value, ok := schemas.searchPaths.Load(conn); ok {
+		current = value.(string)
+	}
+	if current == schema {
+		return true
+	}
+	var searchPath string
+	var exists bool
+	err := conn.QueryRow(ctx, "SELECT set_config('search_path', $1, false), EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $2)",
+		pgx.Identifier{schema}.Sanitize(), schema).Scan(&searchPath, &exists)
+	if err != nil || !exists {
+		schemas.provisioned.Delete(tenantID)
+	}
+	if err != nil {
+		return false
+	}
+	schemas.searchPaths.Store(conn, schema)
+	return true
+}
+
+// Exists reports whether the schema of the tenant exists, the schema of the default tenant always does
+func (schemas *TenantSchemas) Exists(ctx context.Context, pool *pgxpool.Pool, tenantID string) (bool, error) {
+	if tenantID == tenant.DefaultTenant {
+		return true, nil
+	}
+	if _, ok := schemas.provisioned.Load(tenantID); ok {
+		return true, nil
+	}
+	var exists bool
+	err := pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)",
+		schemas.Schema(tenantID)).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		schemas.provisioned.Store(tenantID, struct{}{})
+	}
+	return exists, nil
+}
+
+// Provision creates the schema of the tenant when it is missing and runs the embedded migrations in it
+func (schemas *TenantSchemas) Provision(ctx context.Context, pool *pgxpool.Pool, tenantID string) error {
+	if !tenant.IsValid(tenantID) {
+		return fmt.Errorf("%w %q", tenant.ErrInvalidTenant, tenantID)
+	}
+	schema := schemas.Schema(tenantID)
+	if len(schema) > maxSchemaName {
+		return fmt.Errorf("%w %q, the schema name %q is longer than %d characters",
+			tenant.ErrInvalidTenant, tenantID, schema, maxSchemaName)
+	}
+	// The tenant is not in ctx yet, the schema is created from the base schema
+	if _, err := pool.Exec(ctx, "CREATE SCHEMA IF NOT EXISTS "+pgx.Identifier{schema}.Sanitize()); err != nil {
+		return err
+	}
+	if err := schemas.migrate(ctx, pool, tenantID); err != nil {
+		return err
+	}
+	schemas.provisioned.Store(tenantID, struct{}{})
+	return nil
+}
+
+func (schemas *TenantSchemas) migrate(ctx context.Context, pool *pgxpool.Pool, tenantID string) error {
+	if err := Migrate(tenant.WithTenant(ctx, tenantID), pool, schemas.logger); err != nil {
+		return fmt.Errorf("migrating schema %s: %w", schemas.Schema(tenantID), err)
+	}
+	return nil
+}
+
+// Drop drops the schema of the tenant with all of its data
+func (schemas *TenantSchemas) Drop(ctx context.Context, pool *pgxpool.Pool, tenantID string) error {
+	if tenantID == tenant.DefaultTenant {
+		return ErrBaseSchema
+	}
+	exists, err := schemas.Exists(ctx, pool, tenantID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w %q", tenant.ErrUnknownTenant, tenantID)
+	}
+	if _, err = pool.Exec(ctx, "DROP SCHEMA "+pgx.Identifier{schemas.Schema(tenantID)}.Sanitize()+" CASCADE"); err != nil {
+		return err
+	}
+	schemas.provisioned.Delete(tenantID)
+	return nil
+}
+
+// MigrateAll runs the embedded migrations in the schemas of all tenants, the base schema included
+func (schemas *TenantSchemas) MigrateAll(ctx context.Context, pool *pgxpool.Pool) error {
+	tenants, err := schemas.Tenants(ctx, pool)
+	if err != nil {
+		return err
+	}
+	for _, tenantID := range tenants {
+		if err = schemas.migrate(ctx, pool, tenantID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TenantSchema is a tenant with the migration version of its schema, 0 when it was never migrated
+type TenantSchema struct {
+	Tenant  string
+	Schema  string
+	Version uint
+	Dirty   bool
+}
+
+// Tenants returns the default tenant followed by the tenants with a schema, ordered by tenant
+func (schemas *TenantSchemas) Tenants(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
+	rows, err := pool.Query(ctx, "SELECT nspname FROM pg_namespace WHERE starts_with(nspname, $1) ORDER BY nspname",
+		TenantSchemaPrefix)
+	if err != nil {
+		return nil, err
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	tenants := []string{tenant.DefaultTenant}
+	for _, name := range names {
+		tenantID := strings.TrimPrefix(name, TenantSchemaPrefix)
+		// Schemas not named after a tenant are not ours, e.g. a base schema named tenant_shared
+		if name == schemas.base || !tenant.IsValid(tenantID) || tenantID == tenant.DefaultTenant {
+			continue
+		}
+		tenants = append(tenants, tenantID)
+	}
+	return tenants, nil
+}
+
+// List returns the tenants, see Tenants, with the migration versions of their schemas
+func (schemas *TenantSchemas) List(ctx context.Context, pool *pgxpool.Pool) ([]TenantSchema, error) {
+	tenants, err := schemas.Tenants(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	tenantSchemas := make([]TenantSchema, len(tenants))
+	for i, tenantID := range tenants {
+		tenantSchemas[i] = TenantSchema{Tenant: tenantID, Schema: schemas.Schema(tenantID)}
+		if err = readVersion(ctx, pool, &tenantSchemas[i]); err != nil {
+			return nil, err
+		}
+	}
+	return tenantSchemas, nil
+}
+
+// readVersion reads the version golang-migrate recorded in the schema_migrations table of the schema
+func readVersion(ctx context.Context, pool *pgxpool.Pool, tenantSchema *TenantSchema) error {
+	table := pgx.Identifier{tenantSchema.Schema, "schema_migrations"}.Sanitize()
+	var exists bool
+	if err := pool.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists); err != nil || !exists {
+		return err
+	}
+	var version int64
+	err := pool.QueryRow(ctx, "SELECT version, dirty FROM "+table+" LIMIT 1").Scan(&version, &tenantSchema.Dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	tenantSchema.Version = uint(version)
+	return err
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrConflict is returned when a write conflicts with an existing row, e.g. a duplicate membership
+var ErrConflict = errors.New("conflict")
+
+// The SQLSTATE codes of the constraint violations callers can act on
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+// constraintError maps the constraint violations of err to the errors of the repository: a missing referenced
+// row is pgx.ErrNoRows with the notFound message, a duplicate row is ErrConflict with the conflict message
+func constraintError(err error, notFound, conflict string) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case foreignKeyViolation:
+		return fmt.Errorf("%w: %s", pgx.ErrNoRows, notFound)
+	case uniqueViolation:
+		return fmt.Errorf("%w: %s", ErrConflict, conflict)
+	}
+	return err
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"crud/internal/model"
+	"crud/internal/tenant"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type IJobRepository interface {
+	// Enqueue stores the job for the tenant of ctx, in the transaction of ctx when there is one. When a pending or
+	// running job of the type holds the unique key of the job, nothing is stored and that job is returned
+	Enqueue(job *model.JobModel, ctx *context.Context) (*model.JobModel, error)
+	// ClaimDue returns due jobs of the types and leases them for twice their timeout, so that other workers skip them
+	// while they run. Running jobs whose lease expired are claimed again while they have attempts left
+	ClaimDue(types []string, limit int, ctx *context.Context) ([]*model.JobModel, error)
+	// FailExpired fails the running jobs of the types whose lease expired after their last attempt
+	FailExpired(types []string, ctx *context.Context) (int64, error)
+	// MarkSucceeded, Reschedule, MarkFailed and Release record the outcome of the attempt of a claimed job. A job
+	// claimed again since, because its lease expired, is left alone
+	MarkSucceeded(job *model.JobModel, ctx *context.Context) error
+	// Reschedule makes the job pending again, it runs at runAt
+	Reschedule(job *model.JobModel, lastError string, runAt time.Time, ctx *context.Context) error
+	// MarkFailed stops retrying the job until it is retried by an administrator
+	MarkFailed(job *model.JobModel, lastError string, ctx *context.Context) error
+	// Release makes an interrupted job pending again, the attempt is not counted
+	Release(job *model.JobModel, ctx *context.Context) error
+	// GetAll returns the jobs of the tenant of ctx with the status, of any status when it is empty, newest first
+	GetAll(status string, offset, limit int, ctx *context.Context) ([]*model.JobModel, error)
+	// Retry makes a failed job of the tenant of ctx pending again with a fresh count of attempts. pgx.ErrNoRows is
+	// returned for unknown jobs and jobs which did not fail, ErrConflict when a pending or running job holds the
+	// unique key of the job
+	Retry(id int64, ctx *context.Context) (*model.JobModel, error)
+	// DeleteSucceeded deletes the jobs which succeeded before the given time
+	DeleteSucceeded(before time.Time, ctx *context.Context) (int64, error)
+}
+
+type JobRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewJobRepository(pool *pgxpool.Pool) IJobRepository {
+	return &JobRepository{dbPool: pool}
+}
+
+const jobColumns = "id, tenant_id, type, payload, status, unique_key, attempts, max_attempts, timeout_ms, run_at, " +
+	"locked_until, last_error, created_at, finished_at"
+
+func scanJob(row rowScanner) (*model.JobModel, error) {
+	job := &model.JobModel{}
+	var timeoutMs int64
+	err := row.Scan(&job.ID, &job.TenantID, &job.Type, &job.Payload, &job.Status, &job.UniqueKey, &job.Attempts,
+		&job.MaxAttempts, &timeoutMs, &job.RunAt, &job.LockedUntil, &job.LastError, &job.CreatedAt, &job.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	job.Timeout = time.Duration(timeoutMs) * time.Millisecond
+	return job, nil
+}
+
+func scanJobs(rows pgx.Rows) ([]*model.JobModel, error) {
+	defer rows.Close()
+	jobs := make([]*model.JobModel, 0)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (repository *JobRepository) Enqueue(job *model.JobModel, ctx *context.Context) (*model.JobModel, error) {
+	tenantID, ok := tenant.FromContext(*ctx)
+	if !ok {
+		return nil, tenant.ErrMissingTenant
+	}
+	var runAt *time.Time
+	if !job.RunAt.IsZero() {
+		runAt = &job.RunAt
+	}
+	q := conn(*ctx, repository.dbPool)
+	enqueued, err := scanJob(q.QueryRow(*ctx, `
+		INSERT INTO jobs(tenant_id, type, payload, unique_key, max_attempts, timeout_ms, run_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, now()))
+		ON CONFLICT (tenant_id, type, unique_key) WHERE unique_key IS NOT NULL AND status IN ('pending', 'running')
+		DO NOTHING
+		RETURNING `+jobColumns,
+		tenantID, job.Type, job.Payload, job.UniqueKey, job.MaxAttempts, job.Timeout.Milliseconds(), runAt))
+	if !errors.Is(err, pgx.ErrNoRows) || job.UniqueKey == nil {
+		return enqueued, err
+	}
+	return scanJob(q.QueryRow(*ctx, `
+		SELECT `+jobColumns+` FROM jobs
+		WHERE tenant_id = $1 AND type = $2 AND unique_key = $3 AND status IN ('pending', 'running')`,
+		tenantID, job.Type, *job.UniqueKey))
+}
+
+func (repository *JobRepository) ClaimDue(types []string, limit int, ctx *context.Context) ([]*model.JobModel, error) {
+	rows, err := conn(*ctx, repository.dbPool).Query(*ctx, `
+		UPDATE jobs SET status = 'running', attempts = attempts + 1,
+		locked_until = now() + timeout_ms * interval '2 milliseconds'
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE type = ANY($1) AND run_at <= now() AND (status = 'pending'
+				OR status = 'running' AND locked_until < now() AND attempts < max_attempts)
+			ORDER BY run_at, id LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns,
+		types, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanJobs(rows)
+}
+
+func (repository *JobRepository) FailExpired(types []string, ctx *context.Context) (int64, error) {
+	tag, err := conn(*ctx, repository.dbPool).Exec(*ctx, `
+		UPDATE jobs SET status = 'failed', locked_until = NULL, finished_at = now(),
+		last_error = 'the job did not finish before its lease expired'
+		WHERE status = 'running' AND locked_until < now() AND attempts >= max_attempts AND type = ANY($1)`,
+		types)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (repository *JobRepository) MarkSucceeded(job *model.JobModel, ctx *context.Context) error {
+	_, err := conn(*ctx, repository.dbPool).Exec(*ctx, `
+		UPDATE jobs SET status = 'succeeded', locked_until = NULL, last_error = NULL, finished_at = now()
+		WHERE id = $1 AND status = 'running' AND attempts = $2`,
+		job.ID, job.Attempts)
+	return err
+}
+
+func (repository *JobRepository) Reschedule(job *model.JobModel, lastError string, runAt time.Time, ctx *context.Context) error {
+	_, err := conn(*ctx, repository.dbPool).Exec(*ctx, `
+		UPDATE jobs SET status = 'pending', locked_until = NULL, last_error = $3, run_at = $4
+		WHERE id = $1 AND status = 'running' AND attempts = $2`,
+		job.ID, job.Attempts, lastError, runAt)
+	return err
+}
+
+func (repository *JobRepository) MarkFailed(job *model.JobModel, lastError string, ctx *context.Context) error {
+	_, err := conn(*ctx, repository.dbPool).Exec(*ctx, `
+		UPDATE jobs SET status = 'failed', locked_until = NULL, last_error = $3, finished_at = now()
+		WHERE id = $1 AND status = 'running' AND attempts = $2`,
+		job.ID, job.Attempts, lastError)
+	return err
+}
+
+func (repository *JobRepository) Release(job *model.JobModel, ctx *context.Context) error {
+	_, err := conn(*ctx, repository.dbPool).Exec(*ctx, `
+		UPDATE jobs SET status = 'pending', locked_until = NULL, attempts = attempts - 1
+		WHERE id = $1 AND status = 'running' AND attempts = $2`,
+		job.ID, job.Attempts)
+	return err
+}
+
+func (repository *JobRepository) GetAll(status string, offset, limit int, ctx *context.Context) ([]*model.JobModel, error) {
+	var jobs []*model.JobModel
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		rows, err := q.Query(*ctx, `
+			SELECT `+jobColumns+` FROM jobs
+			WHERE tenant_id = $1 AND ($2 = '' OR status = $2)
+			ORDER BY id DESC LIMIT $3 OFFSET $4`,
+			tenantID, status, limit, offset)
+		if err != nil {
+			return err
+		}
+		jobs, err = scanJobs(rows)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (repository *JobRepository) Retry(id int64, ctx *context.Context) (*model.JobModel, error) {
+	var retried *model.JobModel
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		var err error
+		retried, err = scanJob(q.QueryRow(*ctx, `
+			UPDATE jobs SET status = 'pending', attempts = 0, run_at = now(), finished_at = NULL
+			WHERE id = $1 AND tenant_id = $2 AND status = 'failed'
+			RETURNING `+jobColumns,
+			id, tenantID))
+		return constraintError(err, "unknown job", "another job holding its unique key is pending or running")
+	})
+	return retried, err
+}
+
+func (repository *JobRepository) DeleteSucceeded(before time.Time, ctx *context.Context) (int64, error) {
+	tag, err := conn(*ctx, repository.dbPool).Exec(*ctx,
+		"DELETE FROM jobs WHERE status = 'succeeded' AND finished_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"crud/internal/model"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IOrganizationRepository interface {
+	IRepository[model.OrganizationModel, int]
+}
+
+// organizationTable stores the organizations of each tenant, deleting one deletes its memberships
+var organizationTable = Table[model.OrganizationModel]{
+	Name: "organizations",
+	ID:   Column[model.OrganizationModel]{Name: "id", Field: func(organization *model.OrganizationModel) any { return &organization.ID }},
+	Columns: []Column[model.OrganizationModel]{
+		{Name: "name", Field: func(organization *model.OrganizationModel) any { return &organization.Name }},
+		{Name: "created_at", Field: func(organization *model.OrganizationModel) any { return &organization.CreatedAt }, Generated: true},
+	},
+	TenantScoped: true,
+}
+
+func NewOrganizationRepository(pool *pgxpool.Pool) IOrganizationRepository {
+	return NewRepository[model.OrganizationModel, int](pool, organizationTable)
+}
+
+type IMembershipRepository interface {
+	// LockOrganization locks the organization row until the end of the transaction so that concurrent
+	// membership changes of the organization are serialized, pgx.ErrNoRows is returned for unknown organizations
+	LockOrganization(organizationID int, ctx *context.Context) error
+	// Add returns pgx.ErrNoRows when the user or the organization is unknown, ErrConflict when the user is
+	// a member already
+	Add(membership *model.MembershipModel, ctx *context.Context) (*model.MembershipModel, error)
+	// GetRole returns the role of the user in the organization, pgx.ErrNoRows when the user is not a member
+	GetRole(organizationID int, userID int, ctx *context.Context) (string, error)
+	Remove(organizationID int, userID int, ctx *context.Context) (*model.MembershipModel, error)
+	CountOwners(organizationID int, ctx *context.Context) (int, error)
+	GetMembers(organizationID int, offset, limit int, ctx *context.Context) ([]*model.MemberModel, error)
+	GetUserOrganizations(userID int, offset, limit int, ctx *context.Context) ([]*model.UserOrganizationModel, error)
+}
+
+type MembershipRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewMembershipRepository(pool *pgxpool.Pool) IMembershipRepository {
+	return &MembershipRepository{dbPool: pool}
+}
+
+const membershipColumns = "organization_id, user_id, role, created_at"
+
+func scanMembership(row rowScanner) (*model.MembershipModel, error) {
+	membership := &model.MembershipModel{}
+	err := row.Scan(&membership.OrganizationID, &membership.UserID, &membership.Role, &membership.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+func (repository *MembershipRepository) LockOrganization(organizationID int, ctx *context.Context) error {
+	return scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		var id int
+		return q.QueryRow(*ctx, "SELECT id FROM organizations WHERE id = $1 AND tenant_id = $2 FOR UPDATE",
+			organizationID, tenantID).Scan(&id)
+	})
+}
+
+// Add stores the membership in the tenant of ctx, the user and the organization must belong to the tenant too
+func (repository *MembershipRepository) Add(membership *model.MembershipModel, ctx *context.Context) (*model.MembershipModel, error) {
+	var added *model.MembershipModel
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		var err error
+		added, err = scanMembership(q.QueryRow(*ctx,
+			"INSERT INTO organization_memberships(organization_id, user_id, role, tenant_id) values($1, $2, $3, $4) RETURNING "+membershipColumns,
+			membership.OrganizationID, membership.UserID, membership.Role, tenantID))
+		return constraintError(err, "unknown user or organization", "the user is a member of the organization already")
+	})
+	return added, err
+}
+
+func (repository *MembershipRepository) GetRole(organizationID int, userID int, ctx *context.Context) (string, error) {
+	var role string
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		return q.QueryRow(*ctx,
+			"SELECT role FROM organization_memberships WHERE organization_id = $1 AND user_id = $2 AND tenant_id = $3",
+			organizationID, userID, tenantID).Scan(&role)
+	})
+	return role, err
+}
+
+func (repository *MembershipRepository) Remove(organizationID int, userID int, ctx *context.Context) (*model.MembershipModel, error) {
+	var removed *model.MembershipModel
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		var err error
+		removed, err = scanMembership(q.QueryRow(*ctx,
+			"DELETE FROM organization_memberships WHERE organization_id = $1 AND user_id = $2 AND tenant_id = $3 RETURNING "+membershipColumns,
+			organizationID, userID, tenantID))
+		return err
+	})
+	return removed, err
+}
+
+func (repository *MembershipRepository) CountOwners(organizationID int, ctx *context.Context) (int, error) {
+	var owners int
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		return q.QueryRow(*ctx,
+			"SELECT count(*) FROM organization_memberships WHERE organization_id = $1 AND role = $2 AND tenant_id = $3",
+			organizationID, model.OrganizationRoleOwner, tenantID).Scan(&owners)
+	})
+	return owners, err
+}
+
+func (repository *MembershipRepository) GetMembers(organizationID int, offset, limit int, ctx *context.Context) ([]*model.MemberModel, error) {
+	members := make([]*model.MemberModel, 0)
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		rows, err := q.Query(*ctx, `
+			SELECT u.id, u.name, u.email, m.role, m.created_at
+			FROM organization_memberships m JOIN users u ON u.id = m.user_id AND u.tenant_id = m.tenant_id
+			WHERE m.organization_id = $1 AND m.tenant_id = $2
+			ORDER BY u.id LIMIT $3 OFFSET $4`, organizationID, tenantID, limit, offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			member := &model.MemberModel{}
+			if err = rows.Scan(&member.UserID, &member.Name, &member.Email, &member.Role, &member.CreatedAt); err != nil {
+				return err
+			}
+			members = append(members, member)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (repository *MembershipRepository) GetUserOrganizations(userID int, offset, limit int, ctx *context.Context) ([]*model.UserOrganizationModel, error) {
+	organizations := make([]*model.UserOrganizationModel, 0)
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		rows, err := q.Query(*ctx, `
+			SELECT o.id, o.name, o.created_at, m.role
+			FROM organization_memberships m JOIN organizations o ON o.id = m.organization_id AND o.tenant_id = m.tenant_id
+			WHERE m.user_id = $1 AND m.tenant_id = $2
+			ORDER BY o.id LIMIT $3 OFFSET $4`, userID, tenantID, limit, offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			organization := &model.UserOrganizationModel{}
+			if err = rows.Scan(&organization.ID, &organization.Name, &organization.CreatedAt, &organization.Role); err != nil {
+				return err
+			}
+			organizations = append(organizations, organization)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
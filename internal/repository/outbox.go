@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"crud/internal/model"
+	"crud/internal/tenant"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"slices"
+	"time"
+)
+
+// outboxLockID is the advisory lock serializing the relays of all replicas, so events leave in order
+const outboxLockID = 4_606_101
+
+type IOutboxRepository interface {
+	// Append stores the event for the tenant and the request of ctx, in the transaction of ctx when there is one
+	Append(event *model.OutboxEventModel, ctx *context.Context) (*model.OutboxEventModel, error)
+	// TryLock takes the relay lock until the transaction of ctx ends, it reports false when another relay holds it
+	TryLock(ctx *context.Context) (bool, error)
+	// ClaimDue returns the oldest pending event of each aggregate when it is due, oldest first, and postpones them
+	// to leaseUntil, so that they are published again when the relay does not record the outcome in time
+	ClaimDue(limit int, leaseUntil time.Time, ctx *context.Context) ([]*model.OutboxEventModel, error)
+	MarkPublished(id int64, ctx *context.Context) error
+	// MarkFailed counts the failed attempt and schedules the next one
+	MarkFailed(id int64, nextAttemptAt time.Time, lastError string, ctx *context.Context) error
+	// MarkDead counts the failed attempt and stops retrying the event
+	MarkDead(id int64, lastError string, ctx *context.Context) error
+	// DeletePublished deletes the events published before the given time
+	DeletePublished(before time.Time, ctx *context.Context) (int64, error)
+}
+
+type OutboxRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewOutboxRepository(pool *pgxpool.Pool) IOutboxRepository {
+	return &OutboxRepository{dbPool: pool}
+}
+
+const outboxColumns = "id, tenant_id, aggregate_type, aggregate_id, event_type, payload, created_at, attempts, " +
+	"next_attempt_at, last_error, published_at, failed_at, request_id"
+
+func scanOutboxEvent(row rowScanner) (*model.OutboxEventModel, error) {
+	event := &model.OutboxEventModel{}
+	err := row.Scan(&event.ID, &event.TenantID, &event.AggregateType, &event.AggregateID, &event.Type, &event.Payload,
+		&event.CreatedAt, &event.Attempts, &event.NextAttemptAt, &event.LastError, &event.PublishedAt, &event.FailedAt, &event.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (repository *OutboxRepository) Append(event *model.OutboxEventModel, ctx *context.Context) (*model.OutboxEventModel, error) {
+	tenantID, ok := tenant.FromContext(*ctx)
+	if !ok {
+		return nil, tenant.ErrMissingTenant
+	}
+	row := conn(*ctx, repository.dbPool).QueryRow(*ctx,
+		"INSERT INTO outbox_events(tenant_id, aggregate_type, aggregate_id, event_type, payload, request_id) values($1, $2, $3, $4, $5, $6) RETURNING "+outboxColumns,
+		tenantID, event.AggregateType, event.AggregateID, event.Type, event.Payload, requestIDArg(*ctx))
+	return scanOutboxEvent(row)
+}
+
+func (repository *OutboxRepository) TryLock(ctx *context.Context) (bool, error) {
+	var locked bool
+	err := conn(*ctx, repository.dbPool).QueryRow(*ctx, "SELECT pg_try_advisory_xact_lock($1)", outboxLockID).Scan(&locked)
+	return locked, err
+}
+
+// ClaimDue skips the aggregates with an earlier pending event, a failing event holds back the later events
+// of its aggregate until it is delivered or dead
+func (repository *OutboxRepository) ClaimDue(limit int, leaseUntil time.Time, ctx *context.Context) ([]*model.OutboxEventModel, error) {
+	rows, err := conn(*ctx, repository.dbPool).Query(*ctx, `
+		UPDATE outbox_events SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM outbox_events event
+			WHERE published_at IS NULL AND failed_at IS NULL AND next_attempt_at <= now()
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_events earlier
+				WHERE earlier.published_at IS NULL AND earlier.failed_at IS NULL
+				AND earlier.aggregate_type = event.aggregate_type
+				AND earlier.aggregate_id = event.aggregate_id AND earlier.id < event.id
+			)
+			ORDER BY id LIMIT $1
+		)
+		RETURNING `+outboxColumns,
+		limit, leaseUntil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	events := make([]*model.OutboxEventModel, 0)
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(events, func(a, b *model.OutboxEventModel) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
+
+func (repository *OutboxRepository) MarkPublished(id int64, ctx *context.Context) error {
+	_, err := conn(*ctx, repository.dbPool).Exec(*ctx, "UPDATE outbox_events SET published_at = now() WHERE id = $1", id)
+	return err
+}
+
+func (repository *OutboxRepository) MarkFailed(id int64, nextAttemptAt time.Time, lastError string, ctx *context.Context) error {
+	_, err := conn(*ctx, repository.dbPool).Exec(*ctx,
+		"UPDATE outbox_events SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3 WHERE id = $1",
+		id, nextAttemptAt, lastError)
+	return err
+}
+
+func (repository *OutboxRepository) MarkDead(id int64, lastError string, ctx *context.Context) error {
+	_, err := conn(*ctx, repository.dbPool).Exec(*ctx,
+		"UPDATE outbox_events SET attempts = attempts + 1, failed_at = now(), last_error = $2 WHERE id = $1",
+		id, lastError)
+	return err
+}
+
+func (repository *OutboxRepository) DeletePublished(before time.Time, ctx *context.Context) (int64, error) {
+	tag, err := conn(*ctx, repository.dbPool).Exec(*ctx, "DELETE FROM outbox_events WHERE published_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"crud/internal/querystats"
+	"crud/internal/tenant"
+	"encoding/json"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IQueryPlanRepository interface {
+	Explain(sql string, args []any, ctx *context.Context) (json.RawMessage, error)
+}
+
+type QueryPlanRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewQueryPlanRepository(pool *pgxpool.Pool) IQueryPlanRepository {
+	return &QueryPlanRepository{dbPool: pool}
+}
+
+// Explain returns the plan of a statement without running it. The EXPLAIN runs in a read only
+// transaction which is rolled back, and it is left out of the query statistics. The transaction is scoped
+// to the tenant of ctx, if any, the connection has its schema on the search_path in the schema per tenant mode
+func (repository *QueryPlanRepository) Explain(sql string, args []any, ctx *context.Context) (json.RawMessage, error) {
+	explainCtx := querystats.WithoutStats(*ctx)
+	tx, err := repository.dbPool.BeginTx(explainCtx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(explainCtx) }()
+	if tenantID, ok := tenant.FromContext(explainCtx); ok {
+		if err = setTenant(explainCtx, tx, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	var plan json.RawMessage
+	err = tx.QueryRow(explainCtx, "EXPLAIN (FORMAT JSON) "+sql, args...).Scan(&plan)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "context"
+
+type sensitiveArgsKey struct{}
+
+// WithSensitiveArgs marks the queries run with ctx as carrying secrets, e.g. password hashes,
+// so that query tracers do not log their arguments
+func WithSensitiveArgs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sensitiveArgsKey{}, true)
+}
+
+// HasSensitiveArgs reports whether the query arguments must not be logged
+func HasSensitiveArgs(ctx context.Context) bool {
+	sensitive, _ := ctx.Value(sensitiveArgsKey{}).(bool)
+	return sensitive
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"crud/internal/model"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ISessionRepository interface {
+	Create(session *model.SessionModel, ctx *context.Context) (*model.SessionModel, error)
+	GetByTokenHash(tokenHash []byte, ctx *context.Context) (*model.SessionModel, error)
+	Revoke(id int, ctx *context.Context) error
+	RevokeAllForUser(userID int, exceptID int, ctx *context.Context) error
+	// DeleteInactive deletes the expired and revoked sessions, they authenticate no one anymore. It serves every
+	// tenant of the schema the connection for ctx uses
+	DeleteInactive(ctx *context.Context) (int64, error)
+}
+
+type SessionRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewSessionRepository(pool *pgxpool.Pool) ISessionRepository {
+	return &SessionRepository{dbPool: pool}
+}
+
+const sessionColumns = "id, user_id, tenant_id, token_hash, expires_at, revoked_at, created_at"
+
+func scanSession(row rowScanner) (*model.SessionModel, error) {
+	session := &model.SessionModel{}
+	err := row.Scan(&session.ID, &session.UserID, &session.TenantID, &session.TokenHash, &session.ExpiresAt, &session.RevokedAt, &session.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Create stores the session in the tenant of ctx
+func (repository *SessionRepository) Create(session *model.SessionModel, ctx *context.Context) (*model.SessionModel, error) {
+	var created *model.SessionModel
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		var err error
+		created, err = scanSession(q.QueryRow(WithSensitiveArgs(*ctx),
+			"INSERT INTO user_sessions(user_id, tenant_id, token_hash, expires_at) values($1, $2, $3, $4) RETURNING "+sessionColumns,
+			session.UserID, tenantID, session.TokenHash, session.ExpiresAt))
+		return err
+	})
+	return created, err
+}
+
+// GetByTokenHash looks the session up in every tenant, the session names the tenant of the caller
+func (repository *SessionRepository) GetByTokenHash(tokenHash []byte, ctx *context.Context) (*model.SessionModel, error) {
+	var session *model.SessionModel
+	err := unscoped(*ctx, repository.dbPool, func(q querier) error {
+		var err error
+		session, err = scanSession(q.QueryRow(WithSensitiveArgs(*ctx),
+			"SELECT "+sessionColumns+" FROM user_sessions WHERE token_hash = $1", tokenHash))
+		return err
+	})
+	return session, err
+}
+
+func (repository *SessionRepository) Revoke(id int, ctx *context.Context) error {
+	return scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		_, err := q.Exec(*ctx,
+			"UPDATE user_sessions SET revoked_at = now() WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL",
+			id, tenantID)
+		return err
+	})
+}
+
+// RevokeAllForUser revokes every active session of the user except exceptID, use 0 to revoke all of them
+func (repository *SessionRepository) RevokeAllForUser(userID int, exceptID int, ctx *context.Context) error {
+	return scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		_, err := q.Exec(*ctx,
+			"UPDATE user_sessions SET revoked_at = now() WHERE user_id = $1 AND id <> $2 AND tenant_id = $3 AND revoked_at IS NULL",
+			userID, exceptID, tenantID)
+		return err
+	})
+}
+
+func (repository *SessionRepository) DeleteInactive(ctx *context.Context) (int64, error) {
+	var deleted int64
+	err := unscoped(*ctx, repository.dbPool, func(q querier) error {
+		tag, err := q.Exec(*ctx, "DELETE FROM user_sessions WHERE revoked_at IS NOT NULL OR expires_at <= now()")
+		deleted = tag.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
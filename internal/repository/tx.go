@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"crud/internal/tenant"
+	"crud/internal/util/request"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"strings"
+)
+
+// ITransactor runs units of work in a database transaction
+type ITransactor interface {
+	// InTx runs fn in a transaction which is committed when fn returns nil and rolled back otherwise.
+	// The repositories called with the context passed to fn take part in the transaction, a nested
+	// InTx joins the transaction of its caller. The transaction is scoped to the tenant of ctx, if any
+	InTx(ctx *context.Context, fn func(ctx *context.Context) error) error
+}
+
+type Transactor struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewTransactor(pool *pgxpool.Pool) ITransactor {
+	return &Transactor{dbPool: pool}
+}
+
+func (transactor *Transactor) InTx(ctx *context.Context, fn func(ctx *context.Context) error) error {
+	if _, ok := (*ctx).Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+	return pgx.BeginFunc(*ctx, transactor.dbPool, func(tx pgx.Tx) error {
+		tenantID, ok := tenant.FromContext(*ctx)
+		if ok {
+			if err := setTenant(*ctx, tx, tenantID); err != nil {
+				return err
+			}
+		} else if err := setLocal(*ctx, tx, requestSettings(*ctx)...); err != nil {
+			return err
+		}
+		txCtx := context.WithValue(*ctx, txKey{}, tx)
+		return fn(&txCtx)
+	})
+}
+
+type txKey struct{}
+
+// querier is implemented by the pool and by transactions
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn returns the transaction started by ITransactor.InTx for ctx, or the pool outside of transactions
+func conn(ctx context.Context, pool *pgxpool.Pool) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
+
+// requestSettings returns the request id of ctx as application_name setting, so that pg_stat_activity and the server
+// logs (%a in log_line_prefix) point to the request which ran the statements of a transaction. Statements run outside
+// of requests and outside of transactions are not tagged, PostgreSQL truncates names longer than 63 bytes
+func requestSettings(ctx context.Context) []string {
+	requestID := request.GetRequestID(ctx)
+	if !request.IsValidRequestID(requestID) {
+		return nil
+	}
+	return []string{"application_name", requestID}
+}
+
+// setLocal is SET LOCAL for the name and value pairs of settings. set_config takes them as query arguments, the text
+// of the statement only depends on their number and is prepared once by the statement cache
+func setLocal(ctx context.Context, q querier, settings ...string) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	calls := make([]string, 0, len(settings)/2)
+	args := make([]any, 0, len(settings))
+	for i := 0; i+1 < len(settings); i += 2 {
+		calls = append(calls, fmt.Sprintf("set_config($%d, $%d, true)", i+1, i+2))
+		args = append(args, settings[i], settings[i+1])
+	}
+	_, err := q.Exec(ctx, "SELECT "+strings.Join(calls, ", "), args...)
+	return err
+}
+
+// requestIDArg is the request id of ctx as query argument, NULL outside of requests
+func requestIDArg(ctx context.Context) *string {
+	requestID := request.GetRequestID(ctx)
+	if !request.IsValidRequestID(requestID) {
+		return nil
+	}
+	return &requestID
+}
+
+// scoped runs fn with a connection scoped to the tenant of ctx, which is required. Outside of ITransactor.InTx
+// it starts a transaction for fn, the tenant setting the row level security policies check lives as long as it
+func scoped(ctx context.Context, pool *pgxpool.Pool, fn func(q querier, tenantID string) error) error {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return tenant.ErrMissingTenant
+	}
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(tx, tenantID)
+	}
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if err := setTenant(ctx, tx, tenantID); err != nil {
+			return err
+		}
+		return fn(tx, tenantID)
+	})
+}
+
+// setTenant is SET LOCAL app.tenant_id, the request id of ctx is set along, see requestSettings
+func setTenant(ctx context.Context, tx pgx.Tx, tenantID string) error {
+	return setLocal(ctx, tx, append([]string{"app.tenant_id", tenantID}, requestSettings(ctx)...)...)
+}
+
+// unscoped runs fn with a connection which sees the rows of every tenant, the policies of the credential, webhook
+// and change log tables let app.all_tenants through. It is meant for the lookups of credentials by their secret,
+// which find the tenant of the caller, and for the workers serving every tenant. Outside of ITransactor.InTx it
+// starts a transaction for fn. Within it fn runs in a savepoint and the setting is restored after fn, the later
+// statements of the transaction are scoped to its tenant again. A failing fn rolls back to the savepoint
+func unscoped(ctx context.Context, pool *pgxpool.Pool, fn func(q querier) error) error {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return pgx.BeginFunc(ctx, tx, func(nested pgx.Tx) error {
+			var previous string
+			err := nested.QueryRow(ctx, "SELECT coalesce(current_setting('app.all_tenants', true), '')").Scan(&previous)
+			if err != nil {
+				return err
+			}
+			if err = setAllTenants(ctx, nested); err != nil {
+				return err
+			}
+			if err = fn(nested); err != nil {
+				return err
+			}
+			return setLocal(ctx, nested, "app.all_tenants", previous)
+		})
+	}
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if err := setAllTenants(ctx, tx); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// setAllTenants is SET LOCAL app.all_tenants = on, the request id of ctx is set along, see requestSettings
+func setAllTenants(ctx context.Context, q querier) error {
+	return setLocal(ctx, q, append([]string{"app.all_tenants", "on"}, requestSettings(ctx)...)...)
+}
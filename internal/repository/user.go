@@ -3,84 +3,66 @@ package repository
 import (
 	"context"
 	"crud/internal/model"
+	"fmt"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"log/slog"
 )
 
 type IUserRepository interface {
-	Create(user *model.UserModel, ctx *context.Context) (*model.UserModel, error)
-	GetById(id int, ctx *context.Context) (*model.UserModel, error)
-	Update(user *model.UserModel, ctx *context.Context) (*model.UserModel, error)
-	Delete(id int, ctx *context.Context) (*model.UserModel, error)
-	GetAll(offset, limit int, ctx *context.Context) ([]*model.UserModel, error)
+	IRepository[model.UserModel, int]
+	// Find returns the users matching the filter, ordered by id
+	Find(filter *model.UserFilter, limit int, ctx *context.Context) ([]*model.UserModel, error)
 }
 
-type UserRepository struct {
-	dbPool *pgxpool.Pool
-}
-
-func NewUserRepository(pool *pgxpool.Pool) IUserRepository {
-	return &UserRepository{dbPool: pool}
-}
-
-func (repository *UserRepository) Create(user *model.UserModel, ctx *context.Context) (*model.UserModel, error) {
-	row := repository.dbPool.QueryRow(*ctx,
-		"INSERT INTO users(name, email, age) values($1, $2, $3) RETURNING id",
-		user.Name, user.Email, user.Age)
-	response := *user
-	err := row.Scan(&response.ID)
-	if err != nil {
-		return nil, err
-	}
-	return &response, nil
-}
-
-func (repository *UserRepository) GetById(id int, ctx *context.Context) (*model.UserModel, error) {
-	row := repository.dbPool.QueryRow(*ctx, "SELECT id, name, email, age FROM users WHERE id = $1",
-		id)
-	response := model.UserModel{}
-	err := row.Scan(&response.ID, &response.Name, &response.Email, &response.Age)
-	if err != nil {
-		return nil, err
-	}
-	return &response, nil
+// userTable stores the users of each tenant, the email is set on create only
+var userTable = Table[model.UserModel]{
+	Name: "users",
+	ID:   Column[model.UserModel]{Name: "id", Field: func(user *model.UserModel) any { return &user.ID }},
+	Columns: []Column[model.UserModel]{
+		{Name: "name", Field: func(user *model.UserModel) any { return &user.Name }},
+		{Name: "email", Field: func(user *model.UserModel) any { return &user.Email }, Immutable: true},
+		{Name: "age", Field: func(user *model.UserModel) any { return &user.Age }},
+	},
+	TenantScoped: true,
 }
 
-func (repository *UserRepository) Update(user *model.UserModel, ctx *context.Context) (*model.UserModel, error) {
-	row := repository.dbPool.QueryRow(*ctx,
-		"UPDATE users SET name = $1, age = $2 WHERE id = $3 RETURNING id, name, email, age",
-		user.Name, user.Age, user.ID)
-	response := model.UserModel{}
-	err := row.Scan(&response.ID, &response.Name, &response.Email, &response.Age)
-	if err != nil {
-		return nil, err
-	}
-	return &response, nil
+type UserRepository struct {
+	*Repository[model.UserModel, int]
+	logger *slog.Logger
 }
 
-func (repository *UserRepository) Delete(id int, ctx *context.Context) (*model.UserModel, error) {
-	row := repository.dbPool.QueryRow(*ctx,
-		"DELETE FROM users WHERE id = $1 RETURNING id, name, email, age", id)
-	response := model.UserModel{}
-	err := row.Scan(&response.ID, &response.Name, &response.Email, &response.Age)
-	if err != nil {
-		return nil, err
-	}
-	return &response, nil
+func NewUserRepository(pool *pgxpool.Pool, logger *slog.Logger) IUserRepository {
+	return &UserRepository{Repository: NewRepository[model.UserModel, int](pool, userTable), logger: logger}
 }
 
-func (repository *UserRepository) GetAll(offset, limit int, ctx *context.Context) ([]*model.UserModel, error) {
-	rows, err := repository.dbPool.Query(*ctx, "SELECT * FROM users LIMIT $1 OFFSET $2",
-		limit, offset)
-	if err != nil {
-		return nil, err
-	}
+// Find selects the users the way model.UserFilter.Matches does, strpos instead of LIKE keeps % and _ in the name literal
+func (repository *UserRepository) Find(filter *model.UserFilter, limit int, ctx *context.Context) ([]*model.UserModel, error) {
 	users := make([]*model.UserModel, 0)
-	for rows.Next() {
-		user := &model.UserModel{}
-		if err = rows.Scan(&user.ID, &user.Name, &user.Email, &user.Age); err != nil {
-			return nil, err
+	err := repository.run(*ctx, func(q querier, tenantID string) error {
+		args := []any{filter.Name, filter.Email, filter.MinAge, filter.MaxAge, limit}
+		sql := fmt.Sprintf(`
+			SELECT %s FROM %s
+			WHERE ($1 = '' OR strpos(lower(name), lower($1)) > 0) AND ($2 = '' OR lower(email) = lower($2))
+				AND ($3::int IS NULL OR age >= $3) AND ($4::int IS NULL OR age <= $4)%s
+			ORDER BY id LIMIT $5`,
+			repository.selectColumns, repository.table.Name, repository.tenantFilter(tenantID, &args))
+		rows, err := q.Query(*ctx, sql, args...)
+		if err != nil {
+			return err
 		}
-		users = append(users, user)
+		defer rows.Close()
+		for rows.Next() {
+			user := &model.UserModel{}
+			if err = rows.Scan(repository.fields(user)...); err != nil {
+				return err
+			}
+			users = append(users, user)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
+	repository.logger.DebugContext(*ctx, "found users", slog.Int("count", len(users)), slog.Int("limit", limit))
 	return users, nil
 }
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"crud/internal/model"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IUserChangeRepository reads the log of user changes written by the users_log_change trigger
+type IUserChangeRepository interface {
+	// GetAfter returns the changes of the tenant of ctx after the given id, oldest first, of the given types or of all
+	// types when there are none
+	GetAfter(afterID int64, types []string, limit int, ctx *context.Context) ([]*model.EventMessage, error)
+	// OldestID returns the id of the oldest change of the tenant of ctx, 0 when it has none. The ids are shared by
+	// all tenants, so a gap before it does not mean that changes of the tenant were trimmed
+	OldestID(ctx *context.Context) (int64, error)
+	// Trim deletes all but the newest keep changes of every tenant in the log
+	Trim(keep int, ctx *context.Context) (int64, error)
+}
+
+type UserChangeRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewUserChangeRepository(pool *pgxpool.Pool) IUserChangeRepository {
+	return &UserChangeRepository{dbPool: pool}
+}
+
+func (repository *UserChangeRepository) GetAfter(afterID int64, types []string, limit int, ctx *context.Context) ([]*model.EventMessage, error) {
+	if types == nil {
+		types = []string{}
+	}
+	events := make([]*model.EventMessage, 0)
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		rows, err := q.Query(*ctx, `
+			SELECT id, tenant_id, user_id::text, event_type, payload, created_at FROM user_change_log
+			WHERE tenant_id = $1 AND id > $2 AND (cardinality($3::text[]) = 0 OR event_type = ANY($3))
+			ORDER BY id LIMIT $4`,
+			tenantID, afterID, types, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			event := &model.EventMessage{AggregateType: model.UserAggregate}
+			if err = rows.Scan(&event.ID, &event.TenantID, &event.AggregateID, &event.Type, &event.Payload, &event.OccurredAt); err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (repository *UserChangeRepository) OldestID(ctx *context.Context) (int64, error) {
+	var oldestID int64
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		return q.QueryRow(*ctx, "SELECT coalesce(min(id), 0) FROM user_change_log WHERE tenant_id = $1", tenantID).
+			Scan(&oldestID)
+	})
+	return oldestID, err
+}
+
+func (repository *UserChangeRepository) Trim(keep int, ctx *context.Context) (int64, error) {
+	var deleted int64
+	err := unscoped(*ctx, repository.dbPool, func(q querier) error {
+		tag, err := q.Exec(*ctx, `
+			DELETE FROM user_change_log WHERE id IN (
+				SELECT id FROM (
+					SELECT id, ROW_NUMBER() OVER (PARTITION BY tenant_id ORDER BY id DESC) AS position FROM user_change_log
+				) ranked WHERE position > $1
+			)`,
+			keep)
+		deleted = tag.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"crud/internal/model"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type IWebhookSubscriptionRepository interface {
+	IRepository[model.WebhookSubscriptionModel, int]
+}
+
+// webhookSubscriptionTable stores the webhook subscriptions of each tenant, deleting one deletes its deliveries
+var webhookSubscriptionTable = Table[model.WebhookSubscriptionModel]{
+	Name: "webhook_subscriptions",
+	ID:   Column[model.WebhookSubscriptionModel]{Name: "id", Field: func(subscription *model.WebhookSubscriptionModel) any { return &subscription.ID }},
+	Columns: []Column[model.WebhookSubscriptionModel]{
+		{Name: "url", Field: func(subscription *model.WebhookSubscriptionModel) any { return &subscription.URL }},
+		{Name: "event_types", Field: func(subscription *model.WebhookSubscriptionModel) any { return &subscription.EventTypes }},
+		{Name: "secret", Field: func(subscription *model.WebhookSubscriptionModel) any { return &subscription.Secret }},
+		{Name: "created_at", Field: func(subscription *model.WebhookSubscriptionModel) any { return &subscription.CreatedAt }, Generated: true},
+	},
+	TenantScoped: true,
+}
+
+func NewWebhookSubscriptionRepository(pool *pgxpool.Pool) IWebhookSubscriptionRepository {
+	return NewRepository[model.WebhookSubscriptionModel, int](pool, webhookSubscriptionTable)
+}
+
+type IWebhookDeliveryRepository interface {
+	// Enqueue adds a pending delivery of the event for every subscription of its tenant listing its type, events
+	// enqueued before are skipped. It returns the number of deliveries added
+	Enqueue(event *model.EventMessage, payload []byte, ctx *context.Context) (int64, error)
+	// ClaimDue returns the due pending deliveries of every tenant with the URL and secret of their subscription and postpones them
+	// to leaseUntil, so that other dispatchers skip them while they are sent
+	ClaimDue(limit int, leaseUntil time.Time, ctx *context.Context) ([]*model.WebhookDeliveryModel, error)
+	MarkSucceeded(id int64, statusCode int, ctx *context.Context) error
+	// MarkFailed counts the failed attempt and schedules the next one
+	MarkFailed(id int64, statusCode *int, lastError string, nextAttemptAt time.Time, ctx *context.Context) error
+	// MarkDead counts the failed attempt and stops retrying the delivery
+	MarkDead(id int64, statusCode *int, lastError string, ctx *context.Context) error
+	// GetBySubscription returns the deliveries of a subscription of the tenant of ctx, newest first
+	GetBySubscription(subscriptionID int, offset, limit int, ctx *context.Context) ([]*model.WebhookDeliveryModel, error)
+	// Replay makes a delivery of the tenant of ctx pending again with a fresh count of attempts, pgx.ErrNoRows
+	// is returned for unknown deliveries
+	Replay(subscriptionID int, id int64, ctx *context.Context) (*model.WebhookDeliveryModel, error)
+}
+
+type WebhookDeliveryRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewWebhookDeliveryRepository(pool *pgxpool.Pool) IWebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{dbPool: pool}
+}
+
+const webhookDeliveryColumns = "delivery.id, delivery.tenant_id, delivery.subscription_id, delivery.event_id, delivery.event_type, " +
+	"delivery.payload, delivery.status, delivery.attempts, delivery.next_attempt_at, delivery.last_error, " +
+	"delivery.last_status_code, delivery.created_at, delivery.delivered_at, delivery.request_id"
+
+func webhookDeliveryFields(delivery *model.WebhookDeliveryModel) []any {
+	return []any{&delivery.ID, &delivery.TenantID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType,
+		&delivery.Payload, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastError,
+		&delivery.LastStatusCode, &delivery.CreatedAt, &delivery.DeliveredAt, &delivery.RequestID}
+}
+
+func scanWebhookDelivery(row rowScanner) (*model.WebhookDeliveryModel, error) {
+	delivery := &model.WebhookDeliveryModel{}
+	if err := row.Scan(webhookDeliveryFields(delivery)...); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Enqueue runs in a savepoint of unscoped, the relay keeps using its transaction when the deliveries cannot be written.
+// The relay serves every tenant, the subscriptions of the event's tenant are read unscoped
+func (repository *WebhookDeliveryRepository) Enqueue(event *model.EventMessage, payload []byte, ctx *context.Context) (int64, error) {
+	var enqueued int64
+	err := unscoped(*ctx, repository.dbPool, func(q querier) error {
+		tag, err := q.Exec(*ctx, `
+			INSERT INTO webhook_deliveries(tenant_id, subscription_id, event_id, event_type, payload, request_id)
+			SELECT tenant_id, id, $2, $3, $4, $6 FROM webhook_subscriptions
+			WHERE tenant_id = $1 AND ($3 = ANY(event_types) OR $5 = ANY(event_types))
+			ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+			event.TenantID, event.ID, event.Type, payload, model.WebhookAllEvents, requestIDArg(*ctx))
+		enqueued = tag.RowsAffected()
+		return err
+	})
+	return enqueued, err
+}
+
+func (repository *WebhookDeliveryRepository) ClaimDue(limit int, leaseUntil time.Time, ctx *context.Context) ([]*model.WebhookDeliveryModel, error) {
+	deliveries := make([]*model.WebhookDeliveryModel, 0)
+	err := unscoped(*ctx, repository.dbPool, func(q querier) error {
+		rows, err := q.Query(*ctx, `
+			UPDATE webhook_deliveries delivery SET next_attempt_at = $2
+			FROM webhook_subscriptions subscription
+			WHERE subscription.id = delivery.subscription_id AND delivery.id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= now()
+				ORDER BY next_attempt_at, id LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING `+webhookDeliveryColumns+`, subscription.url, subscription.secret`,
+			limit, leaseUntil)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			delivery := &model.WebhookDeliveryModel{}
+			if err = rows.Scan(append(webhookDeliveryFields(delivery), &delivery.URL, &delivery.Secret)...); err != nil {
+				return err
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (repository *WebhookDeliveryRepository) MarkSucceeded(id int64, statusCode int, ctx *context.Context) error {
+	_, err := conn(*ctx, repository.dbPool).Exec(*ctx, `
+		UPDATE webhook_deliveries SET status = 'succeeded', attempts = attempts + 1, last_status_code = $2,
+		last_error = NULL, delivered_at = now() WHERE id = $1`,
+		id, statusCode)
+	return err
+}
+
+func (repository *WebhookDeliveryRepository) MarkFailed(id int64, statusCode *int, lastError string, nextAttemptAt time.Time, ctx *context.Context) error {
+	_, err := conn(*ctx, repository.dbPool).Exec(*ctx, `
+		UPDATE webhook_deliveries SET attempts = attempts + 1, last_status_code = $2, last_error = $3,
+		next_attempt_at = $4 WHERE id = $1`,
+		id, statusCode, lastError, nextAttemptAt)
+	return err
+}
+
+func (repository *WebhookDeliveryRepository) MarkDead(id int64, statusCode *int, lastError string, ctx *context.Context) error {
+	_, err := conn(*ctx, repository.dbPool).Exec(*ctx, `
+		UPDATE webhook_deliveries SET status = 'dead', attempts = attempts + 1, last_status_code = $2,
+		last_error = $3 WHERE id = $1`,
+		id, statusCode, lastError)
+	return err
+}
+
+func (repository *WebhookDeliveryRepository) GetBySubscription(subscriptionID int, offset, limit int, ctx *context.Context) ([]*model.WebhookDeliveryModel, error) {
+	deliveries := make([]*model.WebhookDeliveryModel, 0)
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		rows, err := q.Query(*ctx, `
+			SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries delivery
+			WHERE delivery.subscription_id = $1 AND delivery.tenant_id = $2
+			ORDER BY delivery.id DESC LIMIT $3 OFFSET $4`,
+			subscriptionID, tenantID, limit, offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			delivery, err := scanWebhookDelivery(rows)
+			if err != nil {
+				return err
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (repository *WebhookDeliveryRepository) Replay(subscriptionID int, id int64, ctx *context.Context) (*model.WebhookDeliveryModel, error) {
+	var replayed *model.WebhookDeliveryModel
+	err := scoped(*ctx, repository.dbPool, func(q querier, tenantID string) error {
+		var err error
+		replayed, err = scanWebhookDelivery(q.QueryRow(*ctx, `
+			UPDATE webhook_deliveries delivery SET status = 'pending', attempts = 0, next_attempt_at = now(),
+			delivered_at = NULL WHERE delivery.id = $1 AND delivery.subscription_id = $2 AND delivery.tenant_id = $3
+			RETURNING `+webhookDeliveryColumns,
+			id, subscriptionID, tenantID))
+		return err
+	})
+	return replayed, err
+}
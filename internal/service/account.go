@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"crud/internal/tenant"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"sync"
+	"time"
+)
+
+// SessionTokenPrefix tells session tokens apart from JWTs in the Bearer scheme
+const SessionTokenPrefix = "crud_session_"
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrAccountLocked      = errors.New("account is temporarily locked")
+	ErrNotSessionCaller   = errors.New("only session tokens can be logged out")
+	ErrNotUserCaller      = errors.New("caller is not a user")
+)
+
+type IAccountService interface {
+	Login(request *model.LoginRequest, ctx *context.Context) (*model.LoginResponse, error)
+	Logout(ctx *context.Context) error
+	ChangePassword(request *model.ChangePasswordRequest, ctx *context.Context) error
+	SetPassword(userID int, request *model.SetPasswordRequest, ctx *context.Context) error
+}
+
+type AccountOptions struct {
+	MaxFailedLogins int
+	LockoutDuration time.Duration
+	SessionTTL      time.Duration
+}
+
+type AccountService struct {
+	credentialRepository repository.ICredentialRepository
+	sessionRepository    repository.ISessionRepository
+	options              AccountOptions
+}
+
+func NewAccountService(credentialRepository repository.ICredentialRepository, sessionRepository repository.ISessionRepository,
+	options AccountOptions) IAccountService {
+	return &AccountService{credentialRepository: credentialRepository, sessionRepository: sessionRepository, options: options}
+}
+
+var (
+	dummyPasswordHash     string
+	dummyPasswordHashOnce sync.Once
+)
+
+// verifyDummyPassword spends the same time as a real verification, so unknown emails cannot be told apart by timing
+func verifyDummyPassword(password string) {
+	dummyPasswordHashOnce.Do(func() {
+		dummyPasswordHash, _ = auth.HashPassword("dummy password")
+	})
+	_, _ = auth.VerifyPassword(password, dummyPasswordHash)
+}
+
+// Login authenticates the user in the tenant of ctx, the session is bound to the tenant
+func (service *AccountService) Login(request *model.LoginRequest, ctx *context.Context) (*model.LoginResponse, error) {
+	tenantID, ok := tenant.FromContext(*ctx)
+	if !ok {
+		return nil, tenant.ErrMissingTenant
+	}
+	credential, err := service.credentialRepository.GetByEmail(request.Email, ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		verifyDummyPassword(request.Password)
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+
+	// A locked account is rejected whatever the password, which is not verified, so that guesses during the lockout
+	// tell nothing. They take the time of a verification and count as failures, which prolongs the lockout
+	if credential.LockedUntil != nil && credential.LockedUntil.After(time.Now()) {
+		verifyDummyPassword(request.Password)
+		if _, err = service.credentialRepository.RecordFailedLogin(credential.UserID,
+			service.options.MaxFailedLogins, service.options.LockoutDuration, ctx); err != nil {
+			return nil, err
+		}
+		return nil, ErrAccountLocked
+	}
+	valid, err := auth.VerifyPassword(request.Password, credential.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		if _, err = service.credentialRepository.RecordFailedLogin(credential.UserID,
+			service.options.MaxFailedLogins, service.options.LockoutDuration, ctx); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+	if err = service.credentialRepository.ResetFailedLogins(credential.UserID, ctx); err != nil {
+		return nil, err
+	}
+
+	token, err := generateSessionToken()
+	if err != nil {
+		return nil, err
+	}
+	session, err := service.sessionRepository.Create(&model.SessionModel{
+		UserID:    credential.UserID,
+		TenantID:  tenantID,
+		TokenHash: hashSessionToken(token),
+		ExpiresAt: time.Now().Add(service.options.SessionTTL),
+	}, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &model.LoginResponse{Token: token, TokenType: "Bearer", ExpiresAt: session.ExpiresAt}, nil
+}
+
+func (service *AccountService) Logout(ctx *context.Context) error {
+	principal, ok := auth.PrincipalFromContext(*ctx)
+	sessionID, isSession := sessionIDFromPrincipal(principal)
+	if !ok || !isSession {
+		return ErrNotSessionCaller
+	}
+	return service.sessionRepository.Revoke(sessionID, ctx)
+}
+
+// ChangePassword changes the caller's own password and signs out its other sessions
+func (service *AccountService) ChangePassword(request *model.ChangePasswordRequest, ctx *context.Context) error {
+	principal, ok := auth.PrincipalFromContext(*ctx)
+	if !ok {
+		return ErrNotUserCaller
+	}
+	userID, isUser := principal.UserID()
+	if !isUser {
+		return ErrNotUserCaller
+	}
+	credential, err := service.credentialRepository.GetByUserID(userID, ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrInvalidCredentials
+	} else if err != nil {
+		return err
+	}
+	valid, err := auth.VerifyPassword(request.CurrentPassword, credential.PasswordHash)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrInvalidCredentials
+	}
+	if err = service.setPassword(userID, request.NewPassword, ctx); err != nil {
+		return err
+	}
+	currentSessionID, _ := sessionIDFromPrincipal(principal)
+	return service.sessionRepository.RevokeAllForUser(userID, currentSessionID, ctx)
+}
+
+// SetPassword sets the password of any user, it is meant for administrators and signs out all sessions of the user
+func (service *AccountService) SetPassword(userID int, request *model.SetPasswordRequest, ctx *context.Context) error {
+	if err := service.setPassword(userID, request.Password, ctx); err != nil {
+		return err
+	}
+	return service.sessionRepository.RevokeAllForUser(userID, 0, ctx)
+}
+
+func (service *AccountService) setPassword(userID int, password string, ctx *context.Context) error {
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return service.credentialRepository.SetPassword(userID, passwordHash, ctx)
+}
+
+func sessionIDFromPrincipal(principal *auth.Principal) (int, bool) {
+	if principal == nil || principal.Method != "session" {
+		return 0, false
+	}
+	sessionID, ok := principal.Claims["session_id"].(int)
+	return sessionID, ok
+}
+
+func generateSessionToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return SessionTokenPrefix + base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+func hashSessionToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/tenant"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testAccountOptions = AccountOptions{MaxFailedLogins: 3, LockoutDuration: time.Minute, SessionTTL: time.Hour}
+
+func TestUnitAccountServiceLogin(t *testing.T) {
+	t.Parallel()
+
+	passwordHash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	credential := &model.CredentialModel{UserID: 7, PasswordHash: passwordHash}
+
+	mockCredentials := mocks.NewMockICredentialRepository(t)
+	mockSessions := mocks.NewMockISessionRepository(t)
+	mockCredentials.EXPECT().GetByEmail("user@example.com", mock.Anything).Return(credential, nil)
+	mockCredentials.EXPECT().ResetFailedLogins(7, mock.Anything).Return(nil)
+	var stored *model.SessionModel
+	mockSessions.EXPECT().
+		Create(mock.Anything, mock.Anything).
+		RunAndReturn(func(session *model.SessionModel, _ *context.Context) (*model.SessionModel, error) {
+			stored = session
+			stored.ID = 3
+			return stored, nil
+		})
+
+	ctx := tenant.WithTenant(context.Background(), "acme")
+	response, err := NewAccountService(mockCredentials, mockSessions, testAccountOptions).
+		Login(&model.LoginRequest{Email: "user@example.com", Password: "correct horse"}, &ctx)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(response.Token, SessionTokenPrefix))
+	assert.Equal(t, hashSessionToken(response.Token), stored.TokenHash)
+	assert.Equal(t, "acme", stored.TenantID)
+
+	mockSessions.EXPECT().GetByTokenHash(stored.TokenHash, mock.Anything).Return(stored, nil)
+	principal, err := NewSessionAuthenticator(mockSessions, []string{"user"}, nil).Authenticate(ctx, response.Token)
+	require.NoError(t, err)
+	userID, ok := principal.UserID()
+	assert.True(t, ok)
+	assert.Equal(t, 7, userID)
+	assert.Equal(t, "acme", principal.Claims[tenant.ClaimName], "requests with the session are scoped to its tenant")
+}
+
+func TestUnitAccountServiceLoginFailures(t *testing.T) {
+	t.Parallel()
+
+	passwordHash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	lockedUntil := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name     string
+		setup    func(mockCredentials *mocks.MockICredentialRepository)
+		password string
+		expected error
+	}{
+		{"Unknown email", func(mockCredentials *mocks.MockICredentialRepository) {
+			mockCredentials.EXPECT().GetByEmail(mock.Anything, mock.Anything).Return(nil, pgx.ErrNoRows)
+		}, "correct horse", ErrInvalidCredentials},
+		{"Wrong password", func(mockCredentials *mocks.MockICredentialRepository) {
+			mockCredentials.EXPECT().GetByEmail(mock.Anything, mock.Anything).
+				Return(&model.CredentialModel{UserID: 7, PasswordHash: passwordHash}, nil)
+			mockCredentials.EXPECT().RecordFailedLogin(7, 3, time.Minute, mock.Anything).
+				Return(&model.CredentialModel{UserID: 7, FailedAttempts: 1}, nil)
+		}, "wrong", ErrInvalidCredentials},
+		{"Last allowed attempt locks the account", func(mockCredentials *mocks.MockICredentialRepository) {
+			mockCredentials.EXPECT().GetByEmail(mock.Anything, mock.Anything).
+				Return(&model.CredentialModel{UserID: 7, PasswordHash: passwordHash, FailedAttempts: 2}, nil)
+			mockCredentials.EXPECT().RecordFailedLogin(7, 3, time.Minute, mock.Anything).
+				Return(&model.CredentialModel{UserID: 7, FailedAttempts: 3, LockedUntil: &lockedUntil}, nil)
+		}, "wrong", ErrInvalidCredentials},
+		{"Locked account rejects a wrong password and counts it", func(mockCredentials *mocks.MockICredentialRepository) {
+			mockCredentials.EXPECT().GetByEmail(mock.Anything, mock.Anything).
+				Return(&model.CredentialModel{UserID: 7, PasswordHash: passwordHash, LockedUntil: &lockedUntil}, nil)
+			mockCredentials.EXPECT().RecordFailedLogin(7, 3, time.Minute, mock.Anything).
+				Return(&model.CredentialModel{UserID: 7, FailedAttempts: 1, LockedUntil: &lockedUntil}, nil)
+		}, "wrong", ErrAccountLocked},
+		{"Locked account rejects the right password the same way", func(mockCredentials *mocks.MockICredentialRepository) {
+			mockCredentials.EXPECT().GetByEmail(mock.Anything, mock.Anything).
+				Return(&model.CredentialModel{UserID: 7, PasswordHash: passwordHash, LockedUntil: &lockedUntil}, nil)
+			mockCredentials.EXPECT().RecordFailedLogin(7, 3, time.Minute, mock.Anything).
+				Return(&model.CredentialModel{UserID: 7, FailedAttempts: 1, LockedUntil: &lockedUntil}, nil)
+		}, "correct horse", ErrAccountLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCredentials := mocks.NewMockICredentialRepository(t)
+			tt.setup(mockCredentials)
+			service := NewAccountService(mockCredentials, mocks.NewMockISessionRepository(t), testAccountOptions)
+
+			ctx := tenant.WithTenant(context.Background(), tenant.DefaultTenant)
+			_, err := service.Login(&model.LoginRequest{Email: "user@example.com", Password: tt.password}, &ctx)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APIKeyTokenPrefix marks API keys so that they are easy to recognize, e.g. by secret scanners
+const APIKeyTokenPrefix = "crud_"
+
+const MaxAPIKeyLimit = 100
+
+type IAPIKeyService interface {
+	Create(request *model.CreateAPIKeyRequest, ctx *context.Context) (*model.CreateAPIKeyResponse, error)
+	GetAPIKeys(offset int, limit int, ctx *context.Context) ([]*model.APIKeyResponse, error)
+	Revoke(id int, ctx *context.Context) (*model.APIKeyResponse, error)
+}
+
+type APIKeyService struct {
+	apiKeyRepository repository.IAPIKeyRepository
+	policy           *auth.Policy
+}
+
+func NewAPIKeyService(apiKeyRepository repository.IAPIKeyRepository, policy *auth.Policy) IAPIKeyService {
+	return &APIKeyService{apiKeyRepository: apiKeyRepository, policy: policy}
+}
+
+// Create stores a new key and returns its plaintext, only the hash of the secret is persisted. The scopes of the key
+// have to be permissions the caller holds itself
+func (service *APIKeyService) Create(request *model.CreateAPIKeyRequest, ctx *context.Context) (*model.CreateAPIKeyResponse, error) {
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expires_at must be in the future")
+	}
+	if err := service.authorizeScopes(request.Scopes, *ctx); err != nil {
+		return nil, err
+	}
+	prefix, secret, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	apiKeyModel, err := service.apiKeyRepository.Create(&model.APIKeyModel{
+		Name:       request.Name,
+		Prefix:     prefix,
+		SecretHash: hashAPIKeySecret(secret),
+		Scopes:     request.Scopes,
+		ExpiresAt:  request.ExpiresAt,
+	}, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CreateAPIKeyResponse{
+		APIKeyResponse: *model.APIKeyModelToAPIKeyResponse(apiKeyModel),
+		Key:            APIKeyTokenPrefix + prefix + "_" + secret,
+	}, nil
+}
+
+// authorizeScopes rejects unknown scopes and the scopes the caller does not hold, so that a key cannot grant more than
+// its creator has
+func (service *APIKeyService) authorizeScopes(scopes []string, ctx context.Context) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	for _, scope := range scopes {
+		if !service.policy.IsKnown(scope) {
+			return fmt.Errorf("%w: unknown scope %q", ErrInvalidRequest, scope)
+		}
+		if !ok || !service.policy.HasPermission(principal, scope) {
+			return fmt.Errorf("%w: the caller does not hold the scope %s", auth.ErrForbidden, scope)
+		}
+	}
+	return nil
+}
+
+func (service *APIKeyService) GetAPIKeys(offset int, limit int, ctx *context.Context) ([]*model.APIKeyResponse, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("offset cannot be less than 0")
+	}
+	if limit > MaxAPIKeyLimit {
+		return nil, fmt.Errorf("limit cannot be greater than %d", MaxAPIKeyLimit)
+	} else if limit <= 0 {
+		return nil, fmt.Errorf("limit must be greater than zero")
+	}
+	apiKeys, err := service.apiKeyRepository.GetAll(offset, limit, ctx)
+	if err != nil {
+		return nil, err
+	}
+	apiKeyResponses := make([]*model.APIKeyResponse, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		apiKeyResponses[i] = model.APIKeyModelToAPIKeyResponse(apiKey)
+	}
+	return apiKeyResponses, nil
+}
+
+func (service *APIKeyService) Revoke(id int, ctx *context.Context) (*model.APIKeyResponse, error) {
+	apiKeyModel, err := service.apiKeyRepository.Revoke(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.APIKeyModelToAPIKeyResponse(apiKeyModel), nil
+}
+
+// generateAPIKey returns a random 96-bit lookup prefix and a 256-bit secret, the prefix is long enough for its
+// unique index to never see a collision
+func generateAPIKey() (string, string, error) {
+	prefix := make([]byte, 12)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(prefix); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(prefix), base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// parseAPIKey splits a "crud_<prefix>_<secret>" key
+func parseAPIKey(key string) (string, string, bool) {
+	rest, found := strings.CutPrefix(key, APIKeyTokenPrefix)
+	if !found {
+		return "", "", false
+	}
+	prefix, secret, found := strings.Cut(rest, "_")
+	return prefix, secret, found && prefix != "" && secret != ""
+}
+
+// hashAPIKeySecret hashes the secret with SHA-256, a slow password hash is not needed for 256-bit random secrets
+func hashAPIKeySecret(secret string) []byte {
+	hash := sha256.Sum256([]byte(secret))
+	return hash[:]
+}
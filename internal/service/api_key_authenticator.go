@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/repository"
+	"crud/internal/tenant"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// APIKeyAuthenticator authenticates the "ApiKey" Authorization scheme
+type APIKeyAuthenticator struct {
+	apiKeyRepository repository.IAPIKeyRepository
+	usageRecorder    *APIKeyUsageRecorder
+}
+
+func NewAPIKeyAuthenticator(apiKeyRepository repository.IAPIKeyRepository, usageRecorder *APIKeyUsageRecorder) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{apiKeyRepository: apiKeyRepository, usageRecorder: usageRecorder}
+}
+
+func (authenticator *APIKeyAuthenticator) Authenticate(ctx context.Context, credentials string) (*auth.Principal, error) {
+	prefix, secret, ok := parseAPIKey(credentials)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	apiKey, err := authenticator.apiKeyRepository.GetByPrefix(prefix, &ctx)
+	if err != nil {
+		// Unknown prefix and database errors look the same to the caller
+		return nil, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare(apiKey.SecretHash, hashAPIKeySecret(secret)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	now := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(now)) {
+		return nil, ErrInvalidAPIKey
+	}
+	authenticator.usageRecorder.Record(tenant.WithTenant(ctx, apiKey.TenantID), apiKey.ID, now)
+	principal := &auth.Principal{
+		Subject: "apikey:" + apiKey.Prefix,
+		Method:  "apikey",
+		Scopes:  apiKey.Scopes,
+		Claims:  map[string]any{"api_key_id": apiKey.ID, "api_key_name": apiKey.Name, tenant.ClaimName: apiKey.TenantID},
+	}
+	if apiKey.ExpiresAt != nil {
+		principal.ExpiresAt = *apiKey.ExpiresAt
+	}
+	return principal, nil
+}
+
+// DefaultAPIKeyUsageFlushInterval is how often recorded key usage is written to the database
+const DefaultAPIKeyUsageFlushInterval = 10 * time.Second
+
+// apiKeyUsageKey identifies a key, keys belong to a tenant and are stored per tenant in the schema per tenant mode
+type apiKeyUsageKey struct {
+	tenantID string
+	id       int
+}
+
+type apiKeyUsage struct {
+	apiKeyUsageKey
+	usedAt time.Time
+}
+
+// APIKeyUsageRecorder collects key usage in memory and writes it in batches, so authentication
+// does not add a write to every request. Usage is dropped when the buffer is full.
+type APIKeyUsageRecorder struct {
+	apiKeyRepository repository.IAPIKeyRepository
+	usages           chan apiKeyUsage
+	interval         time.Duration
+	logger           *slog.Logger
+}
+
+func NewAPIKeyUsageRecorder(apiKeyRepository repository.IAPIKeyRepository, interval time.Duration, logger *slog.Logger) *APIKeyUsageRecorder {
+	return &APIKeyUsageRecorder{
+		apiKeyRepository: apiKeyRepository,
+		usages:           make(chan apiKeyUsage, 1024),
+		interval:         interval,
+		logger:           logger,
+	}
+}
+
+// Record records the usage of the key of the tenant of ctx, if any
+func (recorder *APIKeyUsageRecorder) Record(ctx context.Context, id int, usedAt time.Time) {
+	tenantID, _ := tenant.FromContext(ctx)
+	select {
+	case recorder.usages <- apiKeyUsage{apiKeyUsageKey: apiKeyUsageKey{tenantID: tenantID, id: id}, usedAt: usedAt}:
+	default:
+	}
+}
+
+// Run flushes the recorded usage every interval until ctx is done, then flushes what is left
+func (recorder *APIKeyUsageRecorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(recorder.interval)
+	defer ticker.Stop()
+	pending := make(map[apiKeyUsageKey]time.Time)
+	for {
+		select {
+		case usage := <-recorder.usages:
+			if usage.usedAt.After(pending[usage.apiKeyUsageKey]) {
+				pending[usage.apiKeyUsageKey] = usage.usedAt
+			}
+		case <-ticker.C:
+			pending = recorder.flush(pending)
+		case <-ctx.Done():
+			recorder.flush(pending)
+			return
+		}
+	}
+}
+
+// flush writes the usage of every tenant in a statement of its own
+func (recorder *APIKeyUsageRecorder) flush(pending map[apiKeyUsageKey]time.Time) map[apiKeyUsageKey]time.Time {
+	if len(pending) == 0 {
+		return pending
+	}
+	byTenant := make(map[string]map[int]time.Time)
+	for key, usedAt := range pending {
+		if byTenant[key.tenantID] == nil {
+			byTenant[key.tenantID] = make(map[int]time.Time)
+		}
+		byTenant[key.tenantID][key.id] = usedAt
+	}
+	for tenantID, lastUsed := range byTenant {
+		recorder.flushTenant(tenantID, lastUsed)
+	}
+	return make(map[apiKeyUsageKey]time.Time)
+}
+
+func (recorder *APIKeyUsageRecorder) flushTenant(tenantID string, lastUsed map[int]time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if tenantID != "" {
+		ctx = tenant.WithTenant(ctx, tenantID)
+	}
+	if err := recorder.apiKeyRepository.UpdateLastUsed(lastUsed, &ctx); err != nil {
+		recorder.logger.Warn("failed to record api key usage", slog.String("tenant", tenantID), slog.String("error", err.Error()))
+	}
+}
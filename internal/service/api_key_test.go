@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createTestAPIKey(t *testing.T, mockRepository *mocks.MockIAPIKeyRepository) (*model.APIKeyModel, string) {
+	var stored *model.APIKeyModel
+	mockRepository.EXPECT().
+		Create(mock.Anything, mock.Anything).
+		RunAndReturn(func(apiKey *model.APIKeyModel, _ *context.Context) (*model.APIKeyModel, error) {
+			stored = apiKey
+			stored.ID = 1
+			return stored, nil
+		})
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "1", Method: "jwt", Roles: []string{"admin"}})
+	created, err := NewAPIKeyService(mockRepository, auth.NewPolicy(auth.DefaultRolePermissions, slog.New(slog.DiscardHandler))).Create(&model.CreateAPIKeyRequest{
+		Name:   "billing",
+		Scopes: []string{"users:read"},
+	}, &ctx)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Key, APIKeyTokenPrefix+created.Prefix+"_"))
+	assert.Len(t, created.Prefix, 24, "96 bits of prefix")
+	assert.NotContains(t, string(stored.SecretHash), created.Key)
+	return stored, created.Key
+}
+
+func TestUnitAPIKeyServiceCreateScopes(t *testing.T) {
+	t.Parallel()
+
+	policy := auth.NewPolicy(auth.DefaultRolePermissions, slog.New(slog.DiscardHandler))
+	keyAdmin := &auth.Principal{Subject: "apikey:admin", Method: "apikey",
+		Scopes: []string{auth.PermissionAPIKeysAdmin, auth.PermissionUsersRead}}
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		scopes    []string
+		expected  error
+	}{
+		{"Held scopes", keyAdmin, []string{auth.PermissionUsersRead}, nil},
+		{"Scope the caller lacks", keyAdmin, []string{auth.PermissionOpsAdmin}, auth.ErrForbidden},
+		{"Some scope the caller lacks", keyAdmin, []string{auth.PermissionUsersRead, auth.PermissionUsersWrite}, auth.ErrForbidden},
+		{"Unknown scope", &auth.Principal{Subject: "1", Method: "jwt", Roles: []string{"admin"}}, []string{"root"}, ErrInvalidRequest},
+		{"Anonymous caller", nil, []string{auth.PermissionUsersRead}, auth.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := mocks.NewMockIAPIKeyRepository(t)
+			if tt.expected == nil {
+				mockRepository.EXPECT().Create(mock.Anything, mock.Anything).
+					RunAndReturn(func(apiKey *model.APIKeyModel, _ *context.Context) (*model.APIKeyModel, error) {
+						return apiKey, nil
+					})
+			}
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = auth.WithPrincipal(ctx, tt.principal)
+			}
+			_, err := NewAPIKeyService(mockRepository, policy).Create(&model.CreateAPIKeyRequest{Name: "billing", Scopes: tt.scopes}, &ctx)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestUnitAPIKeyAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	mockRepository := mocks.NewMockIAPIKeyRepository(t)
+	stored, key := createTestAPIKey(t, mockRepository)
+	stored.TenantID = "acme"
+	mockRepository.EXPECT().GetByPrefix(stored.Prefix, mock.Anything).Return(stored, nil)
+
+	recorder := NewAPIKeyUsageRecorder(mockRepository, time.Hour, slog.New(slog.DiscardHandler))
+	authenticator := NewAPIKeyAuthenticator(mockRepository, recorder)
+
+	principal, err := authenticator.Authenticate(context.Background(), key)
+	require.NoError(t, err)
+	assert.Equal(t, "apikey:"+stored.Prefix, principal.Subject)
+	assert.Equal(t, []string{"users:read"}, principal.Scopes)
+	assert.Equal(t, "acme", principal.Claims[tenant.ClaimName], "the key names the tenant of the caller")
+	require.Len(t, recorder.usages, 1, "usage is recorded without touching the database")
+	assert.Equal(t, "acme", (<-recorder.usages).tenantID)
+
+	_, err = authenticator.Authenticate(context.Background(), key+"x")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	expired := time.Now().Add(-time.Minute)
+	stored.ExpiresAt = &expired
+	_, err = authenticator.Authenticate(context.Background(), key)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	stored.ExpiresAt = nil
+	revoked := time.Now()
+	stored.RevokedAt = &revoked
+	_, err = authenticator.Authenticate(context.Background(), key)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	_, err = authenticator.Authenticate(context.Background(), "not-a-key")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestUnitAPIKeyUsageRecorderFlush(t *testing.T) {
+	t.Parallel()
+
+	first := time.Now()
+	last := first.Add(time.Second)
+	flushed := make(chan map[int]time.Time, 2)
+	flushedTenants := make(chan string, 2)
+	mockRepository := mocks.NewMockIAPIKeyRepository(t)
+	mockRepository.EXPECT().
+		UpdateLastUsed(mock.Anything, mock.Anything).
+		RunAndReturn(func(lastUsed map[int]time.Time, ctx *context.Context) error {
+			tenantID, _ := tenant.FromContext(*ctx)
+			flushed <- lastUsed
+			flushedTenants <- tenantID
+			return nil
+		})
+
+	recorder := NewAPIKeyUsageRecorder(mockRepository, time.Hour, slog.New(slog.DiscardHandler))
+	acmeCtx := tenant.WithTenant(context.Background(), "acme")
+	recorder.Record(context.Background(), 1, last)
+	recorder.Record(context.Background(), 1, first)
+	recorder.Record(context.Background(), 2, first)
+	recorder.Record(acmeCtx, 1, first)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		recorder.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return len(recorder.usages) == 0 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	usage := map[string]map[int]time.Time{}
+	for range 2 {
+		lastUsed := <-flushed
+		usage[<-flushedTenants] = lastUsed
+	}
+	assert.Equal(t, map[string]map[int]time.Time{"": {1: last, 2: first}, "acme": {1: first}}, usage,
+		"the usage of each tenant is written separately")
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/repository"
+	"errors"
+	"fmt"
+)
+
+// DefaultMaxLimit caps the page size of resources without their own limit
+const DefaultMaxLimit = 20
+
+// ErrInvalidRequest is returned for requests rejected by validation, the controllers answer 400 to it
+var ErrInvalidRequest = errors.New("invalid request")
+
+// IService is the service layer of a resource taking requests of type Req and answering with responses of type Resp
+type IService[ID any, Req any, Resp any] interface {
+	Create(request *Req, ctx *context.Context) (*Resp, error)
+	GetById(id ID, ctx *context.Context) (*Resp, error)
+	Update(id ID, request *Req, ctx *context.Context) (*Resp, error)
+	Delete(id ID, ctx *context.Context) (*Resp, error)
+	GetAll(offset int, limit int, ctx *context.Context) ([]*Resp, error)
+}
+
+// Hooks declare the resource specific parts of a Service. ToModel and ToResponse are required, the other hooks
+// are skipped when nil. An error of a before hook stops the operation, an error of an after hook is returned
+// although the change is already stored
+type Hooks[T any, ID any, Req any, Resp any] struct {
+	// ToModel maps a request to the entity, id is the zero value on create
+	ToModel    func(id ID, request *Req) *T
+	ToResponse func(entity *T) *Resp
+	// Validate checks create and update requests, its error is wrapped in ErrInvalidRequest
+	Validate     func(request *Req, ctx context.Context) error
+	BeforeCreate func(entity *T, ctx context.Context) error
+	AfterCreate  func(entity *T, ctx context.Context) error
+	BeforeUpdate func(entity *T, ctx context.Context) error
+	AfterUpdate  func(entity *T, ctx context.Context) error
+	BeforeDelete func(id ID, ctx context.Context) error
+	AfterDelete  func(entity *T, ctx context.Context) error
+	// MaxLimit caps the page size of GetAll, DefaultMaxLimit when zero
+	MaxLimit int
+}
+
+// Service implements IService on top of a repository of entities of type T
+type Service[T any, ID any, Req any, Resp any] struct {
+	repository repository.IRepository[T, ID]
+	hooks      Hooks[T, ID, Req, Resp]
+}
+
+func NewService[T any, ID any, Req any, Resp any](repository repository.IRepository[T, ID], hooks Hooks[T, ID, Req, Resp]) IService[ID, Req, Resp] {
+	if hooks.MaxLimit <= 0 {
+		hooks.MaxLimit = DefaultMaxLimit
+	}
+	return &Service[T, ID, Req, Resp]{repository: repository, hooks: hooks}
+}
+
+func (service *Service[T, ID, Req, Resp]) Create(request *Req, ctx *context.Context) (*Resp, error) {
+	if err := service.validate(request, *ctx); err != nil {
+		return nil, err
+	}
+	var zeroID ID
+	entity := service.hooks.ToModel(zeroID, request)
+	if err := runHook(service.hooks.BeforeCreate, entity, *ctx); err != nil {
+		return nil, err
+	}
+	created, err := service.repository.Create(entity, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = runHook(service.hooks.AfterCreate, created, *ctx); err != nil {
+		return nil, err
+	}
+	return service.hooks.ToResponse(created), nil
+}
+
+func (service *Service[T, ID, Req, Resp]) GetById(id ID, ctx *context.Context) (*Resp, error) {
+	entity, err := service.repository.GetById(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.hooks.ToResponse(entity), nil
+}
+
+func (service *Service[T, ID, Req, Resp]) Update(id ID, request *Req, ctx *context.Context) (*Resp, error) {
+	if err := service.validate(request, *ctx); err != nil {
+		return nil, err
+	}
+	entity := service.hooks.ToModel(id, request)
+	if err := runHook(service.hooks.BeforeUpdate, entity, *ctx); err != nil {
+		return nil, err
+	}
+	updated, err := service.repository.Update(entity, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = runHook(service.hooks.AfterUpdate, updated, *ctx); err != nil {
+		return nil, err
+	}
+	return service.hooks.ToResponse(updated), nil
+}
+
+func (service *Service[T, ID, Req, Resp]) Delete(id ID, ctx *context.Context) (*Resp, error) {
+	if err := runHook(service.hooks.BeforeDelete, id, *ctx); err != nil {
+		return nil, err
+	}
+	deleted, err := service.repository.Delete(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = runHook(service.hooks.AfterDelete, deleted, *ctx); err != nil {
+		return nil, err
+	}
+	return service.hooks.ToResponse(deleted), nil
+}
+
+func (service *Service[T, ID, Req, Resp]) GetAll(offset int, limit int, ctx *context.Context) ([]*Resp, error) {
+	if err := validatePage(offset, limit, service.hooks.MaxLimit); err != nil {
+		return nil, err
+	}
+	entities, err := service.repository.GetAll(offset, limit, ctx)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*Resp, len(entities))
+	for i, entity := range entities {
+		responses[i] = service.hooks.ToResponse(entity)
+	}
+	return responses, nil
+}
+
+func (service *Service[T, ID, Req, Resp]) validate(request *Req, ctx context.Context) error {
+	if service.hooks.Validate == nil {
+		return nil
+	}
+	if err := service.hooks.Validate(request, ctx); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+// validatePage checks the offset and limit of a list request
+func validatePage(offset int, limit int, maxLimit int) error {
+	if offset < 0 {
+		return fmt.Errorf("%w: offset cannot be less than 0", ErrInvalidRequest)
+	}
+	if limit > maxLimit {
+		return fmt.Errorf("%w: limit cannot be greater than %d", ErrInvalidRequest, maxLimit)
+	} else if limit <= 0 {
+		return fmt.Errorf("%w: limit must be greater than zero", ErrInvalidRequest)
+	}
+	return nil
+}
+
+func runHook[V any](hook func(value V, ctx context.Context) error, value V, ctx context.Context) error {
+	if hook == nil {
+		return nil
+	}
+	return hook(value, ctx)
+}
+
+// ResourcePermissions are the permissions an AuthorizedService checks for a resource
+type ResourcePermissions struct {
+	Resource string
+	Read     string
+	Write    string
+}
+
+// AuthorizedService checks the caller's permissions before delegating to the wrapped service
+type AuthorizedService[ID any, Req any, Resp any] struct {
+	service     IService[ID, Req, Resp]
+	policy      *auth.Policy
+	permissions ResourcePermissions
+}
+
+func NewAuthorizedService[ID any, Req any, Resp any](service IService[ID, Req, Resp], policy *auth.Policy, permissions ResourcePermissions) IService[ID, Req, Resp] {
+	return &AuthorizedService[ID, Req, Resp]{service: service, policy: policy, permissions: permissions}
+}
+
+func (service *AuthorizedService[ID, Req, Resp]) Create(request *Req, ctx *context.Context) (*Resp, error) {
+	if err := service.policy.Authorize(*ctx, service.permissions.Write, service.permissions.Resource, nil); err != nil {
+		return nil, err
+	}
+	return service.service.Create(request, ctx)
+}
+
+func (service *AuthorizedService[ID, Req, Resp]) GetById(id ID, ctx *context.Context) (*Resp, error) {
+	if err := service.policy.Authorize(*ctx, service.permissions.Read, service.permissions.Resource, id); err != nil {
+		return nil, err
+	}
+	return service.service.GetById(id, ctx)
+}
+
+func (service *AuthorizedService[ID, Req, Resp]) Update(id ID, request *Req, ctx *context.Context) (*Resp, error) {
+	if err := service.policy.Authorize(*ctx, service.permissions.Write, service.permissions.Resource, id); err != nil {
+		return nil, err
+	}
+	return service.service.Update(id, request, ctx)
+}
+
+func (service *AuthorizedService[ID, Req, Resp]) Delete(id ID, ctx *context.Context) (*Resp, error) {
+	if err := service.policy.Authorize(*ctx, service.permissions.Write, service.permissions.Resource, id); err != nil {
+		return nil, err
+	}
+	return service.service.Delete(id, ctx)
+}
+
+func (service *AuthorizedService[ID, Req, Resp]) GetAll(offset int, limit int, ctx *context.Context) ([]*Resp, error) {
+	if err := service.policy.Authorize(*ctx, service.permissions.Read, service.permissions.Resource, nil); err != nil {
+		return nil, err
+	}
+	return service.service.GetAll(offset, limit, ctx)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/mocks"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+type testWidget struct {
+	ID      int
+	Name    string
+	Audited bool
+}
+
+type testWidgetRequest struct {
+	Name string
+}
+
+type testWidgetResponse struct {
+	ID   int
+	Name string
+}
+
+func newTestWidgetService(repository *mocks.MockIRepository[testWidget, int], calls *[]string) IService[int, testWidgetRequest, testWidgetResponse] {
+	return NewService(repository, Hooks[testWidget, int, testWidgetRequest, testWidgetResponse]{
+		ToModel: func(id int, request *testWidgetRequest) *testWidget {
+			return &testWidget{ID: id, Name: request.Name}
+		},
+		ToResponse: func(widget *testWidget) *testWidgetResponse {
+			return &testWidgetResponse{ID: widget.ID, Name: widget.Name}
+		},
+		Validate: func(request *testWidgetRequest, _ context.Context) error {
+			if request.Name == "" {
+				return errors.New("name is required")
+			}
+			return nil
+		},
+		BeforeCreate: func(widget *testWidget, _ context.Context) error {
+			*calls = append(*calls, "before create")
+			widget.Audited = true
+			return nil
+		},
+		AfterCreate: func(widget *testWidget, _ context.Context) error {
+			*calls = append(*calls, "after create")
+			return nil
+		},
+		BeforeDelete: func(id int, _ context.Context) error {
+			return errors.New("widgets cannot be deleted")
+		},
+		MaxLimit: 5,
+	})
+}
+
+func TestUnitService(t *testing.T) {
+	t.Parallel()
+
+	mockRepository := mocks.NewMockIRepository[testWidget, int](t)
+	calls := make([]string, 0)
+	service := newTestWidgetService(mockRepository, &calls)
+	ctx := context.Background()
+
+	mockRepository.EXPECT().
+		Create(mock.Anything, mock.Anything).
+		RunAndReturn(func(widget *testWidget, _ *context.Context) (*testWidget, error) {
+			assert.True(t, widget.Audited, "before create runs before the entity is stored")
+			calls = append(calls, "create")
+			return &testWidget{ID: 7, Name: widget.Name}, nil
+		})
+	created, err := service.Create(&testWidgetRequest{Name: "gear"}, &ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &testWidgetResponse{ID: 7, Name: "gear"}, created)
+	assert.Equal(t, []string{"before create", "create", "after create"}, calls)
+
+	_, err = service.Create(&testWidgetRequest{}, &ctx)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	assert.EqualError(t, err, "invalid request: name is required")
+
+	mockRepository.EXPECT().Update(&testWidget{ID: 7, Name: "cog"}, mock.Anything).Return(&testWidget{ID: 7, Name: "cog"}, nil)
+	updated, err := service.Update(7, &testWidgetRequest{Name: "cog"}, &ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "cog", updated.Name)
+
+	_, err = service.Delete(7, &ctx)
+	assert.EqualError(t, err, "widgets cannot be deleted", "the repository is not called when a before hook fails")
+
+	_, err = service.GetAll(0, 6, &ctx)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	mockRepository.EXPECT().GetAll(0, 5, mock.Anything).Return([]*testWidget{{ID: 7, Name: "cog"}}, nil)
+	widgets, err := service.GetAll(0, 5, &ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*testWidgetResponse{{ID: 7, Name: "cog"}}, widgets)
+}
+
+func TestUnitAuthorizedService(t *testing.T) {
+	t.Parallel()
+
+	mockService := mocks.NewMockIService[int, testWidgetRequest, testWidgetResponse](t)
+	policy := auth.NewPolicy(map[string][]string{"viewer": {"widgets:read"}}, slog.New(slog.DiscardHandler))
+	service := NewAuthorizedService(mockService, policy, ResourcePermissions{
+		Resource: "widget",
+		Read:     "widgets:read",
+		Write:    "widgets:write",
+	})
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "1", Roles: []string{"viewer"}})
+
+	mockService.EXPECT().GetById(7, mock.Anything).Return(&testWidgetResponse{ID: 7}, nil)
+	widget, err := service.GetById(7, &ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 7, widget.ID)
+
+	_, err = service.Delete(7, &ctx)
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultJobTimeout bounds a run of the jobs whose handler sets no timeout
+	DefaultJobTimeout = time.Minute
+	// DefaultJobMaxAttempts is the number of runs after which a failing job is failed
+	DefaultJobMaxAttempts = 5
+
+	maxJobUniqueKeyLength = 255
+)
+
+// ErrJobPermanent fails a job without retrying it, handlers wrap it into errors which another attempt would not fix
+var ErrJobPermanent = errors.New("permanent job failure")
+
+// JobHandler runs the jobs of a type, ctx carries the tenant of the job and ends after its timeout. Handle must
+// return when ctx is done, the job runs again when the worker stops before it returned, so it must be idempotent
+type JobHandler struct {
+	Type string
+	// Timeout and MaxAttempts fall back to DefaultJobTimeout and DefaultJobMaxAttempts
+	Timeout     time.Duration
+	MaxAttempts int
+	// Every makes the job periodic, see JobType.Every
+	Every  time.Duration
+	Handle func(ctx context.Context, job *model.JobModel) error
+}
+
+// JobType declares a job whose payload is a P, it creates the handler of the jobs and the requests enqueuing them
+type JobType[P any] struct {
+	Name        string
+	Timeout     time.Duration
+	MaxAttempts int
+	// Every schedules a job of the type with the zero payload every interval. The workers keep one pending per
+	// tenant, and per database in the shared schema, where it runs for the default tenant
+	Every time.Duration
+}
+
+// Handler decodes the payload of the jobs for handle, a payload which cannot be decoded fails the job at once
+func (jobType JobType[P]) Handler(handle func(ctx context.Context, payload P) error) JobHandler {
+	return JobHandler{
+		Type:        jobType.Name,
+		Timeout:     jobType.Timeout,
+		MaxAttempts: jobType.MaxAttempts,
+		Every:       jobType.Every,
+		Handle: func(ctx context.Context, job *model.JobModel) error {
+			var payload P
+			if err := json.Unmarshal(job.Payload, &payload); err != nil {
+				return fmt.Errorf("%w: invalid payload: %w", ErrJobPermanent, err)
+			}
+			return handle(ctx, payload)
+		},
+	}
+}
+
+// Request creates the request enqueuing a job with the payload, the caller may delay it or set its unique key
+func (jobType JobType[P]) Request(payload P) (*model.EnqueueJobRequest, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &model.EnqueueJobRequest{Type: jobType.Name, Payload: encoded}, nil
+}
+
+// JobRegistry holds the job handlers registered at startup, the workers only claim jobs of registered types
+type JobRegistry struct {
+	handlers map[string]JobHandler
+	types    []string
+}
+
+func NewJobRegistry(handlers ...JobHandler) (*JobRegistry, error) {
+	registry := &JobRegistry{handlers: make(map[string]JobHandler, len(handlers))}
+	for _, handler := range handlers {
+		switch {
+		case handler.Type == "":
+			return nil, errors.New("job type is required")
+		case handler.Handle == nil:
+			return nil, fmt.Errorf("job type %q has no handler", handler.Type)
+		}
+		if _, ok := registry.handlers[handler.Type]; ok {
+			return nil, fmt.Errorf("job type %q is registered twice", handler.Type)
+		}
+		if handler.Timeout <= 0 {
+			handler.Timeout = DefaultJobTimeout
+		}
+		if handler.MaxAttempts <= 0 {
+			handler.MaxAttempts = DefaultJobMaxAttempts
+		}
+		registry.handlers[handler.Type] = handler
+		registry.types = append(registry.types, handler.Type)
+	}
+	slices.Sort(registry.types)
+	return registry, nil
+}
+
+// Types returns the registered job types, sorted
+func (registry *JobRegistry) Types() []string {
+	return registry.types
+}
+
+// Periodic returns the handlers of the periodic job types, sorted by type
+func (registry *JobRegistry) Periodic() []JobHandler {
+	periodic := make([]JobHandler, 0)
+	for _, jobType := range registry.types {
+		if handler := registry.handlers[jobType]; handler.Every > 0 {
+			periodic = append(periodic, handler)
+		}
+	}
+	return periodic
+}
+
+func (registry *JobRegistry) Get(jobType string) (JobHandler, bool) {
+	handler, ok := registry.handlers[jobType]
+	return handler, ok
+}
+
+type IJobService interface {
+	// Enqueue adds a job of a registered type for the tenant of ctx, in the transaction of ctx when there is one.
+	// The pending or running job holding the unique key of the request is returned instead of a new one
+	Enqueue(request *model.EnqueueJobRequest, ctx *context.Context) (*model.JobResponse, error)
+	// GetJobs returns the jobs of the tenant of ctx with the status, of any status when it is empty, newest first
+	GetJobs(status string, offset int, limit int, ctx *context.Context) ([]*model.JobResponse, error)
+	// Retry runs a failed job again with a fresh count of attempts
+	Retry(id int64, ctx *context.Context) (*model.JobResponse, error)
+}
+
+type JobService struct {
+	jobRepository repository.IJobRepository
+	registry      *JobRegistry
+}
+
+func NewJobService(jobRepository repository.IJobRepository, registry *JobRegistry) IJobService {
+	return &JobService{jobRepository: jobRepository, registry: registry}
+}
+
+func (service *JobService) Enqueue(request *model.EnqueueJobRequest, ctx *context.Context) (*model.JobResponse, error) {
+	handler, ok := service.registry.Get(request.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown job type %q", ErrInvalidRequest, request.Type)
+	}
+	payload := request.Payload
+	if len(payload) == 0 {
+		payload = json.RawMessage("{}")
+	} else if !json.Valid(payload) {
+		return nil, fmt.Errorf("%w: payload is not valid JSON", ErrInvalidRequest)
+	}
+	if len(request.UniqueKey) > maxJobUniqueKeyLength {
+		return nil, fmt.Errorf("%w: unique key cannot be longer than %d characters", ErrInvalidRequest, maxJobUniqueKeyLength)
+	}
+	job := &model.JobModel{
+		Type:        request.Type,
+		Payload:     payload,
+		MaxAttempts: handler.MaxAttempts,
+		Timeout:     handler.Timeout,
+		RunAt:       request.RunAt,
+	}
+	if request.UniqueKey != "" {
+		job.UniqueKey = &request.UniqueKey
+	}
+	job, err := service.jobRepository.Enqueue(job, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.JobModelToJobResponse(job), nil
+}
+
+func (service *JobService) GetJobs(status string, offset int, limit int, ctx *context.Context) ([]*model.JobResponse, error) {
+	if status != "" && !slices.Contains(model.JobStatuses, status) {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidRequest, status)
+	}
+	if err := validatePage(offset, limit, DefaultMaxLimit); err != nil {
+		return nil, err
+	}
+	jobs, err := service.jobRepository.GetAll(status, offset, limit, ctx)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*model.JobResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = model.JobModelToJobResponse(job)
+	}
+	return responses, nil
+}
+
+func (service *JobService) Retry(id int64, ctx *context.Context) (*model.JobResponse, error) {
+	job, err := service.jobRepository.Retry(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.JobModelToJobResponse(job), nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/tenant"
+	"encoding/json"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+type reportPayload struct {
+	UserID int    `json:"user_id"`
+	Format string `json:"format"`
+}
+
+var reportJob = JobType[reportPayload]{Name: "reports.send", Timeout: 5 * time.Second}
+
+func TestUnitJobRegistry(t *testing.T) {
+	t.Parallel()
+
+	noop := func(context.Context, *model.JobModel) error { return nil }
+	registry, err := NewJobRegistry(
+		JobHandler{Type: "users.export", MaxAttempts: 2, Handle: noop},
+		reportJob.Handler(func(context.Context, reportPayload) error { return nil }),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reports.send", "users.export"}, registry.Types())
+	handler, ok := registry.Get("users.export")
+	require.True(t, ok)
+	assert.Equal(t, DefaultJobTimeout, handler.Timeout)
+	assert.Equal(t, 2, handler.MaxAttempts)
+	handler, ok = registry.Get("reports.send")
+	require.True(t, ok)
+	assert.Equal(t, 5*time.Second, handler.Timeout)
+	assert.Equal(t, DefaultJobMaxAttempts, handler.MaxAttempts)
+	_, ok = registry.Get("users.import")
+	assert.False(t, ok)
+
+	_, err = NewJobRegistry(JobHandler{Type: "users.export", Handle: noop}, JobHandler{Type: "users.export", Handle: noop})
+	assert.EqualError(t, err, `job type "users.export" is registered twice`)
+	_, err = NewJobRegistry(JobHandler{Type: "users.export"})
+	assert.EqualError(t, err, `job type "users.export" has no handler`)
+	_, err = NewJobRegistry(JobHandler{Handle: noop})
+	assert.EqualError(t, err, "job type is required")
+}
+
+func TestUnitJobTypeHandler(t *testing.T) {
+	t.Parallel()
+
+	var handled reportPayload
+	handler := reportJob.Handler(func(_ context.Context, payload reportPayload) error {
+		handled = payload
+		return nil
+	})
+	request, err := reportJob.Request(reportPayload{UserID: 5, Format: "csv"})
+	require.NoError(t, err)
+	assert.Equal(t, "reports.send", request.Type)
+	assert.JSONEq(t, `{"user_id":5,"format":"csv"}`, string(request.Payload))
+
+	require.NoError(t, handler.Handle(context.Background(), &model.JobModel{Payload: request.Payload}))
+	assert.Equal(t, reportPayload{UserID: 5, Format: "csv"}, handled)
+	err = handler.Handle(context.Background(), &model.JobModel{Payload: json.RawMessage(`[]`)})
+	assert.ErrorIs(t, err, ErrJobPermanent, "another attempt would not decode the payload either")
+}
+
+func TestUnitJobServiceEnqueue(t *testing.T) {
+	t.Parallel()
+
+	registry, err := NewJobRegistry(reportJob.Handler(func(context.Context, reportPayload) error { return nil }))
+	require.NoError(t, err)
+	runAt, uniqueKey := time.Now().Add(time.Hour), "user-5"
+	tests := []struct {
+		name    string
+		request *model.EnqueueJobRequest
+		job     *model.JobModel
+		message string
+	}{
+		{name: "job", request: &model.EnqueueJobRequest{Type: "reports.send", Payload: json.RawMessage(`{"user_id":5}`)},
+			job: &model.JobModel{Type: "reports.send", Payload: json.RawMessage(`{"user_id":5}`), MaxAttempts: DefaultJobMaxAttempts,
+				Timeout: 5 * time.Second}},
+		{name: "delayed unique job", request: &model.EnqueueJobRequest{Type: "reports.send", RunAt: runAt, UniqueKey: "user-5"},
+			job: &model.JobModel{Type: "reports.send", Payload: json.RawMessage(`{}`), MaxAttempts: DefaultJobMaxAttempts,
+				Timeout: 5 * time.Second, RunAt: runAt, UniqueKey: &uniqueKey}},
+		{name: "unknown type", request: &model.EnqueueJobRequest{Type: "reports.print"},
+			message: `invalid request: unknown job type "reports.print"`},
+		{name: "invalid payload", request: &model.EnqueueJobRequest{Type: "reports.send", Payload: json.RawMessage(`{`)},
+			message: "invalid request: payload is not valid JSON"},
+		{name: "long unique key", request: &model.EnqueueJobRequest{Type: "reports.send", UniqueKey: strings.Repeat("k", 256)},
+			message: "invalid request: unique key cannot be longer than 255 characters"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			mockRepository := mocks.NewMockIJobRepository(t)
+			if test.job != nil {
+				mockRepository.EXPECT().Enqueue(test.job, mock.Anything).
+					RunAndReturn(func(job *model.JobModel, _ *context.Context) (*model.JobModel, error) {
+						enqueued := *job
+						enqueued.ID, enqueued.TenantID, enqueued.Status = 1, "acme", model.JobPending
+						return &enqueued, nil
+					})
+			}
+			service := NewJobService(mockRepository, registry)
+
+			ctx := tenant.WithTenant(context.Background(), "acme")
+			job, err := service.Enqueue(test.request, &ctx)
+			if test.message != "" {
+				assert.EqualError(t, err, test.message)
+				assert.ErrorIs(t, err, ErrInvalidRequest)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), job.ID)
+			assert.Equal(t, model.JobPending, job.Status)
+			assert.Equal(t, int64(5000), job.TimeoutMs)
+		})
+	}
+}
+
+func TestUnitJobServiceAdministration(t *testing.T) {
+	t.Parallel()
+
+	registry, err := NewJobRegistry()
+	require.NoError(t, err)
+	mockRepository := mocks.NewMockIJobRepository(t)
+	mockRepository.EXPECT().GetAll(model.JobFailed, 0, 10, mock.Anything).
+		Return([]*model.JobModel{{ID: 3, Type: "reports.send", Status: model.JobFailed}}, nil)
+	mockRepository.EXPECT().Retry(int64(3), mock.Anything).
+		Return(&model.JobModel{ID: 3, Type: "reports.send", Status: model.JobPending}, nil)
+	mockRepository.EXPECT().Retry(int64(4), mock.Anything).Return(nil, pgx.ErrNoRows)
+	service := NewJobService(mockRepository, registry)
+	ctx := tenant.WithTenant(context.Background(), "acme")
+
+	jobs, err := service.GetJobs(model.JobFailed, 0, 10, &ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, int64(3), jobs[0].ID)
+	_, err = service.GetJobs("dead", 0, 10, &ctx)
+	assert.EqualError(t, err, `invalid request: unknown status "dead"`)
+	_, err = service.GetJobs("", 0, DefaultMaxLimit+1, &ctx)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+
+	job, err := service.Retry(3, &ctx)
+	require.NoError(t, err)
+	assert.Equal(t, model.JobPending, job.Status)
+	_, err = service.Retry(4, &ctx)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"crud/internal/tenant"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultJobConcurrency  = 10
+	DefaultJobPollInterval = time.Second
+	// DefaultJobShutdownTimeout is how long running jobs get to finish when the worker stops
+	DefaultJobShutdownTimeout = 30 * time.Second
+	// DefaultJobRetention is how long succeeded jobs are kept
+	DefaultJobRetention = 7 * 24 * time.Hour
+
+	jobMinBackoff    = 5 * time.Second
+	jobMaxBackoff    = time.Hour
+	jobSweepInterval = time.Hour
+)
+
+// JobWorkerOptions configures the worker, zero values fall back to the defaults
+type JobWorkerOptions struct {
+	// Concurrency is the number of jobs running at once
+	Concurrency  int
+	PollInterval time.Duration
+	// ShutdownTimeout is how long running jobs get to finish when the worker stops, then they are canceled and
+	// run again later
+	ShutdownTimeout time.Duration
+	Retention       time.Duration
+	// Tenants lists the tenants whose jobs are run in the schema per tenant mode. When it is nil the jobs are
+	// shared by all tenants
+	Tenants func(ctx context.Context) ([]string, error)
+}
+
+// JobWorker runs the due jobs of the registered types. A failing job is retried with an exponential backoff until
+// it ran MaxAttempts times, then it is failed until it is retried. Replicas claim distinct jobs, their order is not
+// guaranteed
+type JobWorker struct {
+	jobRepository repository.IJobRepository
+	registry      *JobRegistry
+	options       JobWorkerOptions
+	logger        *slog.Logger
+	// slots holds a token per running job
+	slots     chan struct{}
+	running   sync.WaitGroup
+	lastSweep time.Time
+	// scheduled is set once the periodic jobs were scheduled at startup
+	scheduled bool
+}
+
+func NewJobWorker(jobRepository repository.IJobRepository, registry *JobRegistry, options JobWorkerOptions,
+	logger *slog.Logger) *JobWorker {
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultJobConcurrency
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultJobPollInterval
+	}
+	if options.ShutdownTimeout <= 0 {
+		options.ShutdownTimeout = DefaultJobShutdownTimeout
+	}
+	if options.Retention <= 0 {
+		options.Retention = DefaultJobRetention
+	}
+	return &JobWorker{
+		jobRepository: jobRepository,
+		registry:      registry,
+		options:       options,
+		logger:        logger,
+		slots:         make(chan struct{}, options.Concurrency),
+		lastSweep:     time.Now(),
+	}
+}
+
+// Run starts the due jobs every poll interval until ctx is done. Then it stops claiming jobs and waits up to the
+// shutdown timeout for the running ones
+func (worker *JobWorker) Run(ctx context.Context) {
+	// The jobs outlive ctx by the shutdown timeout
+	jobsCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+	ticker := time.NewTicker(worker.options.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			worker.runDue(ctx, jobsCtx)
+		case <-ctx.Done():
+			worker.shutdown(cancelJobs)
+			return
+		}
+	}
+}
+
+// runDue starts the due jobs of every tenant in jobsCtx while there are free slots. At startup and once in a while
+// it schedules the periodic jobs, which reschedule themselves when they ran, and deletes the expired succeeded jobs
+func (worker *JobWorker) runDue(ctx context.Context, jobsCtx context.Context) {
+	contexts, err := tenantContexts(ctx, worker.options.Tenants)
+	if err != nil {
+		worker.logger.Warn("failed to list the tenants of the jobs", slog.String("error", err.Error()))
+		return
+	}
+	sweep := time.Since(worker.lastSweep) >= jobSweepInterval
+	for _, tenantCtx := range contexts {
+		if sweep || !worker.scheduled {
+			worker.schedule(tenantCtx, worker.registry.Periodic()...)
+		}
+		if err = worker.claim(tenantCtx, jobsCtx); err != nil && !errors.Is(err, context.Canceled) {
+			worker.logger.Warn("failed to claim jobs", slog.String("error", err.Error()))
+		}
+		if sweep {
+			worker.sweep(tenantCtx)
+		}
+	}
+	worker.scheduled = true
+	if sweep {
+		worker.lastSweep = time.Now()
+	}
+}
+
+// schedule enqueues the next run of the periodic jobs for the tenant of ctx, or the default tenant when ctx has none.
+// The type is the unique key, so the run already pending is kept, and a job enqueued by another replica too
+func (worker *JobWorker) schedule(ctx context.Context, handlers ...JobHandler) {
+	if _, ok := tenant.FromContext(ctx); !ok {
+		ctx = tenant.WithTenant(ctx, tenant.DefaultTenant)
+	}
+	for _, handler := range handlers {
+		uniqueKey := handler.Type
+		_, err := worker.jobRepository.Enqueue(&model.JobModel{
+			Type:        handler.Type,
+			Payload:     json.RawMessage("{}"),
+			UniqueKey:   &uniqueKey,
+			MaxAttempts: handler.MaxAttempts,
+			Timeout:     handler.Timeout,
+			RunAt:       time.Now().Add(handler.Every),
+		}, &ctx)
+		if err != nil {
+			worker.logger.Warn("failed to schedule periodic job", slog.String("job_type", handler.Type),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// claim starts batches of due jobs until no slot is free or fewer jobs than free slots are due
+func (worker *JobWorker) claim(ctx context.Context, jobsCtx context.Context) error {
+	types := worker.registry.Types()
+	if len(types) == 0 {
+		return nil
+	}
+	failed, err := worker.jobRepository.FailExpired(types, &ctx)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		worker.logger.Warn("failed jobs whose lease expired on their last attempt", slog.Int64("jobs", failed))
+	}
+	for ctx.Err() == nil {
+		// Only claim adds tokens, the free slots cannot be taken meanwhile
+		free := cap(worker.slots) - len(worker.slots)
+		if free == 0 {
+			return nil
+		}
+		jobs, err := worker.jobRepository.ClaimDue(types, free, &ctx)
+		if err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			worker.slots <- struct{}{}
+			worker.running.Add(1)
+			go func() {
+				defer func() {
+					<-worker.slots
+					worker.running.Done()
+				}()
+				worker.run(jobsCtx, job)
+			}()
+		}
+		if len(jobs) < free {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// run runs a claimed job and records the outcome. When ctx is canceled, because the worker stopped and the shutdown
+// timeout expired, the job is released and runs again later
+func (worker *JobWorker) run(ctx context.Context, job *model.JobModel) {
+	ctx = tenant.WithTenant(ctx, job.TenantID)
+	runErr := worker.handle(ctx, job)
+	// The outcome is recorded after ctx is canceled too
+	recordCtx := context.WithoutCancel(ctx)
+	attrs := []any{
+		slog.Int64("job_id", job.ID),
+		slog.String("job_type", job.Type),
+		slog.String("tenant_id", job.TenantID),
+		slog.Int("attempts", job.Attempts),
+	}
+	var err error
+	switch {
+	case runErr == nil:
+		worker.logger.Debug("ran job", attrs...)
+		if err = worker.jobRepository.MarkSucceeded(job, &recordCtx); err == nil {
+			worker.reschedule(recordCtx, job)
+		}
+	case ctx.Err() != nil:
+		worker.logger.Info("interrupted job, it runs again later", append(attrs, slog.String("error", runErr.Error()))...)
+		err = worker.jobRepository.Release(job, &recordCtx)
+	case job.Attempts >= job.MaxAttempts || errors.Is(runErr, ErrJobPermanent):
+		worker.logger.Warn("job failed", append(attrs, slog.String("error", runErr.Error()))...)
+		err = worker.jobRepository.MarkFailed(job, runErr.Error(), &recordCtx)
+	default:
+		runAt := time.Now().Add(jobBackoff(job.Attempts))
+		worker.logger.Info("failed to run job", append(attrs, slog.String("error", runErr.Error()),
+			slog.Time("run_at", runAt))...)
+		err = worker.jobRepository.Reschedule(job, runErr.Error(), runAt, &recordCtx)
+	}
+	if err != nil {
+		worker.logger.Warn("failed to record the outcome of job", append(attrs, slog.String("error", err.Error()))...)
+	}
+}
+
+// reschedule enqueues the next run of a periodic job which ran, failed runs are rescheduled when the worker schedules
+// the periodic jobs again
+func (worker *JobWorker) reschedule(ctx context.Context, job *model.JobModel) {
+	if handler, ok := worker.registry.Get(job.Type); ok && handler.Every > 0 {
+		worker.schedule(ctx, handler)
+	}
+}
+
+// handle runs the handler of the job within the timeout of the job, a panic fails the attempt
+func (worker *JobWorker) handle(ctx context.Context, job *model.JobModel) (err error) {
+	handler, ok := worker.registry.Get(job.Type)
+	if !ok {
+		return fmt.Errorf("%w: no handler of job type %q", ErrJobPermanent, job.Type)
+	}
+	timeout := job.Timeout
+	if timeout <= 0 {
+		timeout = handler.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return handler.Handle(ctx, job)
+}
+
+// shutdown waits for the running jobs, after the shutdown timeout they are canceled
+func (worker *JobWorker) shutdown(cancelJobs context.CancelFunc) {
+	done := make(chan struct{})
+	go func() {
+		worker.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(worker.options.ShutdownTimeout):
+		worker.logger.Warn("canceling the running jobs, they run again later",
+			slog.Duration("shutdown_timeout", worker.options.ShutdownTimeout))
+		cancelJobs()
+		<-done
+	}
+}
+
+func (worker *JobWorker) sweep(ctx context.Context) {
+	deleted, err := worker.jobRepository.DeleteSucceeded(time.Now().Add(-worker.options.Retention), &ctx)
+	if err != nil {
+		worker.logger.Warn("failed to delete succeeded jobs", slog.String("error", err.Error()))
+		return
+	}
+	if deleted > 0 {
+		worker.logger.Debug("deleted succeeded jobs", slog.Int64("deleted", deleted))
+	}
+}
+
+// jobBackoff doubles the delay with every failed attempt, up to jobMaxBackoff
+func jobBackoff(attempts int) time.Duration {
+	return exponentialBackoff(attempts, jobMinBackoff, jobMaxBackoff)
+}
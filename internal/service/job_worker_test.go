@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/tenant"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func claimedJob(id int64, jobType string, attempts int) *model.JobModel {
+	return &model.JobModel{
+		ID:          id,
+		TenantID:    "acme",
+		Type:        jobType,
+		Payload:     json.RawMessage(`{}`),
+		Status:      model.JobRunning,
+		Attempts:    attempts,
+		MaxAttempts: 3,
+		Timeout:     time.Second,
+	}
+}
+
+// newTestJobWorker runs the jobs of handle, the jobs claimed are handed out once
+func newTestJobWorker(t *testing.T, handle func(ctx context.Context, job *model.JobModel) error, options JobWorkerOptions,
+	jobs ...*model.JobModel) (*JobWorker, *mocks.MockIJobRepository) {
+	t.Helper()
+	registry, err := NewJobRegistry(JobHandler{Type: "reports.send", Handle: handle})
+	require.NoError(t, err)
+	mockRepository := mocks.NewMockIJobRepository(t)
+	mockRepository.EXPECT().FailExpired([]string{"reports.send"}, mock.Anything).Return(0, nil)
+	mockRepository.EXPECT().ClaimDue([]string{"reports.send"}, mock.Anything, mock.Anything).Return(jobs, nil).Once()
+	mockRepository.EXPECT().ClaimDue([]string{"reports.send"}, mock.Anything, mock.Anything).Return([]*model.JobModel{}, nil).Maybe()
+	return NewJobWorker(mockRepository, registry, options, slog.New(slog.DiscardHandler)), mockRepository
+}
+
+func TestUnitJobWorkerOutcomes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		attempts int
+		handle   func(ctx context.Context) error
+		expect   func(repository *mocks.MockIJobRepository, job *model.JobModel)
+	}{
+		{name: "succeeded", attempts: 1, handle: func(ctx context.Context) error {
+			if tenantID, _ := tenant.FromContext(ctx); tenantID != "acme" {
+				return fmt.Errorf("tenant %q", tenantID)
+			}
+			return nil
+		}, expect: func(repository *mocks.MockIJobRepository, job *model.JobModel) {
+			repository.EXPECT().MarkSucceeded(job, mock.Anything).Return(nil)
+		}},
+		{name: "retried", attempts: 2, handle: func(context.Context) error {
+			return errors.New("report service unavailable")
+		}, expect: func(repository *mocks.MockIJobRepository, job *model.JobModel) {
+			repository.EXPECT().Reschedule(job, "report service unavailable", mock.Anything, mock.Anything).
+				RunAndReturn(func(_ *model.JobModel, _ string, runAt time.Time, _ *context.Context) error {
+					assert.WithinDuration(t, time.Now().Add(10*time.Second), runAt, time.Second, "the third attempt waits 10s")
+					return nil
+				})
+		}},
+		{name: "failed after the last attempt", attempts: 3, handle: func(context.Context) error {
+			return errors.New("report service unavailable")
+		}, expect: func(repository *mocks.MockIJobRepository, job *model.JobModel) {
+			repository.EXPECT().MarkFailed(job, "report service unavailable", mock.Anything).Return(nil)
+		}},
+		{name: "failed permanently", attempts: 1, handle: func(context.Context) error {
+			return fmt.Errorf("%w: unknown report format", ErrJobPermanent)
+		}, expect: func(repository *mocks.MockIJobRepository, job *model.JobModel) {
+			repository.EXPECT().MarkFailed(job, "permanent job failure: unknown report format", mock.Anything).Return(nil)
+		}},
+		{name: "panicked", attempts: 1, handle: func(context.Context) error {
+			panic("boom")
+		}, expect: func(repository *mocks.MockIJobRepository, job *model.JobModel) {
+			repository.EXPECT().Reschedule(job, "job panicked: boom", mock.Anything, mock.Anything).Return(nil)
+		}},
+		{name: "timed out", attempts: 1, handle: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, expect: func(repository *mocks.MockIJobRepository, job *model.JobModel) {
+			repository.EXPECT().Reschedule(job, "context deadline exceeded", mock.Anything, mock.Anything).Return(nil)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			job := claimedJob(7, "reports.send", test.attempts)
+			job.Timeout = 50 * time.Millisecond
+			worker, mockRepository := newTestJobWorker(t, func(ctx context.Context, _ *model.JobModel) error {
+				return test.handle(ctx)
+			}, JobWorkerOptions{}, job)
+			test.expect(mockRepository, job)
+
+			worker.runDue(context.Background(), context.Background())
+			worker.running.Wait()
+		})
+	}
+}
+
+func TestUnitJobWorkerConcurrency(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	jobs := []*model.JobModel{claimedJob(1, "reports.send", 1), claimedJob(2, "reports.send", 1)}
+	worker, mockRepository := newTestJobWorker(t, func(context.Context, *model.JobModel) error {
+		<-release
+		return nil
+	}, JobWorkerOptions{Concurrency: 2}, jobs...)
+	mockRepository.EXPECT().MarkSucceeded(mock.Anything, mock.Anything).Return(nil).Times(2)
+
+	worker.runDue(context.Background(), context.Background())
+	mockRepository.AssertNumberOfCalls(t, "ClaimDue", 1)
+	worker.runDue(context.Background(), context.Background())
+	mockRepository.AssertNumberOfCalls(t, "ClaimDue", 1)
+	close(release)
+	worker.running.Wait()
+
+	worker.runDue(context.Background(), context.Background())
+	mockRepository.AssertNumberOfCalls(t, "ClaimDue", 2)
+}
+
+func TestUnitJobWorkerShutdown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		handle func(ctx context.Context, finish <-chan struct{}) error
+		expect func(repository *mocks.MockIJobRepository, job *model.JobModel)
+	}{
+		{name: "running jobs finish", handle: func(_ context.Context, finish <-chan struct{}) error {
+			<-finish
+			return nil
+		}, expect: func(repository *mocks.MockIJobRepository, job *model.JobModel) {
+			repository.EXPECT().MarkSucceeded(job, mock.Anything).Return(nil)
+		}},
+		{name: "running jobs are released after the shutdown timeout", handle: func(ctx context.Context, _ <-chan struct{}) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, expect: func(repository *mocks.MockIJobRepository, job *model.JobModel) {
+			repository.EXPECT().Release(job, mock.Anything).Return(nil)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			started, finish := make(chan struct{}), make(chan struct{})
+			job := claimedJob(7, "reports.send", 1)
+			job.Timeout = time.Minute
+			worker, mockRepository := newTestJobWorker(t, func(ctx context.Context, _ *model.JobModel) error {
+				close(started)
+				return test.handle(ctx, finish)
+			}, JobWorkerOptions{PollInterval: 10 * time.Millisecond, ShutdownTimeout: 200 * time.Millisecond}, job)
+			test.expect(mockRepository, job)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			stopped := make(chan struct{})
+			go func() {
+				worker.Run(ctx)
+				close(stopped)
+			}()
+			<-started
+			cancel()
+			select {
+			case <-stopped:
+				t.Fatal("the worker stopped before the running job ended")
+			case <-time.After(50 * time.Millisecond):
+			}
+			close(finish)
+			select {
+			case <-stopped:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the worker did not stop")
+			}
+		})
+	}
+}
+
+func TestUnitJobWorkerPeriodicJobs(t *testing.T) {
+	t.Parallel()
+
+	registry, err := NewJobRegistry(JobHandler{Type: "sessions.purge", Every: time.Hour,
+		Handle: func(context.Context, *model.JobModel) error { return nil }})
+	require.NoError(t, err)
+	mockRepository := mocks.NewMockIJobRepository(t)
+	scheduled := make(map[string]int)
+	mockRepository.EXPECT().Enqueue(mock.Anything, mock.Anything).
+		RunAndReturn(func(job *model.JobModel, ctx *context.Context) (*model.JobModel, error) {
+			tenantID, _ := tenant.FromContext(*ctx)
+			scheduled[tenantID]++
+			assert.Equal(t, "sessions.purge", job.Type)
+			require.NotNil(t, job.UniqueKey)
+			assert.Equal(t, "sessions.purge", *job.UniqueKey, "a tenant has one pending run at most")
+			assert.WithinDuration(t, time.Now().Add(time.Hour), job.RunAt, time.Minute)
+			return job, nil
+		})
+	mockRepository.EXPECT().FailExpired([]string{"sessions.purge"}, mock.Anything).Return(0, nil)
+	ran := claimedJob(7, "sessions.purge", 1)
+	mockRepository.EXPECT().ClaimDue([]string{"sessions.purge"}, mock.Anything, mock.Anything).Return([]*model.JobModel{ran}, nil).Once()
+	mockRepository.EXPECT().ClaimDue([]string{"sessions.purge"}, mock.Anything, mock.Anything).Return([]*model.JobModel{}, nil).Maybe()
+	mockRepository.EXPECT().MarkSucceeded(ran, mock.Anything).Return(nil)
+	worker := NewJobWorker(mockRepository, registry, JobWorkerOptions{
+		Tenants: func(context.Context) ([]string, error) { return []string{"acme", "globex"}, nil },
+	}, slog.New(slog.DiscardHandler))
+
+	worker.runDue(context.Background(), context.Background())
+	worker.running.Wait()
+	assert.Equal(t, map[string]int{"acme": 2, "globex": 1}, scheduled, "scheduled at startup and again after a run")
+
+	worker.runDue(context.Background(), context.Background())
+	assert.Equal(t, map[string]int{"acme": 2, "globex": 1}, scheduled, "scheduled again at the next sweep only")
+
+	worker = NewJobWorker(mockRepository, registry, JobWorkerOptions{}, slog.New(slog.DiscardHandler))
+	worker.schedule(context.Background(), registry.Periodic()...)
+	assert.Equal(t, 1, scheduled[tenant.DefaultTenant], "the shared schema schedules for the default tenant")
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/model"
+	"crud/internal/util/log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// MaxDebugTokenTTL bounds the validity of debug tokens, they cannot be revoked
+const MaxDebugTokenTTL = log.MaxDebugTokenLifetime
+
+var ErrDebugTokensDisabled = errors.New("debug tokens are disabled, no debug log secret is configured")
+
+type ILogLevelService interface {
+	GetLogLevel(ctx *context.Context) *model.LogLevelResponse
+	SetLogLevel(request *model.SetLogLevelRequest, ctx *context.Context) (*model.LogLevelResponse, error)
+	CreateDebugToken(request *model.DebugTokenRequest, ctx *context.Context) (*model.DebugTokenResponse, error)
+}
+
+type LogLevelService struct {
+	levelVar    *slog.LevelVar
+	debugSecret []byte
+	debugHeader string
+	logger      *slog.Logger
+}
+
+// NewLogLevelService changes the level of levelVar, debug tokens are signed with debugSecret and sent in debugHeader
+func NewLogLevelService(levelVar *slog.LevelVar, debugSecret []byte, debugHeader string, logger *slog.Logger) ILogLevelService {
+	return &LogLevelService{levelVar: levelVar, debugSecret: debugSecret, debugHeader: debugHeader, logger: logger}
+}
+
+func (service *LogLevelService) GetLogLevel(_ *context.Context) *model.LogLevelResponse {
+	return &model.LogLevelResponse{Level: service.levelVar.Level().String()}
+}
+
+// SetLogLevel accepts the slog level names, e.g. "debug" or "WARN", the change is logged with the caller
+func (service *LogLevelService) SetLogLevel(request *model.SetLogLevelRequest, ctx *context.Context) (*model.LogLevelResponse, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(request.Level)); err != nil {
+		return nil, err
+	}
+	previous := service.levelVar.Level()
+	service.levelVar.Set(level)
+	service.logger.WarnContext(*ctx, "log level changed",
+		slog.Bool("audit", true),
+		slog.String("previous", previous.String()),
+		slog.String("level", level.String()),
+		slog.String("subject", callerSubject(*ctx)))
+	return &model.LogLevelResponse{Level: level.String()}, nil
+}
+
+// CreateDebugToken signs a token which escalates the requests sending it to debug level
+func (service *LogLevelService) CreateDebugToken(request *model.DebugTokenRequest, ctx *context.Context) (*model.DebugTokenResponse, error) {
+	if len(service.debugSecret) == 0 {
+		return nil, ErrDebugTokensDisabled
+	}
+	ttl, err := time.ParseDuration(request.TTL)
+	if err != nil {
+		return nil, err
+	}
+	if ttl <= 0 || ttl > MaxDebugTokenTTL {
+		return nil, fmt.Errorf("ttl must be positive and at most %s", MaxDebugTokenTTL)
+	}
+	issuedAt := time.Now().Truncate(time.Second)
+	expiresAt := issuedAt.Add(ttl).Truncate(time.Second)
+	service.logger.WarnContext(*ctx, "debug token created",
+		slog.Bool("audit", true),
+		slog.Time("expires_at", expiresAt),
+		slog.String("subject", callerSubject(*ctx)))
+	return &model.DebugTokenResponse{
+		Header:    service.debugHeader,
+		Token:     log.SignDebugToken(service.debugSecret, issuedAt, expiresAt),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func callerSubject(ctx context.Context) string {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		return principal.Subject
+	}
+	return ""
+}
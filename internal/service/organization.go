@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"slices"
+	"strings"
+)
+
+const organizationResource = "organization"
+
+var organizationRoles = []string{model.OrganizationRoleOwner, model.OrganizationRoleAdmin, model.OrganizationRoleMember}
+
+// managerRoles are the roles of the members who may change an organization and its memberships
+var managerRoles = []string{model.OrganizationRoleOwner, model.OrganizationRoleAdmin}
+
+// IOrganizationService manages organizations and the memberships of users in them
+type IOrganizationService interface {
+	IService[int, model.OrganizationRequest, model.OrganizationResponse]
+	GetMembers(organizationID int, offset int, limit int, ctx *context.Context) ([]*model.MemberResponse, error)
+	AddMember(organizationID int, request *model.AddMemberRequest, ctx *context.Context) (*model.MembershipResponse, error)
+	RemoveMember(organizationID int, userID int, ctx *context.Context) (*model.MembershipResponse, error)
+	GetUserOrganizations(userID int, offset int, limit int, ctx *context.Context) ([]*model.UserOrganizationResponse, error)
+}
+
+// OrganizationService makes the user creating an organization its owner and keeps at least one owner
+// in every organization, membership changes run in a transaction. Callers with the organizations:read and
+// organizations:write permissions reach every organization. With the self permissions the members of an
+// organization may read it, its owners and admins may change it and its memberships, only owners may delete it
+// or add owners
+type OrganizationService struct {
+	IService[int, model.OrganizationRequest, model.OrganizationResponse]
+	membershipRepository repository.IMembershipRepository
+	transactor           repository.ITransactor
+	// policy is nil when authorization is disabled
+	policy *auth.Policy
+}
+
+func NewOrganizationService(organizationRepository repository.IOrganizationRepository, membershipRepository repository.IMembershipRepository,
+	transactor repository.ITransactor, policy *auth.Policy) IOrganizationService {
+	service := &OrganizationService{membershipRepository: membershipRepository, transactor: transactor, policy: policy}
+	service.IService = NewService[model.OrganizationModel, int, model.OrganizationRequest, model.OrganizationResponse](organizationRepository, Hooks[model.OrganizationModel, int, model.OrganizationRequest, model.OrganizationResponse]{
+		ToModel:     model.OrganizationRequestToOrganizationModel,
+		ToResponse:  model.OrganizationModelToOrganizationResponse,
+		Validate:    validateOrganizationRequest,
+		AfterCreate: service.addCreatorAsOwner,
+	})
+	return service
+}
+
+func validateOrganizationRequest(request *model.OrganizationRequest, _ context.Context) error {
+	if strings.TrimSpace(request.Name) == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// Create stores the organization and the owner membership of the calling user in one transaction
+func (service *OrganizationService) Create(request *model.OrganizationRequest, ctx *context.Context) (*model.OrganizationResponse, error) {
+	if service.policy != nil {
+		err := service.policy.AuthorizeMember(*ctx, auth.PermissionOrganizationsWrite, auth.PermissionOrganizationsWriteSelf,
+			organizationResource, nil, func(int) (bool, error) { return true, nil })
+		if err != nil {
+			return nil, err
+		}
+	}
+	var created *model.OrganizationResponse
+	err := service.transactor.InTx(ctx, func(ctx *context.Context) error {
+		var err error
+		created, err = service.IService.Create(request, ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (service *OrganizationService) GetById(id int, ctx *context.Context) (*model.OrganizationResponse, error) {
+	if err := service.authorize(ctx, auth.PermissionOrganizationsRead, auth.PermissionOrganizationsReadSelf, id, organizationRoles); err != nil {
+		return nil, err
+	}
+	return service.IService.GetById(id, ctx)
+}
+
+func (service *OrganizationService) Update(id int, request *model.OrganizationRequest, ctx *context.Context) (*model.OrganizationResponse, error) {
+	if err := service.authorize(ctx, auth.PermissionOrganizationsWrite, auth.PermissionOrganizationsWriteSelf, id, managerRoles); err != nil {
+		return nil, err
+	}
+	return service.IService.Update(id, request, ctx)
+}
+
+func (service *OrganizationService) Delete(id int, ctx *context.Context) (*model.OrganizationResponse, error) {
+	err := service.authorize(ctx, auth.PermissionOrganizationsWrite, auth.PermissionOrganizationsWriteSelf, id,
+		[]string{model.OrganizationRoleOwner})
+	if err != nil {
+		return nil, err
+	}
+	return service.IService.Delete(id, ctx)
+}
+
+// GetAll lists every organization of the tenant, callers with the self permission list theirs with GetUserOrganizations
+func (service *OrganizationService) GetAll(offset int, limit int, ctx *context.Context) ([]*model.OrganizationResponse, error) {
+	if service.policy != nil {
+		if err := service.policy.Authorize(*ctx, auth.PermissionOrganizationsRead, organizationResource, nil); err != nil {
+			return nil, err
+		}
+	}
+	return service.IService.GetAll(offset, limit, ctx)
+}
+
+// addCreatorAsOwner runs in the transaction of Create. Callers which do not identify a user, e.g. API keys,
+// create organizations without members
+func (service *OrganizationService) addCreatorAsOwner(organization *model.OrganizationModel, ctx context.Context) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	userID, isUser := principal.UserID()
+	if !isUser {
+		return nil
+	}
+	_, err := service.membershipRepository.Add(&model.MembershipModel{
+		OrganizationID: organization.ID,
+		UserID:         userID,
+		Role:           model.OrganizationRoleOwner,
+	}, &ctx)
+	return err
+}
+
+func (service *OrganizationService) GetMembers(organizationID int, offset int, limit int, ctx *context.Context) ([]*model.MemberResponse, error) {
+	err := service.authorize(ctx, auth.PermissionOrganizationsRead, auth.PermissionOrganizationsReadSelf, organizationID,
+		organizationRoles)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePage(offset, limit, DefaultMaxLimit); err != nil {
+		return nil, err
+	}
+	members, err := service.membershipRepository.GetMembers(organizationID, offset, limit, ctx)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*model.MemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = model.MemberModelToMemberResponse(member)
+	}
+	return responses, nil
+}
+
+func (service *OrganizationService) AddMember(organizationID int, request *model.AddMemberRequest, ctx *context.Context) (*model.MembershipResponse, error) {
+	if !slices.Contains(organizationRoles, request.Role) {
+		return nil, fmt.Errorf("%w: role must be one of %s", ErrInvalidRequest, strings.Join(organizationRoles, ", "))
+	}
+	roles := managerRoles
+	if request.Role == model.OrganizationRoleOwner {
+		roles = []string{model.OrganizationRoleOwner}
+	}
+	if err := service.authorize(ctx, auth.PermissionOrganizationsWrite, auth.PermissionOrganizationsWriteSelf, organizationID, roles); err != nil {
+		return nil, err
+	}
+	var added *model.MembershipModel
+	err := service.transactor.InTx(ctx, func(ctx *context.Context) error {
+		if err := service.membershipRepository.LockOrganization(organizationID, ctx); err != nil {
+			return err
+		}
+		var err error
+		added, err = service.membershipRepository.Add(&model.MembershipModel{
+			OrganizationID: organizationID,
+			UserID:         request.UserID,
+			Role:           request.Role,
+		}, ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return model.MembershipModelToMembershipResponse(added), nil
+}
+
+// RemoveMember removes the user from the organization, removing the last owner is rejected. Like adding them, removing
+// an owner takes an owner
+func (service *OrganizationService) RemoveMember(organizationID int, userID int, ctx *context.Context) (*model.MembershipResponse, error) {
+	err := service.authorize(ctx, auth.PermissionOrganizationsWrite, auth.PermissionOrganizationsWriteSelf, organizationID,
+		managerRoles)
+	if err != nil {
+		return nil, err
+	}
+	var removed *model.MembershipModel
+	err = service.transactor.InTx(ctx, func(ctx *context.Context) error {
+		if err := service.membershipRepository.LockOrganization(organizationID, ctx); err != nil {
+			return err
+		}
+		if err := service.authorizeOwnerRemoval(organizationID, userID, ctx); err != nil {
+			return err
+		}
+		var err error
+		if removed, err = service.membershipRepository.Remove(organizationID, userID, ctx); err != nil {
+			return err
+		}
+		if removed.Role != model.OrganizationRoleOwner {
+			return nil
+		}
+		owners, err := service.membershipRepository.CountOwners(organizationID, ctx)
+		if err != nil {
+			return err
+		}
+		if owners == 0 {
+			return fmt.Errorf("%w: the last owner of an organization cannot be removed", ErrInvalidRequest)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return model.MembershipModelToMembershipResponse(removed), nil
+}
+
+// authorizeOwnerRemoval requires the caller to be an owner when the user to remove is one, it runs before the removal
+// so that owners may remove themselves
+func (service *OrganizationService) authorizeOwnerRemoval(organizationID int, userID int, ctx *context.Context) error {
+	if service.policy == nil {
+		return nil
+	}
+	role, err := service.membershipRepository.GetRole(organizationID, userID, ctx)
+	if err != nil || role != model.OrganizationRoleOwner {
+		return err
+	}
+	return service.authorize(ctx, auth.PermissionOrganizationsWrite, auth.PermissionOrganizationsWriteSelf, organizationID,
+		[]string{model.OrganizationRoleOwner})
+}
+
+// GetUserOrganizations lists the organizations of the user, callers with the self permission may list their own only
+func (service *OrganizationService) GetUserOrganizations(userID int, offset int, limit int, ctx *context.Context) ([]*model.UserOrganizationResponse, error) {
+	if service.policy != nil {
+		err := service.policy.AuthorizeOwner(*ctx, auth.PermissionOrganizationsRead, auth.PermissionOrganizationsReadSelf,
+			organizationResource, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := validatePage(offset, limit, DefaultMaxLimit); err != nil {
+		return nil, err
+	}
+	organizations, err := service.membershipRepository.GetUserOrganizations(userID, offset, limit, ctx)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*model.UserOrganizationResponse, len(organizations))
+	for i, organization := range organizations {
+		responses[i] = model.UserOrganizationModelToUserOrganizationResponse(organization)
+	}
+	return responses, nil
+}
+
+// authorize allows callers with the permission, and callers with the self permission who are members of the
+// organization with one of the roles
+func (service *OrganizationService) authorize(ctx *context.Context, permission, selfPermission string, organizationID int, roles []string) error {
+	if service.policy == nil {
+		return nil
+	}
+	return service.policy.AuthorizeMember(*ctx, permission, selfPermission, organizationResource, organizationID,
+		func(userID int) (bool, error) {
+			role, err := service.membershipRepository.GetRole(organizationID, userID, ctx)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return slices.Contains(roles, role), nil
+		})
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+// newTestTransactor runs the units of work directly and records the error each of them ended with
+func newTestTransactor(t *testing.T, results *[]error) *mocks.MockITransactor {
+	transactor := mocks.NewMockITransactor(t)
+	transactor.EXPECT().
+		InTx(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx *context.Context, fn func(ctx *context.Context) error) error {
+			err := fn(ctx)
+			*results = append(*results, err)
+			return err
+		}).
+		Maybe()
+	return transactor
+}
+
+func TestUnitOrganizationServiceCreate(t *testing.T) {
+	t.Parallel()
+
+	mockOrganizations := mocks.NewMockIOrganizationRepository(t)
+	mockMemberships := mocks.NewMockIMembershipRepository(t)
+	transactions := make([]error, 0)
+	service := NewOrganizationService(mockOrganizations, mockMemberships, newTestTransactor(t, &transactions), nil)
+	ctx := contextWithPrincipal("5", "user")
+
+	mockOrganizations.EXPECT().
+		Create(&model.OrganizationModel{Name: "acme"}, mock.Anything).
+		Return(&model.OrganizationModel{ID: 3, Name: "acme"}, nil)
+	mockMemberships.EXPECT().
+		Add(&model.MembershipModel{OrganizationID: 3, UserID: 5, Role: model.OrganizationRoleOwner}, mock.Anything).
+		Return(&model.MembershipModel{OrganizationID: 3, UserID: 5, Role: model.OrganizationRoleOwner}, nil)
+	created, err := service.Create(&model.OrganizationRequest{Name: "acme"}, &ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, created.ID)
+
+	mockOrganizations.EXPECT().
+		Create(&model.OrganizationModel{Name: "fails"}, mock.Anything).
+		Return(&model.OrganizationModel{ID: 4, Name: "fails"}, nil)
+	mockMemberships.EXPECT().
+		Add(&model.MembershipModel{OrganizationID: 4, UserID: 5, Role: model.OrganizationRoleOwner}, mock.Anything).
+		Return(nil, errors.New("insert failed"))
+	_, err = service.Create(&model.OrganizationRequest{Name: "fails"}, &ctx)
+	assert.EqualError(t, err, "insert failed")
+	assert.Equal(t, []error{nil, err}, transactions, "the organization is rolled back when its owner cannot be added")
+}
+
+func TestUnitOrganizationServiceMembers(t *testing.T) {
+	t.Parallel()
+
+	mockMemberships := mocks.NewMockIMembershipRepository(t)
+	transactions := make([]error, 0)
+	service := NewOrganizationService(mocks.NewMockIOrganizationRepository(t), mockMemberships, newTestTransactor(t, &transactions), nil)
+	ctx := context.Background()
+
+	_, err := service.AddMember(3, &model.AddMemberRequest{UserID: 6, Role: "guest"}, &ctx)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+
+	mockMemberships.EXPECT().LockOrganization(3, mock.Anything).Return(nil)
+	mockMemberships.EXPECT().
+		Add(&model.MembershipModel{OrganizationID: 3, UserID: 6, Role: model.OrganizationRoleMember}, mock.Anything).
+		Return(&model.MembershipModel{OrganizationID: 3, UserID: 6, Role: model.OrganizationRoleMember}, nil)
+	added, err := service.AddMember(3, &model.AddMemberRequest{UserID: 6, Role: model.OrganizationRoleMember}, &ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &model.MembershipResponse{OrganizationID: 3, UserID: 6, Role: model.OrganizationRoleMember}, added)
+
+	mockMemberships.EXPECT().
+		Remove(3, 6, mock.Anything).
+		Return(&model.MembershipModel{OrganizationID: 3, UserID: 6, Role: model.OrganizationRoleMember}, nil)
+	_, err = service.RemoveMember(3, 6, &ctx)
+	require.NoError(t, err)
+
+	mockMemberships.EXPECT().
+		Remove(3, 5, mock.Anything).
+		Return(&model.MembershipModel{OrganizationID: 3, UserID: 5, Role: model.OrganizationRoleOwner}, nil)
+	mockMemberships.EXPECT().CountOwners(3, mock.Anything).Return(0, nil)
+	_, err = service.RemoveMember(3, 5, &ctx)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	assert.EqualError(t, err, "invalid request: the last owner of an organization cannot be removed")
+	assert.Equal(t, err, transactions[len(transactions)-1], "removing the last owner is rolled back")
+}
+
+func TestUnitOrganizationServiceAuthorization(t *testing.T) {
+	t.Parallel()
+	policy := auth.NewPolicy(auth.DefaultRolePermissions, slog.New(slog.DiscardHandler))
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		userID  int
+		allowed bool
+	}{
+		{"User can list own organizations", contextWithPrincipal("5", "user"), 5, true},
+		{"User cannot list organizations of others", contextWithPrincipal("5", "user"), 6, false},
+		{"Viewer can list organizations of others", contextWithPrincipal("7", "viewer"), 6, true},
+		{"Anonymous caller is denied", context.Background(), 5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMemberships := mocks.NewMockIMembershipRepository(t)
+			if tt.allowed {
+				mockMemberships.EXPECT().
+					GetUserOrganizations(tt.userID, 0, 10, mock.Anything).
+					Return([]*model.UserOrganizationModel{}, nil)
+			}
+			service := NewOrganizationService(mocks.NewMockIOrganizationRepository(t), mockMemberships,
+				mocks.NewMockITransactor(t), policy)
+
+			_, err := service.GetUserOrganizations(tt.userID, 0, 10, &tt.ctx)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, auth.ErrForbidden)
+			}
+		})
+	}
+
+	ctx := contextWithPrincipal("5", "user")
+	mockMemberships := mocks.NewMockIMembershipRepository(t)
+	mockMemberships.EXPECT().GetRole(3, 5, mock.Anything).Return(model.OrganizationRoleMember, nil)
+	service := NewOrganizationService(mocks.NewMockIOrganizationRepository(t), mockMemberships,
+		mocks.NewMockITransactor(t), policy)
+	_, err := service.RemoveMember(3, 6, &ctx)
+	assert.ErrorIs(t, err, auth.ErrForbidden, "members are managed by the owners and admins of the organization")
+}
+
+func TestUnitOrganizationServiceMembershipRoles(t *testing.T) {
+	t.Parallel()
+	policy := auth.NewPolicy(auth.DefaultRolePermissions, slog.New(slog.DiscardHandler))
+	member := &model.AddMemberRequest{UserID: 6, Role: model.OrganizationRoleMember}
+	owner := &model.AddMemberRequest{UserID: 6, Role: model.OrganizationRoleOwner}
+
+	tests := []struct {
+		name    string
+		role    string
+		request *model.AddMemberRequest
+		allowed bool
+	}{
+		{"Owner adds a member", model.OrganizationRoleOwner, member, true},
+		{"Owner adds an owner", model.OrganizationRoleOwner, owner, true},
+		{"Admin adds a member", model.OrganizationRoleAdmin, member, true},
+		{"Admin cannot add an owner", model.OrganizationRoleAdmin, owner, false},
+		{"Member cannot add a member", model.OrganizationRoleMember, member, false},
+		{"Non-member cannot add a member", "", member, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMemberships := mocks.NewMockIMembershipRepository(t)
+			if tt.role == "" {
+				mockMemberships.EXPECT().GetRole(3, 5, mock.Anything).Return("", pgx.ErrNoRows)
+			} else {
+				mockMemberships.EXPECT().GetRole(3, 5, mock.Anything).Return(tt.role, nil)
+			}
+			if tt.allowed {
+				mockMemberships.EXPECT().LockOrganization(3, mock.Anything).Return(nil)
+				mockMemberships.EXPECT().Add(mock.Anything, mock.Anything).
+					Return(&model.MembershipModel{OrganizationID: 3, UserID: 6, Role: tt.request.Role}, nil)
+			}
+			transactions := make([]error, 0)
+			service := NewOrganizationService(mocks.NewMockIOrganizationRepository(t), mockMemberships,
+				newTestTransactor(t, &transactions), policy)
+			ctx := contextWithPrincipal("5", "user")
+
+			_, err := service.AddMember(3, tt.request, &ctx)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, auth.ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestUnitOrganizationServiceRemoveMemberRoles(t *testing.T) {
+	t.Parallel()
+	policy := auth.NewPolicy(auth.DefaultRolePermissions, slog.New(slog.DiscardHandler))
+
+	tests := []struct {
+		name       string
+		callerRole string
+		userID     int
+		removed    string
+		allowed    bool
+	}{
+		{"Owner removes an owner", model.OrganizationRoleOwner, 6, model.OrganizationRoleOwner, true},
+		{"Owner removes themselves", model.OrganizationRoleOwner, 5, model.OrganizationRoleOwner, true},
+		{"Admin removes a member", model.OrganizationRoleAdmin, 6, model.OrganizationRoleMember, true},
+		{"Admin cannot remove an owner", model.OrganizationRoleAdmin, 6, model.OrganizationRoleOwner, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMemberships := mocks.NewMockIMembershipRepository(t)
+			mockMemberships.EXPECT().GetRole(3, 5, mock.Anything).Return(tt.callerRole, nil)
+			if tt.userID != 5 {
+				mockMemberships.EXPECT().GetRole(3, tt.userID, mock.Anything).Return(tt.removed, nil)
+			}
+			mockMemberships.EXPECT().LockOrganization(3, mock.Anything).Return(nil)
+			if tt.allowed {
+				mockMemberships.EXPECT().Remove(3, tt.userID, mock.Anything).
+					Return(&model.MembershipModel{OrganizationID: 3, UserID: tt.userID, Role: tt.removed}, nil)
+				if tt.removed == model.OrganizationRoleOwner {
+					mockMemberships.EXPECT().CountOwners(3, mock.Anything).Return(1, nil)
+				}
+			}
+			transactions := make([]error, 0)
+			service := NewOrganizationService(mocks.NewMockIOrganizationRepository(t), mockMemberships,
+				newTestTransactor(t, &transactions), policy)
+			ctx := contextWithPrincipal("5", "user")
+
+			_, err := service.RemoveMember(3, tt.userID, &ctx)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, auth.ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestUnitOrganizationServiceSelfAccess(t *testing.T) {
+	t.Parallel()
+	policy := auth.NewPolicy(auth.DefaultRolePermissions, slog.New(slog.DiscardHandler))
+	mockOrganizations := mocks.NewMockIOrganizationRepository(t)
+	mockMemberships := mocks.NewMockIMembershipRepository(t)
+	transactions := make([]error, 0)
+	service := NewOrganizationService(mockOrganizations, mockMemberships, newTestTransactor(t, &transactions), policy)
+	ctx := contextWithPrincipal("5", "user")
+
+	mockOrganizations.EXPECT().
+		Create(&model.OrganizationModel{Name: "acme"}, mock.Anything).
+		Return(&model.OrganizationModel{ID: 3, Name: "acme"}, nil)
+	mockMemberships.EXPECT().
+		Add(&model.MembershipModel{OrganizationID: 3, UserID: 5, Role: model.OrganizationRoleOwner}, mock.Anything).
+		Return(&model.MembershipModel{OrganizationID: 3, UserID: 5, Role: model.OrganizationRoleOwner}, nil)
+	_, err := service.Create(&model.OrganizationRequest{Name: "acme"}, &ctx)
+	require.NoError(t, err, "users create organizations of their own")
+
+	mockMemberships.EXPECT().GetRole(3, 5, mock.Anything).Return(model.OrganizationRoleMember, nil)
+	mockMemberships.EXPECT().GetMembers(3, 0, 10, mock.Anything).Return([]*model.MemberModel{}, nil)
+	_, err = service.GetMembers(3, 0, 10, &ctx)
+	assert.NoError(t, err, "members list the members of their organization")
+	_, err = service.Delete(3, &ctx)
+	assert.ErrorIs(t, err, auth.ErrForbidden, "only owners delete their organization")
+
+	mockMemberships.EXPECT().GetRole(4, 5, mock.Anything).Return("", pgx.ErrNoRows)
+	_, err = service.GetMembers(4, 0, 10, &ctx)
+	assert.ErrorIs(t, err, auth.ErrForbidden, "non-members cannot list the members")
+
+	_, err = service.GetAll(0, 10, &ctx)
+	assert.ErrorIs(t, err, auth.ErrForbidden, "users list their organizations with GetUserOrganizations")
+}
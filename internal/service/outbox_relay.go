@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"crud/internal/model"
+	"crud/internal/outbox"
+	"crud/internal/repository"
+	"crud/internal/tenant"
+	"crud/internal/util/request"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+const (
+	DefaultOutboxPollInterval = time.Second
+	DefaultOutboxBatchSize    = 100
+	// DefaultOutboxRetention is how long published events are kept
+	DefaultOutboxRetention = 24 * time.Hour
+	// DefaultOutboxMaxAttempts is the number of failed attempts after which an event is dead, about an hour of retries
+	DefaultOutboxMaxAttempts = 20
+
+	outboxMinBackoff    = time.Second
+	outboxMaxBackoff    = 5 * time.Minute
+	outboxSweepInterval = time.Hour
+	// outboxLease is how long the events of a batch stay claimed while they are published
+	outboxLease = 5 * time.Minute
+)
+
+// OutboxRelayOptions configures the relay, zero values fall back to the defaults
+type OutboxRelayOptions struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Retention    time.Duration
+	MaxAttempts  int
+	// Tenants lists the tenants whose outbox is relayed in the schema per tenant mode. When it is nil
+	// the outbox is shared by all tenants
+	Tenants func(ctx context.Context) ([]string, error)
+}
+
+// OutboxRelay publishes the events of the outbox to the sinks, at least once. An event is published to every sink
+// and retried with an exponential backoff until all of them accept it or it failed MaxAttempts times, then it is
+// dead. The events of an aggregate are published in the order they were written, one replica claims at a time
+type OutboxRelay struct {
+	outboxRepository repository.IOutboxRepository
+	transactor       repository.ITransactor
+	sinks            []outbox.Sink
+	options          OutboxRelayOptions
+	logger           *slog.Logger
+	lastSweep        time.Time
+}
+
+func NewOutboxRelay(outboxRepository repository.IOutboxRepository, transactor repository.ITransactor, sinks []outbox.Sink,
+	options OutboxRelayOptions, logger *slog.Logger) *OutboxRelay {
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultOutboxPollInterval
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultOutboxBatchSize
+	}
+	if options.Retention <= 0 {
+		options.Retention = DefaultOutboxRetention
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultOutboxMaxAttempts
+	}
+	return &OutboxRelay{
+		outboxRepository: outboxRepository,
+		transactor:       transactor,
+		sinks:            sinks,
+		options:          options,
+		logger:           logger,
+		lastSweep:        time.Now(),
+	}
+}
+
+// Run relays the due events every poll interval until ctx is done
+func (relay *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relay.options.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			relay.RelayAll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RelayAll relays the due events of every tenant and deletes the expired published events once in a while
+func (relay *OutboxRelay) RelayAll(ctx context.Context) {
+	contexts, err := tenantContexts(ctx, relay.options.Tenants)
+	if err != nil {
+		relay.logger.Warn("failed to list the tenants of the outbox", slog.String("error", err.Error()))
+		return
+	}
+	sweep := time.Since(relay.lastSweep) >= outboxSweepInterval
+	for _, tenantCtx := range contexts {
+		if err := relay.drain(tenantCtx); err != nil && !errors.Is(err, context.Canceled) {
+			relay.logger.Warn("failed to relay outbox events", slog.String("error", err.Error()))
+		}
+		if sweep {
+			relay.sweep(tenantCtx)
+		}
+	}
+	if sweep {
+		relay.lastSweep = time.Now()
+	}
+}
+
+// drain relays batches as long as events are published, a batch holds at most one event per aggregate
+func (relay *OutboxRelay) drain(ctx context.Context) error {
+	for ctx.Err() == nil {
+		published, err := relay.relayBatch(ctx)
+		if err != nil || published == 0 {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// relayBatch claims a batch of due events in a short transaction holding the relay lock, publishes them outside of
+// it and records the outcomes in a second transaction. Events whose outcome is not recorded are published again once
+// their claim expires
+func (relay *OutboxRelay) relayBatch(ctx context.Context) (int, error) {
+	var events []*model.OutboxEventModel
+	err := relay.transactor.InTx(&ctx, func(ctx *context.Context) error {
+		locked, err := relay.outboxRepository.TryLock(ctx)
+		if err != nil || !locked {
+			return err
+		}
+		events, err = relay.outboxRepository.ClaimDue(relay.options.BatchSize, time.Now().Add(outboxLease), ctx)
+		return err
+	})
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	errs := make([]error, len(events))
+	for i, event := range events {
+		errs[i] = relay.publish(ctx, event)
+	}
+
+	published := 0
+	err = relay.transactor.InTx(&ctx, func(ctx *context.Context) error {
+		for i, event := range events {
+			if errs[i] == nil {
+				if err := relay.outboxRepository.MarkPublished(event.ID, ctx); err != nil {
+					return err
+				}
+				published++
+				continue
+			}
+			if err := relay.markFailed(event, errs[i], ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, nil
+}
+
+// markFailed schedules the next attempt of the event, or marks it dead after its last one
+func (relay *OutboxRelay) markFailed(event *model.OutboxEventModel, publishErr error, ctx *context.Context) error {
+	attempts := event.Attempts + 1
+	attrs := []any{
+		slog.Int64("event_id", event.ID),
+		slog.String("event_type", event.Type),
+		slog.Int("attempts", attempts),
+		slog.String("error", publishErr.Error()),
+	}
+	if attempts >= relay.options.MaxAttempts {
+		relay.logger.Error("outbox event is dead", attrs...)
+		return relay.outboxRepository.MarkDead(event.ID, publishErr.Error(), ctx)
+	}
+	nextAttemptAt := time.Now().Add(outboxBackoff(attempts))
+	relay.logger.Warn("failed to publish outbox event", append(attrs, slog.Time("next_attempt_at", nextAttemptAt))...)
+	return relay.outboxRepository.MarkFailed(event.ID, nextAttemptAt, publishErr.Error(), ctx)
+}
+
+// publish runs in the request which recorded the event, the sinks pass its id on
+func (relay *OutboxRelay) publish(ctx context.Context, event *model.OutboxEventModel) error {
+	if event.RequestID != nil {
+		ctx = request.WithRequestID(ctx, *event.RequestID)
+	}
+	message := model.OutboxEventModelToEventMessage(event)
+	var err error
+	for _, sink := range relay.sinks {
+		err = errors.Join(err, sink.Publish(ctx, message))
+	}
+	return err
+}
+
+func (relay *OutboxRelay) sweep(ctx context.Context) {
+	deleted, err := relay.outboxRepository.DeletePublished(time.Now().Add(-relay.options.Retention), &ctx)
+	if err != nil {
+		relay.logger.Warn("failed to delete published outbox events", slog.String("error", err.Error()))
+		return
+	}
+	if deleted > 0 {
+		relay.logger.Debug("deleted published outbox events", slog.Int64("deleted", deleted))
+	}
+}
+
+// tenantContexts returns ctx for each of the tenants listed by tenants, or ctx alone when tenants is nil
+func tenantContexts(ctx context.Context, tenants func(ctx context.Context) ([]string, error)) ([]context.Context, error) {
+	if tenants == nil {
+		return []context.Context{ctx}, nil
+	}
+	tenantIDs, err := tenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	contexts := make([]context.Context, len(tenantIDs))
+	for i, tenantID := range tenantIDs {
+		contexts[i] = tenant.WithTenant(ctx, tenantID)
+	}
+	return contexts, nil
+}
+
+// outboxBackoff doubles the delay with every failed attempt, up to outboxMaxBackoff
+func outboxBackoff(attempts int) time.Duration {
+	return exponentialBackoff(attempts, outboxMinBackoff, outboxMaxBackoff)
+}
+
+// exponentialBackoff is minBackoff after the first failed attempt and doubles with every further one, up to maxBackoff
+func exponentialBackoff(attempts int, minBackoff, maxBackoff time.Duration) time.Duration {
+	backoff := minBackoff
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
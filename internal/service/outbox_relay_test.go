@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/outbox"
+	"crud/internal/tenant"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink records the events it gets and fails the events of the aggregates in failing
+type recordingSink struct {
+	mutex     sync.Mutex
+	published []*model.EventMessage
+	failing   map[string]bool
+	// onPublish is called with every event, if set
+	onPublish func()
+}
+
+func (sink *recordingSink) Publish(_ context.Context, event *model.EventMessage) error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	if sink.onPublish != nil {
+		sink.onPublish()
+	}
+	if sink.failing[event.AggregateID] {
+		return errors.New("webhook responded 503 Service Unavailable")
+	}
+	sink.published = append(sink.published, event)
+	return nil
+}
+
+func TestUnitOutboxRelay(t *testing.T) {
+	t.Parallel()
+
+	mockOutbox := mocks.NewMockIOutboxRepository(t)
+	transactions := make([]error, 0)
+	sink := &recordingSink{failing: map[string]bool{"6": true}}
+	sink.onPublish = func() {
+		assert.Equal(t, []error{nil}, transactions, "events are published after the claim committed")
+	}
+	relay := NewOutboxRelay(mockOutbox, newTestTransactor(t, &transactions), []outbox.Sink{sink}, OutboxRelayOptions{},
+		slog.New(slog.DiscardHandler))
+
+	mockOutbox.EXPECT().TryLock(mock.Anything).Return(true, nil)
+	var leaseUntil time.Time
+	mockOutbox.EXPECT().ClaimDue(DefaultOutboxBatchSize, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ int, lease time.Time, _ *context.Context) ([]*model.OutboxEventModel, error) {
+			leaseUntil = lease
+			return []*model.OutboxEventModel{
+				{ID: 1, TenantID: "acme", AggregateType: model.UserAggregate, AggregateID: "5", Type: model.UserCreatedEvent},
+				{ID: 2, TenantID: "acme", AggregateType: model.UserAggregate, AggregateID: "6", Type: model.UserCreatedEvent, Attempts: 2},
+			}, nil
+		}).Once()
+	mockOutbox.EXPECT().ClaimDue(DefaultOutboxBatchSize, mock.Anything, mock.Anything).Return([]*model.OutboxEventModel{}, nil).Once()
+	mockOutbox.EXPECT().MarkPublished(int64(1), mock.Anything).Return(nil)
+	var nextAttemptAt time.Time
+	mockOutbox.EXPECT().
+		MarkFailed(int64(2), mock.Anything, "webhook responded 503 Service Unavailable", mock.Anything).
+		RunAndReturn(func(_ int64, next time.Time, _ string, _ *context.Context) error {
+			nextAttemptAt = next
+			return nil
+		})
+
+	relay.RelayAll(context.Background())
+
+	require.Len(t, sink.published, 1)
+	assert.Equal(t, int64(1), sink.published[0].ID)
+	assert.Equal(t, "acme", sink.published[0].TenantID)
+	assert.WithinDuration(t, time.Now().Add(4*time.Second), nextAttemptAt, time.Second, "the third attempt waits 4s")
+	assert.WithinDuration(t, time.Now().Add(outboxLease), leaseUntil, time.Second)
+	assert.Equal(t, []error{nil, nil, nil}, transactions, "batches are claimed and marked until none is published")
+}
+
+func TestUnitOutboxRelayDeadEvent(t *testing.T) {
+	t.Parallel()
+
+	mockOutbox := mocks.NewMockIOutboxRepository(t)
+	transactions := make([]error, 0)
+	sink := &recordingSink{failing: map[string]bool{"6": true}}
+	relay := NewOutboxRelay(mockOutbox, newTestTransactor(t, &transactions), []outbox.Sink{sink},
+		OutboxRelayOptions{MaxAttempts: 3}, slog.New(slog.DiscardHandler))
+
+	mockOutbox.EXPECT().TryLock(mock.Anything).Return(true, nil)
+	mockOutbox.EXPECT().ClaimDue(DefaultOutboxBatchSize, mock.Anything, mock.Anything).
+		Return([]*model.OutboxEventModel{
+			{ID: 2, TenantID: "acme", AggregateType: model.UserAggregate, AggregateID: "6", Type: model.UserCreatedEvent, Attempts: 2},
+		}, nil).Once()
+	mockOutbox.EXPECT().MarkDead(int64(2), "webhook responded 503 Service Unavailable", mock.Anything).Return(nil)
+
+	relay.RelayAll(context.Background())
+	assert.Equal(t, []error{nil, nil}, transactions, "nothing was published, the relay stops after the batch")
+}
+
+func TestUnitOutboxRelayLockedByAnotherReplica(t *testing.T) {
+	t.Parallel()
+
+	mockOutbox := mocks.NewMockIOutboxRepository(t)
+	transactions := make([]error, 0)
+	relay := NewOutboxRelay(mockOutbox, newTestTransactor(t, &transactions), []outbox.Sink{&recordingSink{}},
+		OutboxRelayOptions{}, slog.New(slog.DiscardHandler))
+	mockOutbox.EXPECT().TryLock(mock.Anything).Return(false, nil)
+
+	relay.RelayAll(context.Background())
+	assert.Len(t, transactions, 1, "nothing is read without the lock")
+}
+
+func TestUnitOutboxRelayTenants(t *testing.T) {
+	t.Parallel()
+
+	mockOutbox := mocks.NewMockIOutboxRepository(t)
+	transactions := make([]error, 0)
+	relay := NewOutboxRelay(mockOutbox, newTestTransactor(t, &transactions), []outbox.Sink{&recordingSink{}},
+		OutboxRelayOptions{Tenants: func(context.Context) ([]string, error) {
+			return []string{tenant.DefaultTenant, "acme"}, nil
+		}}, slog.New(slog.DiscardHandler))
+	relayed := make([]string, 0)
+	mockOutbox.EXPECT().TryLock(mock.Anything).Return(true, nil)
+	mockOutbox.EXPECT().
+		ClaimDue(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ int, _ time.Time, ctx *context.Context) ([]*model.OutboxEventModel, error) {
+			tenantID, _ := tenant.FromContext(*ctx)
+			relayed = append(relayed, tenantID)
+			return []*model.OutboxEventModel{}, nil
+		})
+
+	relay.RelayAll(context.Background())
+	assert.Equal(t, []string{tenant.DefaultTenant, "acme"}, relayed, "the outbox of every tenant schema is relayed")
+}
+
+func TestUnitOutboxBackoff(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, time.Second, outboxBackoff(1))
+	assert.Equal(t, 2*time.Second, outboxBackoff(2))
+	assert.Equal(t, 64*time.Second, outboxBackoff(7))
+	assert.Equal(t, 5*time.Minute, outboxBackoff(50))
+}
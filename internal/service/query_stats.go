@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"crud/internal/model"
+	"crud/internal/querystats"
+	"crud/internal/repository"
+	"crud/internal/tenant"
+	"errors"
+)
+
+var ErrExplainDisabled = errors.New("explaining slow queries is disabled")
+
+type IQueryStatsService interface {
+	GetQueryStats(explain bool, ctx *context.Context) (*model.QueryStatsResponse, error)
+	Reset(ctx *context.Context)
+}
+
+type QueryStatsService struct {
+	collector           *querystats.Collector
+	queryPlanRepository repository.IQueryPlanRepository
+	explainEnabled      bool
+}
+
+func NewQueryStatsService(collector *querystats.Collector, queryPlanRepository repository.IQueryPlanRepository,
+	explainEnabled bool) IQueryStatsService {
+	return &QueryStatsService{collector: collector, queryPlanRepository: queryPlanRepository, explainEnabled: explainEnabled}
+}
+
+// GetQueryStats returns the statistics of the statements, with explain the last slow execution
+// of each statement is explained
+func (service *QueryStatsService) GetQueryStats(explain bool, ctx *context.Context) (*model.QueryStatsResponse, error) {
+	if explain && !service.explainEnabled {
+		return nil, ErrExplainDisabled
+	}
+	stats, dropped := service.collector.Snapshot()
+	statements := make([]*model.StatementStatsResponse, 0, len(stats))
+	for _, statementStats := range stats {
+		response := model.QueryStatsToStatementStatsResponse(statementStats)
+		if explain && statementStats.LastSample != nil {
+			// A failing EXPLAIN, e.g. of a statement using a dropped table, does not fail the others. The statement
+			// is explained for the tenant it ran for, whose schema the connection is checked out with
+			explainCtx := *ctx
+			if statementStats.LastSample.TenantID != "" {
+				explainCtx = tenant.WithTenant(explainCtx, statementStats.LastSample.TenantID)
+			}
+			plan, err := service.queryPlanRepository.Explain(statementStats.LastSample.SQL, statementStats.LastSample.Args, &explainCtx)
+			if err != nil {
+				response.PlanError = err.Error()
+			} else {
+				response.Plan = plan
+			}
+		}
+		statements = append(statements, response)
+	}
+	return &model.QueryStatsResponse{Statements: statements, Dropped: dropped}, nil
+}
+
+func (service *QueryStatsService) Reset(_ *context.Context) {
+	service.collector.Reset()
+}
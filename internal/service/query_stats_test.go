@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"crud/internal/mocks"
+	"crud/internal/querystats"
+	"crud/internal/tenant"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestUnitQueryStatsServiceExplainsForTheTenant(t *testing.T) {
+	t.Parallel()
+
+	collector := querystats.NewCollector(10)
+	collector.Record("SELECT * FROM users WHERE id = $1", time.Second, nil, true,
+		&querystats.Sample{SQL: "SELECT * FROM users WHERE id = $1", Args: []any{5}, TenantID: "acme"})
+	mockRepository := mocks.NewMockIQueryPlanRepository(t)
+	mockRepository.EXPECT().Explain("SELECT * FROM users WHERE id = $1", []any{5}, mock.Anything).
+		RunAndReturn(func(_ string, _ []any, ctx *context.Context) (json.RawMessage, error) {
+			tenantID, _ := tenant.FromContext(*ctx)
+			assert.Equal(t, "acme", tenantID, "the tenant of the statement, not the one of the caller")
+			return json.RawMessage(`[{"Plan":{}}]`), nil
+		})
+	service := NewQueryStatsService(collector, mockRepository, true)
+
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultTenant)
+	stats, err := service.GetQueryStats(true, &ctx)
+	require.NoError(t, err)
+	require.Len(t, stats.Statements, 1)
+	assert.JSONEq(t, `[{"Plan":{}}]`, string(stats.Statements[0].Plan))
+}
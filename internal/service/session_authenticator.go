@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/repository"
+	"crud/internal/tenant"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SessionAuthenticator authenticates session tokens issued by Login in the Bearer scheme and hands
+// every other bearer token to the next authenticator, e.g. the JWT verifier
+type SessionAuthenticator struct {
+	sessionRepository repository.ISessionRepository
+	roles             []string
+	next              auth.Authenticator
+}
+
+func NewSessionAuthenticator(sessionRepository repository.ISessionRepository, roles []string, next auth.Authenticator) *SessionAuthenticator {
+	return &SessionAuthenticator{sessionRepository: sessionRepository, roles: roles, next: next}
+}
+
+func (authenticator *SessionAuthenticator) Authenticate(ctx context.Context, credentials string) (*auth.Principal, error) {
+	if !strings.HasPrefix(credentials, SessionTokenPrefix) {
+		if authenticator.next == nil {
+			return nil, auth.ErrInvalidToken
+		}
+		return authenticator.next.Authenticate(ctx, credentials)
+	}
+	session, err := authenticator.sessionRepository.GetByTokenHash(hashSessionToken(credentials), &ctx)
+	if err != nil {
+		return nil, auth.ErrInvalidToken
+	}
+	if session.RevokedAt != nil || !session.ExpiresAt.After(time.Now()) {
+		return nil, auth.ErrInvalidToken
+	}
+	return &auth.Principal{
+		Subject:   strconv.Itoa(session.UserID),
+		Method:    "session",
+		Roles:     authenticator.roles,
+		Claims:    map[string]any{"session_id": session.ID, tenant.ClaimName: session.TenantID},
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
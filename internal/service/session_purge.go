@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"crud/internal/repository"
+	"log/slog"
+	"time"
+)
+
+// PurgeSessionsJob deletes the expired and revoked sessions, the workers run it every hour. In the schema per tenant
+// mode it runs for every tenant, in the shared schema once for all of them
+var PurgeSessionsJob = JobType[struct{}]{Name: "sessions.purge", Every: time.Hour}
+
+// NewPurgeSessionsHandler handles PurgeSessionsJob
+func NewPurgeSessionsHandler(sessionRepository repository.ISessionRepository, logger *slog.Logger) JobHandler {
+	return PurgeSessionsJob.Handler(func(ctx context.Context, _ struct{}) error {
+		deleted, err := sessionRepository.DeleteInactive(&ctx)
+		if err != nil {
+			return err
+		}
+		logger.DebugContext(ctx, "purged inactive sessions", slog.Int64("deleted", deleted))
+		return nil
+	})
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/tenant"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+func TestUnitPurgeSessionsHandler(t *testing.T) {
+	t.Parallel()
+
+	mockSessions := mocks.NewMockISessionRepository(t)
+	mockSessions.EXPECT().DeleteInactive(mock.Anything).
+		RunAndReturn(func(ctx *context.Context) (int64, error) {
+			tenantID, _ := tenant.FromContext(*ctx)
+			assert.Equal(t, "acme", tenantID, "the connection uses the schema of the tenant of the job")
+			return 2, nil
+		})
+	handler := NewPurgeSessionsHandler(mockSessions, slog.New(slog.DiscardHandler))
+	assert.Equal(t, PurgeSessionsJob.Name, handler.Type)
+	assert.Positive(t, handler.Every, "the workers schedule the purge")
+
+	ctx := tenant.WithTenant(context.Background(), "acme")
+	require.NoError(t, handler.Handle(ctx, &model.JobModel{Type: PurgeSessionsJob.Name, Payload: json.RawMessage("{}")}))
+}
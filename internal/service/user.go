@@ -4,7 +4,10 @@ import (
 	"context"
 	"crud/internal/model"
 	"crud/internal/repository"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"strconv"
 )
 
 const MaxUserLimit = 20
@@ -20,13 +23,20 @@ type IUserService interface {
 	GetUsers(offset int, limit int, ctx *context.Context) ([]*model.UserResponse, error)
 }
 
-// UserService is instance wrapper for IUserStore interface
+// UserService is instance wrapper for IUserStore interface. The changes of users are recorded as domain events
+// in the outbox, in the transaction of the change
 type UserService struct {
 	userRepository repository.IUserRepository
+	// outboxRepository is nil when no events are recorded
+	outboxRepository repository.IOutboxRepository
+	transactor       repository.ITransactor
+	logger           *slog.Logger
 }
 
-func NewUserService(userRepository repository.IUserRepository) IUserService {
-	return &UserService{userRepository: userRepository}
+func NewUserService(userRepository repository.IUserRepository, outboxRepository repository.IOutboxRepository,
+	transactor repository.ITransactor, logger *slog.Logger) IUserService {
+	return &UserService{userRepository: userRepository, outboxRepository: outboxRepository, transactor: transactor,
+		logger: logger}
 }
 
 func (service *UserService) Create(user *model.CreateUserRequest, ctx *context.Context) (*model.UserResponse, error) {
@@ -35,11 +45,9 @@ func (service *UserService) Create(user *model.CreateUserRequest, ctx *context.C
 		Age:   user.Age,
 		Email: user.Email,
 	}
-	newUserModel, err := service.userRepository.Create(createUserModel, ctx)
-	if err != nil {
-		return nil, err
-	}
-	return model.UserModelToUserResponse(newUserModel), nil
+	return service.change(model.UserCreatedEvent, ctx, func(ctx *context.Context) (*model.UserModel, error) {
+		return service.userRepository.Create(createUserModel, ctx)
+	})
 }
 
 func (service *UserService) GetById(id int, ctx *context.Context) (*model.UserResponse, error) {
@@ -57,19 +65,57 @@ func (service *UserService) Update(user *model.UpdateUserRequest, ctx *context.C
 		Age:   user.Age,
 		Email: user.Email,
 	}
-	userModel, err := service.userRepository.Update(updateUserModel, ctx)
-	if err != nil {
-		return nil, err
-	}
-	return model.UserModelToUserResponse(userModel), nil
+	return service.change(model.UserUpdatedEvent, ctx, func(ctx *context.Context) (*model.UserModel, error) {
+		return service.userRepository.Update(updateUserModel, ctx)
+	})
 }
 
 func (service *UserService) Delete(id int, ctx *context.Context) (*model.UserResponse, error) {
-	userModel, err := service.userRepository.Delete(id, ctx)
+	return service.change(model.UserDeletedEvent, ctx, func(ctx *context.Context) (*model.UserModel, error) {
+		return service.userRepository.Delete(id, ctx)
+	})
+}
+
+// change applies a change of a user and appends the event describing it to the outbox in one transaction,
+// the payload of the event is the user after the change, or the deleted user
+func (service *UserService) change(eventType string, ctx *context.Context,
+	apply func(ctx *context.Context) (*model.UserModel, error)) (*model.UserResponse, error) {
+	if service.outboxRepository == nil {
+		userModel, err := apply(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return model.UserModelToUserResponse(userModel), nil
+	}
+
+	var response *model.UserResponse
+	err := service.transactor.InTx(ctx, func(ctx *context.Context) error {
+		userModel, err := apply(ctx)
+		if err != nil {
+			return err
+		}
+		response = model.UserModelToUserResponse(userModel)
+		payload, err := json.Marshal(response)
+		if err != nil {
+			return err
+		}
+		event, err := service.outboxRepository.Append(&model.OutboxEventModel{
+			AggregateType: model.UserAggregate,
+			AggregateID:   strconv.Itoa(userModel.ID),
+			Type:          eventType,
+			Payload:       payload,
+		}, ctx)
+		if err != nil {
+			return err
+		}
+		service.logger.DebugContext(*ctx, "recorded user event", slog.Int64("event_id", event.ID),
+			slog.String("type", eventType), slog.Int("user_id", userModel.ID))
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return model.UserModelToUserResponse(userModel), nil
+	return response, nil
 }
 
 func (service *UserService) GetUsers(offset int, limit int, ctx *context.Context) ([]*model.UserResponse, error) {
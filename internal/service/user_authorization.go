@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/model"
+)
+
+const userResource = "user"
+
+// AuthorizedUserService checks the caller's permissions before delegating to the wrapped service
+type AuthorizedUserService struct {
+	userService IUserService
+	policy      *auth.Policy
+}
+
+func NewAuthorizedUserService(userService IUserService, policy *auth.Policy) IUserService {
+	return &AuthorizedUserService{userService: userService, policy: policy}
+}
+
+func (service *AuthorizedUserService) Create(user *model.CreateUserRequest, ctx *context.Context) (*model.UserResponse, error) {
+	if err := service.policy.Authorize(*ctx, auth.PermissionUsersWrite, userResource, nil); err != nil {
+		return nil, err
+	}
+	return service.userService.Create(user, ctx)
+}
+
+func (service *AuthorizedUserService) GetById(id int, ctx *context.Context) (*model.UserResponse, error) {
+	if err := service.policy.Authorize(*ctx, auth.PermissionUsersRead, userResource, id); err != nil {
+		return nil, err
+	}
+	return service.userService.GetById(id, ctx)
+}
+
+func (service *AuthorizedUserService) Update(user *model.UpdateUserRequest, ctx *context.Context) (*model.UserResponse, error) {
+	err := service.policy.AuthorizeOwner(*ctx, auth.PermissionUsersWrite, auth.PermissionUsersWriteSelf, userResource, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	return service.userService.Update(user, ctx)
+}
+
+func (service *AuthorizedUserService) Delete(id int, ctx *context.Context) (*model.UserResponse, error) {
+	if err := service.policy.Authorize(*ctx, auth.PermissionUsersWrite, userResource, id); err != nil {
+		return nil, err
+	}
+	return service.userService.Delete(id, ctx)
+}
+
+func (service *AuthorizedUserService) GetUsers(offset int, limit int, ctx *context.Context) ([]*model.UserResponse, error) {
+	if err := service.policy.Authorize(*ctx, auth.PermissionUsersRead, userResource, nil); err != nil {
+		return nil, err
+	}
+	return service.userService.GetUsers(offset, limit, ctx)
+}
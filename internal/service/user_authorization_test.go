@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/util/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+func contextWithPrincipal(subject string, roles ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{Subject: subject, Method: "jwt", Roles: roles})
+}
+
+func TestUnitAuthorizedUserServiceReadAccess(t *testing.T) {
+	t.Parallel()
+	policy := auth.NewPolicy(auth.DefaultRolePermissions, slog.New(slog.DiscardHandler))
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		allowed bool
+	}{
+		{"Viewer can list users", contextWithPrincipal("7", "viewer"), true},
+		{"Admin can list users", contextWithPrincipal("1", "admin"), true},
+		{"Unknown role is denied", contextWithPrincipal("7", "guest"), false},
+		{"Anonymous caller is denied", context.Background(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockIUserService(t)
+			if tt.allowed {
+				mockService.EXPECT().
+					GetUsers(0, 10, mock.Anything).
+					Return([]*model.UserResponse{}, nil)
+			}
+			service := NewAuthorizedUserService(mockService, policy)
+
+			_, err := service.GetUsers(0, 10, &tt.ctx)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, auth.ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestUnitAuthorizedUserServiceOwnership(t *testing.T) {
+	t.Parallel()
+	policy := auth.NewPolicy(auth.DefaultRolePermissions, slog.New(slog.DiscardHandler))
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		userID  int
+		allowed bool
+	}{
+		{"User can update own record", contextWithPrincipal("7", "user"), 7, true},
+		{"User cannot update another record", contextWithPrincipal("7", "user"), 8, false},
+		{"Viewer cannot update own record", contextWithPrincipal("7", "viewer"), 7, false},
+		{"Admin can update any record", contextWithPrincipal("1", "admin"), 8, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &model.UpdateUserRequest{Id: tt.userID, Name: "Name"}
+			mockService := mocks.NewMockIUserService(t)
+			if tt.allowed {
+				mockService.EXPECT().
+					Update(request, mock.Anything).
+					Return(&model.UserResponse{ID: tt.userID}, nil)
+			}
+			service := NewAuthorizedUserService(mockService, policy)
+
+			_, err := service.Update(request, &tt.ctx)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, auth.ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestUnitAuthorizedUserServiceScopes(t *testing.T) {
+	t.Parallel()
+	logger, logs := log.NewCaptureLogger(slog.LevelInfo)
+	policy := auth.NewPolicy(auth.DefaultRolePermissions, logger)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		Subject: "service-a",
+		Scopes:  []string{auth.PermissionUsersWrite},
+	})
+
+	mockService := mocks.NewMockIUserService(t)
+	mockService.EXPECT().Delete(3, mock.Anything).Return(&model.UserResponse{ID: 3}, nil)
+	service := NewAuthorizedUserService(mockService, policy)
+
+	_, err := service.Delete(3, &ctx)
+	assert.NoError(t, err)
+	_, err = service.GetById(3, &ctx)
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+
+	records := logs.Records()
+	require.Len(t, records, 1, "only the denied call is audited")
+	attrs := log.Attrs(records[0])
+	assert.Equal(t, "access denied", records[0].Message)
+	assert.Equal(t, "service-a", attrs["subject"].String())
+	assert.Equal(t, auth.PermissionUsersRead, attrs["permission"].String())
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"crud/internal/stream"
+	"crud/internal/tenant"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+// IUserLiveService answers the live queries of the WebSocket API. A connection watches the user changes of its
+// tenant, every query starts with a snapshot and follows the changes with a UserLiveQuery
+type IUserLiveService interface {
+	// Watch subscribes to the user changes of the tenant of ctx, the subscription has to be closed
+	Watch(ctx *context.Context) (*stream.Subscription, error)
+	// Snapshot returns the users in the result of the query, the snapshot of a filtered query holds at most its limit
+	Snapshot(query *model.UserQuery, ctx *context.Context) ([]*model.UserResponse, error)
+}
+
+type UserLiveService struct {
+	userRepository repository.IUserRepository
+	broker         *stream.Broker
+	// policy is nil when authorization is disabled
+	policy *auth.Policy
+}
+
+func NewUserLiveService(userRepository repository.IUserRepository, broker *stream.Broker, policy *auth.Policy) IUserLiveService {
+	return &UserLiveService{userRepository: userRepository, broker: broker, policy: policy}
+}
+
+func (service *UserLiveService) Watch(ctx *context.Context) (*stream.Subscription, error) {
+	if err := service.authorize(ctx); err != nil {
+		return nil, err
+	}
+	tenantID, ok := tenant.FromContext(*ctx)
+	if !ok {
+		return nil, tenant.ErrMissingTenant
+	}
+	return service.broker.Subscribe(tenantID, nil)
+}
+
+func (service *UserLiveService) Snapshot(query *model.UserQuery, ctx *context.Context) ([]*model.UserResponse, error) {
+	if err := service.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if err := validateUserQuery(query); err != nil {
+		return nil, err
+	}
+	var users []*model.UserModel
+	if query.UserID != nil {
+		user, err := service.userRepository.GetById(*query.UserID, ctx)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		if user != nil {
+			users = append(users, user)
+		}
+	} else {
+		filter := query.Filter
+		if filter == nil {
+			filter = &model.UserFilter{}
+		}
+		var err error
+		if users, err = service.userRepository.Find(filter, query.Limit, ctx); err != nil {
+			return nil, err
+		}
+	}
+	responses := make([]*model.UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = model.UserModelToUserResponse(user)
+	}
+	return responses, nil
+}
+
+func (service *UserLiveService) authorize(ctx *context.Context) error {
+	if service.policy == nil {
+		return nil
+	}
+	return service.policy.Authorize(*ctx, auth.PermissionUsersRead, userResource, nil)
+}
+
+// validateUserQuery defaults the limit of filtered queries to DefaultMaxLimit
+func validateUserQuery(query *model.UserQuery) error {
+	if query == nil {
+		return fmt.Errorf("%w: query is required", ErrInvalidRequest)
+	}
+	if query.UserID != nil {
+		if query.Filter != nil || query.Limit != 0 {
+			return fmt.Errorf("%w: user_id cannot be combined with filter or limit", ErrInvalidRequest)
+		}
+		return nil
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultMaxLimit
+	}
+	if err := validatePage(0, query.Limit, DefaultMaxLimit); err != nil {
+		return err
+	}
+	if filter := query.Filter; filter != nil && filter.MinAge != nil && filter.MaxAge != nil && *filter.MinAge > *filter.MaxAge {
+		return fmt.Errorf("%w: min_age cannot be greater than max_age", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// UserLiveQuery keeps the ids of the users in the result of a query and turns the user changes into the changes of
+// the result. Changes may arrive for users the snapshot already shows, they are sent as updates
+type UserLiveQuery struct {
+	id      string
+	query   *model.UserQuery
+	userIDs map[int]struct{}
+}
+
+func NewUserLiveQuery(id string, query *model.UserQuery, snapshot []*model.UserResponse) *UserLiveQuery {
+	userIDs := make(map[int]struct{}, len(snapshot))
+	for _, user := range snapshot {
+		userIDs[user.ID] = struct{}{}
+	}
+	return &UserLiveQuery{id: id, query: query, userIDs: userIDs}
+}
+
+// Apply returns the change of the result of the query for the user change, nil when the result does not change.
+// Users which start to match a filtered query are added, beyond the limit of its snapshot
+func (liveQuery *UserLiveQuery) Apply(event *model.EventMessage) (*model.LiveMessage, error) {
+	user := &model.UserModel{}
+	if err := json.Unmarshal(event.Payload, user); err != nil {
+		return nil, fmt.Errorf("invalid payload of event %d: %w", event.ID, err)
+	}
+	_, known := liveQuery.userIDs[user.ID]
+	matches := event.Type != model.UserDeletedEvent && liveQuery.query.Matches(user)
+	var messageType string
+	switch {
+	case matches && known:
+		messageType = model.LiveUpdated
+	case matches:
+		messageType = model.LiveAdded
+		liveQuery.userIDs[user.ID] = struct{}{}
+	case known:
+		messageType = model.LiveRemoved
+		delete(liveQuery.userIDs, user.ID)
+	default:
+		return nil, nil
+	}
+	return &model.LiveMessage{Type: messageType, ID: liveQuery.id, User: model.UserModelToUserResponse(user), EventID: event.ID}, nil
+}
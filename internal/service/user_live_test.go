@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/stream"
+	"crud/internal/tenant"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+func TestUnitUserLiveServiceSnapshot(t *testing.T) {
+	t.Parallel()
+
+	userID, minAge, maxAge := 5, 40, 30
+	tests := []struct {
+		name    string
+		query   *model.UserQuery
+		setup   func(repository *mocks.MockIUserRepository)
+		users   int
+		message string
+	}{
+		{name: "user", query: &model.UserQuery{UserID: &userID}, setup: func(repository *mocks.MockIUserRepository) {
+			repository.EXPECT().GetById(5, mock.Anything).Return(&model.UserModel{ID: 5, Name: "Ada"}, nil)
+		}, users: 1},
+		{name: "missing user", query: &model.UserQuery{UserID: &userID}, setup: func(repository *mocks.MockIUserRepository) {
+			repository.EXPECT().GetById(5, mock.Anything).Return(nil, pgx.ErrNoRows)
+		}},
+		{name: "all users", query: &model.UserQuery{}, setup: func(repository *mocks.MockIUserRepository) {
+			repository.EXPECT().Find(&model.UserFilter{}, DefaultMaxLimit, mock.Anything).
+				Return([]*model.UserModel{{ID: 1}, {ID: 2}}, nil)
+		}, users: 2},
+		{name: "filtered users", query: &model.UserQuery{Filter: &model.UserFilter{Name: "ada"}, Limit: 5},
+			setup: func(repository *mocks.MockIUserRepository) {
+				repository.EXPECT().Find(&model.UserFilter{Name: "ada"}, 5, mock.Anything).
+					Return([]*model.UserModel{{ID: 1, Name: "Ada"}}, nil)
+			}, users: 1},
+		{name: "no query", message: "invalid request: query is required"},
+		{name: "user and filter", query: &model.UserQuery{UserID: &userID, Filter: &model.UserFilter{}},
+			message: "invalid request: user_id cannot be combined with filter or limit"},
+		{name: "limit too large", query: &model.UserQuery{Limit: DefaultMaxLimit + 1},
+			message: fmt.Sprintf("invalid request: limit cannot be greater than %d", DefaultMaxLimit)},
+		{name: "empty age range", query: &model.UserQuery{Filter: &model.UserFilter{MinAge: &minAge, MaxAge: &maxAge}},
+			message: "invalid request: min_age cannot be greater than max_age"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			mockRepository := mocks.NewMockIUserRepository(t)
+			if test.setup != nil {
+				test.setup(mockRepository)
+			}
+			service := NewUserLiveService(mockRepository, stream.NewBroker(0), nil)
+
+			ctx := tenant.WithTenant(context.Background(), "acme")
+			users, err := service.Snapshot(test.query, &ctx)
+			if test.message != "" {
+				assert.EqualError(t, err, test.message)
+				assert.ErrorIs(t, err, ErrInvalidRequest)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, users, test.users)
+		})
+	}
+}
+
+func TestUnitUserLiveServiceAuthorization(t *testing.T) {
+	t.Parallel()
+
+	broker := stream.NewBroker(0)
+	policy := auth.NewPolicy(auth.DefaultRolePermissions, slog.New(slog.DiscardHandler))
+	service := NewUserLiveService(mocks.NewMockIUserRepository(t), broker, policy)
+
+	anonymous := tenant.WithTenant(context.Background(), "acme")
+	_, err := service.Watch(&anonymous)
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+	_, err = service.Snapshot(&model.UserQuery{}, &anonymous)
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+
+	withoutTenant := contextWithPrincipal("2", "viewer")
+	_, err = service.Watch(&withoutTenant)
+	assert.ErrorIs(t, err, tenant.ErrMissingTenant)
+
+	viewer := tenant.WithTenant(contextWithPrincipal("2", "viewer"), "acme")
+	subscription, err := service.Watch(&viewer)
+	require.NoError(t, err)
+	defer subscription.Close()
+	assert.Equal(t, 1, broker.Len())
+}
+
+func TestUnitUserLiveQueryApply(t *testing.T) {
+	t.Parallel()
+
+	change := func(id int64, eventType string, user model.UserModel) *model.EventMessage {
+		payload, _ := json.Marshal(user)
+		return &model.EventMessage{ID: id, TenantID: "acme", Type: eventType, Payload: payload}
+	}
+	minAge := 30
+	liveQuery := NewUserLiveQuery("adults", &model.UserQuery{Filter: &model.UserFilter{MinAge: &minAge}},
+		[]*model.UserResponse{{ID: 1, Name: "Ada", Age: 36}})
+
+	tests := []struct {
+		name        string
+		event       *model.EventMessage
+		messageType string
+	}{
+		{"matching user created", change(10, model.UserCreatedEvent, model.UserModel{ID: 2, Name: "Alan", Age: 41}), model.LiveAdded},
+		{"other user created", change(11, model.UserCreatedEvent, model.UserModel{ID: 3, Name: "Tim", Age: 12}), ""},
+		{"user of the snapshot updated", change(12, model.UserUpdatedEvent, model.UserModel{ID: 1, Name: "Ada L.", Age: 37}), model.LiveUpdated},
+		{"user no longer matches", change(13, model.UserUpdatedEvent, model.UserModel{ID: 2, Name: "Alan", Age: 29}), model.LiveRemoved},
+		{"user starts to match", change(14, model.UserUpdatedEvent, model.UserModel{ID: 3, Name: "Tim", Age: 30}), model.LiveAdded},
+		{"user deleted", change(15, model.UserDeletedEvent, model.UserModel{ID: 1, Name: "Ada L.", Age: 37}), model.LiveRemoved},
+		{"removed user deleted", change(16, model.UserDeletedEvent, model.UserModel{ID: 2, Name: "Alan", Age: 29}), ""},
+	}
+	// The changes build on each other, the subtests run in order
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message, err := liveQuery.Apply(test.event)
+			require.NoError(t, err)
+			if test.messageType == "" {
+				assert.Nil(t, message)
+				return
+			}
+			require.NotNil(t, message)
+			assert.Equal(t, test.messageType, message.Type)
+			assert.Equal(t, "adults", message.ID)
+			assert.Equal(t, test.event.ID, message.EventID)
+			var user model.UserModel
+			require.NoError(t, json.Unmarshal(test.event.Payload, &user))
+			assert.Equal(t, model.UserModelToUserResponse(&user), message.User)
+		})
+	}
+
+	userID := 5
+	single := NewUserLiveQuery("ada", &model.UserQuery{UserID: &userID}, nil)
+	message, err := single.Apply(change(20, model.UserUpdatedEvent, model.UserModel{ID: 6}))
+	require.NoError(t, err)
+	assert.Nil(t, message, "changes of other users are ignored")
+	_, err = single.Apply(&model.EventMessage{ID: 21, Payload: json.RawMessage(`[]`)})
+	assert.EqualError(t, err, "invalid payload of event 21: json: cannot unmarshal array into Go value of type model.UserModel")
+}
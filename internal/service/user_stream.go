@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"crud/internal/stream"
+	"crud/internal/tenant"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultUserStreamLogSize is how many user changes the log keeps per tenant for streams to resume from
+	DefaultUserStreamLogSize = 10000
+	// MaxUserStreamBacklog is how many changes a stream resumes with at most, clients further behind are reset
+	MaxUserStreamBacklog = 1000
+
+	userStreamTrimInterval = time.Minute
+)
+
+// UserStreamOptions configures the stream, zero values fall back to the defaults
+type UserStreamOptions struct {
+	LogSize int
+	// Tenants lists the tenants whose log is trimmed in the schema per tenant mode. When it is nil the log
+	// is shared by all tenants
+	Tenants func(ctx context.Context) ([]string, error)
+}
+
+type IUserStreamService interface {
+	// Open subscribes to the user changes of the tenant of ctx, of the given types or of all types when there are
+	// none. With a last event id the stream resumes with the logged changes after it. The stream has to be closed
+	Open(lastEventID *int64, types []string, ctx *context.Context) (*stream.Stream, error)
+}
+
+// UserStreamService streams the user changes notified by the database and trims the log streams resume from
+type UserStreamService struct {
+	changeRepository repository.IUserChangeRepository
+	broker           *stream.Broker
+	// policy is nil when authorization is disabled
+	policy  *auth.Policy
+	options UserStreamOptions
+	logger  *slog.Logger
+}
+
+func NewUserStreamService(changeRepository repository.IUserChangeRepository, broker *stream.Broker, policy *auth.Policy,
+	options UserStreamOptions, logger *slog.Logger) *UserStreamService {
+	if options.LogSize <= 0 {
+		options.LogSize = DefaultUserStreamLogSize
+	}
+	return &UserStreamService{changeRepository: changeRepository, broker: broker, policy: policy, options: options, logger: logger}
+}
+
+func (service *UserStreamService) Open(lastEventID *int64, types []string, ctx *context.Context) (*stream.Stream, error) {
+	if service.policy != nil {
+		if err := service.policy.Authorize(*ctx, auth.PermissionUsersRead, userResource, nil); err != nil {
+			return nil, err
+		}
+	}
+	for _, eventType := range types {
+		if !slices.Contains(model.EventTypes, eventType) {
+			return nil, fmt.Errorf("%w: unknown event type %q, use %s", ErrInvalidRequest, eventType,
+				strings.Join(model.EventTypes, ", "))
+		}
+	}
+	tenantID, ok := tenant.FromContext(*ctx)
+	if !ok {
+		return nil, tenant.ErrMissingTenant
+	}
+	// The subscription starts before the log is read, the changes in between are both in the backlog and live
+	subscription, err := service.broker.Subscribe(tenantID, types)
+	if err != nil {
+		return nil, err
+	}
+	if lastEventID == nil {
+		return stream.NewStream(subscription, nil), nil
+	}
+	backlog, reset, err := service.backlog(*lastEventID, types, ctx)
+	if err != nil {
+		subscription.Close()
+		return nil, err
+	}
+	userStream := stream.NewStream(subscription, backlog)
+	userStream.Reset = reset
+	return userStream, nil
+}
+
+// backlog returns the logged changes after lastEventID, it reports a reset instead when some of them may have been
+// trimmed or there are more than MaxUserStreamBacklog. The ids are shared by all tenants and the log is trimmed per
+// tenant, so changes may be missing only when the last event itself was trimmed
+func (service *UserStreamService) backlog(lastEventID int64, types []string, ctx *context.Context) ([]*model.EventMessage, bool, error) {
+	oldestID, err := service.changeRepository.OldestID(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if oldestID > lastEventID {
+		return nil, true, nil
+	}
+	backlog, err := service.changeRepository.GetAfter(lastEventID, types, MaxUserStreamBacklog+1, ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(backlog) > MaxUserStreamBacklog {
+		return nil, true, nil
+	}
+	return backlog, false, nil
+}
+
+// Run trims the log of every tenant every minute until ctx is done
+func (service *UserStreamService) Run(ctx context.Context) {
+	ticker := time.NewTicker(userStreamTrimInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			service.TrimAll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// TrimAll trims the log to the newest log size changes of every tenant, in every tenant schema in the schema per
+// tenant mode
+func (service *UserStreamService) TrimAll(ctx context.Context) {
+	contexts, err := tenantContexts(ctx, service.options.Tenants)
+	if err != nil {
+		service.logger.Warn("failed to list the tenants of the user change log", slog.String("error", err.Error()))
+		return
+	}
+	for _, tenantCtx := range contexts {
+		deleted, err := service.changeRepository.Trim(service.options.LogSize, &tenantCtx)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				service.logger.Warn("failed to trim the user change log", slog.String("error", err.Error()))
+			}
+			continue
+		}
+		if deleted > 0 {
+			service.logger.Debug("trimmed the user change log", slog.Int64("deleted", deleted))
+		}
+	}
+}
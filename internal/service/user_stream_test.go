@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/stream"
+	"crud/internal/tenant"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+func lastEventID(id int64) *int64 {
+	return &id
+}
+
+func TestUnitUserStreamServiceOpen(t *testing.T) {
+	t.Parallel()
+
+	backlog := []*model.EventMessage{{ID: 6, TenantID: "acme", Type: model.UserCreatedEvent}, {ID: 7, TenantID: "acme", Type: model.UserDeletedEvent}}
+	tests := []struct {
+		name        string
+		lastEventID *int64
+		types       []string
+		setup       func(repository *mocks.MockIUserChangeRepository)
+		reset       bool
+		backlog     []*model.EventMessage
+	}{
+		{name: "without last event id", setup: func(*mocks.MockIUserChangeRepository) {}},
+		{name: "resume", lastEventID: lastEventID(5), types: []string{model.UserCreatedEvent, model.UserDeletedEvent},
+			setup: func(repository *mocks.MockIUserChangeRepository) {
+				repository.EXPECT().OldestID(mock.Anything).Return(3, nil)
+				repository.EXPECT().GetAfter(int64(5), []string{model.UserCreatedEvent, model.UserDeletedEvent},
+					MaxUserStreamBacklog+1, mock.Anything).Return(backlog, nil)
+			}, backlog: backlog},
+		{name: "resume from the oldest change", lastEventID: lastEventID(3),
+			setup: func(repository *mocks.MockIUserChangeRepository) {
+				repository.EXPECT().OldestID(mock.Anything).Return(3, nil)
+				repository.EXPECT().GetAfter(int64(3), []string(nil), MaxUserStreamBacklog+1, mock.Anything).Return(backlog, nil)
+			}, backlog: backlog},
+		{name: "trimmed changes", lastEventID: lastEventID(2),
+			setup: func(repository *mocks.MockIUserChangeRepository) {
+				repository.EXPECT().OldestID(mock.Anything).Return(3, nil)
+			}, reset: true},
+		{name: "too far behind", lastEventID: lastEventID(1),
+			setup: func(repository *mocks.MockIUserChangeRepository) {
+				repository.EXPECT().OldestID(mock.Anything).Return(1, nil)
+				repository.EXPECT().GetAfter(int64(1), []string(nil), MaxUserStreamBacklog+1, mock.Anything).
+					Return(make([]*model.EventMessage, MaxUserStreamBacklog+1), nil)
+			}, reset: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			mockRepository := mocks.NewMockIUserChangeRepository(t)
+			test.setup(mockRepository)
+			broker := stream.NewBroker(0)
+			service := NewUserStreamService(mockRepository, broker, nil, UserStreamOptions{}, slog.New(slog.DiscardHandler))
+
+			ctx := tenant.WithTenant(context.Background(), "acme")
+			userStream, err := service.Open(test.lastEventID, test.types, &ctx)
+			require.NoError(t, err)
+			defer userStream.Close()
+			assert.Equal(t, test.reset, userStream.Reset)
+			assert.Equal(t, test.backlog, userStream.Backlog)
+			assert.Equal(t, 1, broker.Len())
+		})
+	}
+}
+
+func TestUnitUserStreamServiceOpenErrors(t *testing.T) {
+	t.Parallel()
+
+	mockRepository := mocks.NewMockIUserChangeRepository(t)
+	broker := stream.NewBroker(0)
+	policy := auth.NewPolicy(auth.DefaultRolePermissions, slog.New(slog.DiscardHandler))
+	service := NewUserStreamService(mockRepository, broker, policy, UserStreamOptions{}, slog.New(slog.DiscardHandler))
+	viewer := tenant.WithTenant(contextWithPrincipal("2", "viewer"), "acme")
+
+	ctx := tenant.WithTenant(context.Background(), "acme")
+	_, err := service.Open(nil, nil, &ctx)
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+
+	_, err = service.Open(nil, []string{model.UserCreatedEvent, "user.renamed"}, &viewer)
+	assert.EqualError(t, err, `invalid request: unknown event type "user.renamed", use user.created, user.updated, user.deleted`)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+
+	ctx = contextWithPrincipal("2", "viewer")
+	_, err = service.Open(nil, nil, &ctx)
+	assert.ErrorIs(t, err, tenant.ErrMissingTenant)
+
+	failure := errors.New("connection refused")
+	mockRepository.EXPECT().OldestID(mock.Anything).Return(0, failure)
+	_, err = service.Open(lastEventID(5), nil, &viewer)
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 0, broker.Len(), "the subscription is closed when the stream cannot be opened")
+
+	broker.Close()
+	_, err = service.Open(nil, nil, &viewer)
+	assert.ErrorIs(t, err, stream.ErrClosed)
+}
+
+func TestUnitUserStreamServiceTrimAll(t *testing.T) {
+	t.Parallel()
+
+	mockRepository := mocks.NewMockIUserChangeRepository(t)
+	tenants := func(context.Context) ([]string, error) {
+		return []string{"acme", "globex"}, nil
+	}
+	service := NewUserStreamService(mockRepository, stream.NewBroker(0), nil, UserStreamOptions{LogSize: 100, Tenants: tenants},
+		slog.New(slog.DiscardHandler))
+	trimmed := make([]string, 0)
+	mockRepository.EXPECT().Trim(100, mock.Anything).
+		RunAndReturn(func(_ int, ctx *context.Context) (int64, error) {
+			tenantID, _ := tenant.FromContext(*ctx)
+			trimmed = append(trimmed, tenantID)
+			if tenantID == "acme" {
+				return 0, errors.New("connection refused")
+			}
+			return 4, nil
+		})
+
+	service.TrimAll(context.Background())
+	assert.Equal(t, []string{"acme", "globex"}, trimmed, "a failing tenant does not keep the others from being trimmed")
+}
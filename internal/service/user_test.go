@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+func TestUnitUserServiceRecordsEvents(t *testing.T) {
+	t.Parallel()
+
+	mockUsers := mocks.NewMockIUserRepository(t)
+	mockOutbox := mocks.NewMockIOutboxRepository(t)
+	transactions := make([]error, 0)
+	service := NewUserService(mockUsers, mockOutbox, newTestTransactor(t, &transactions), slog.New(slog.DiscardHandler))
+	ctx := context.Background()
+
+	user := &model.UserModel{ID: 5, Name: "Ada", Email: "ada@example.com", Age: 36}
+	mockUsers.EXPECT().Create(&model.UserModel{Name: "Ada", Email: "ada@example.com", Age: 36}, mock.Anything).Return(user, nil)
+	mockUsers.EXPECT().Delete(5, mock.Anything).Return(user, nil)
+	events := make([]*model.OutboxEventModel, 0)
+	mockOutbox.EXPECT().
+		Append(mock.Anything, mock.Anything).
+		RunAndReturn(func(event *model.OutboxEventModel, _ *context.Context) (*model.OutboxEventModel, error) {
+			events = append(events, event)
+			return event, nil
+		})
+
+	created, err := service.Create(&model.CreateUserRequest{Name: "Ada", Email: "ada@example.com", Age: 36}, &ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5, created.ID)
+	_, err = service.Delete(5, &ctx)
+	require.NoError(t, err)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, []error{nil, nil}, transactions, "every change runs in a transaction of its own")
+	assert.Equal(t, model.UserCreatedEvent, events[0].Type)
+	assert.Equal(t, model.UserDeletedEvent, events[1].Type)
+	assert.Equal(t, model.UserAggregate, events[0].AggregateType)
+	assert.Equal(t, "5", events[0].AggregateID)
+	var payload model.UserResponse
+	require.NoError(t, json.Unmarshal(events[0].Payload, &payload))
+	assert.Equal(t, *created, payload)
+}
+
+func TestUnitUserServiceEventFailureRollsBack(t *testing.T) {
+	t.Parallel()
+
+	mockUsers := mocks.NewMockIUserRepository(t)
+	mockOutbox := mocks.NewMockIOutboxRepository(t)
+	transactions := make([]error, 0)
+	service := NewUserService(mockUsers, mockOutbox, newTestTransactor(t, &transactions), slog.New(slog.DiscardHandler))
+	ctx := context.Background()
+
+	mockUsers.EXPECT().Update(mock.Anything, mock.Anything).Return(&model.UserModel{ID: 5, Name: "Ada"}, nil)
+	mockOutbox.EXPECT().Append(mock.Anything, mock.Anything).Return(nil, errors.New("connection reset"))
+
+	_, err := service.Update(&model.UpdateUserRequest{Id: 5, Name: "Ada"}, &ctx)
+	assert.EqualError(t, err, "connection reset")
+	require.Len(t, transactions, 1)
+	assert.Error(t, transactions[0], "the transaction of the change is rolled back")
+}
+
+func TestUnitUserServiceWithoutOutbox(t *testing.T) {
+	t.Parallel()
+
+	mockUsers := mocks.NewMockIUserRepository(t)
+	service := NewUserService(mockUsers, nil, mocks.NewMockITransactor(t), slog.New(slog.DiscardHandler))
+	ctx := context.Background()
+
+	mockUsers.EXPECT().Create(mock.Anything, mock.Anything).Return(&model.UserModel{ID: 5}, nil)
+	created, err := service.Create(&model.CreateUserRequest{Name: "Ada"}, &ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5, created.ID)
+}
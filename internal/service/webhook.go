@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"crud/internal/webhook"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const webhookResource = "webhook"
+
+// MinWebhookSecretLength is the length a secret needs at least, shorter secrets are guessable from signed deliveries
+const MinWebhookSecretLength = 16
+
+var webhookPermissions = ResourcePermissions{
+	Resource: webhookResource,
+	Read:     auth.PermissionWebhooksRead,
+	Write:    auth.PermissionWebhooksWrite,
+}
+
+// IWebhookService manages the webhook subscriptions of a tenant and the log of their deliveries
+type IWebhookService interface {
+	IService[int, model.WebhookSubscriptionRequest, model.WebhookSubscriptionResponse]
+	GetDeliveries(subscriptionID int, offset int, limit int, ctx *context.Context) ([]*model.WebhookDeliveryResponse, error)
+	// ReplayDelivery sends a delivery again, whatever its status
+	ReplayDelivery(subscriptionID int, deliveryID int64, ctx *context.Context) (*model.WebhookDeliveryResponse, error)
+}
+
+type WebhookService struct {
+	IService[int, model.WebhookSubscriptionRequest, model.WebhookSubscriptionResponse]
+	deliveryRepository repository.IWebhookDeliveryRepository
+	// policy is nil when authorization is disabled
+	policy *auth.Policy
+}
+
+// NewWebhookService creates the service, targets rejects the subscription URLs in the networks of the application.
+// A guard allowing none of them is used when it is nil
+func NewWebhookService(subscriptionRepository repository.IWebhookSubscriptionRepository, deliveryRepository repository.IWebhookDeliveryRepository,
+	targets *webhook.TargetGuard, policy *auth.Policy) IWebhookService {
+	if targets == nil {
+		targets = &webhook.TargetGuard{}
+	}
+	service := &WebhookService{deliveryRepository: deliveryRepository, policy: policy}
+	service.IService = NewService[model.WebhookSubscriptionModel, int, model.WebhookSubscriptionRequest, model.WebhookSubscriptionResponse](subscriptionRepository, Hooks[model.WebhookSubscriptionModel, int, model.WebhookSubscriptionRequest, model.WebhookSubscriptionResponse]{
+		ToModel:    model.WebhookSubscriptionRequestToWebhookSubscriptionModel,
+		ToResponse: model.WebhookSubscriptionModelToWebhookSubscriptionResponse,
+		Validate: func(request *model.WebhookSubscriptionRequest, ctx context.Context) error {
+			return validateWebhookSubscriptionRequest(request, targets, ctx)
+		},
+	})
+	if policy != nil {
+		service.IService = NewAuthorizedService(service.IService, policy, webhookPermissions)
+	}
+	return service
+}
+
+func validateWebhookSubscriptionRequest(request *model.WebhookSubscriptionRequest, targets *webhook.TargetGuard, ctx context.Context) error {
+	subscriptionURL, err := url.Parse(request.URL)
+	if err != nil || (subscriptionURL.Scheme != "http" && subscriptionURL.Scheme != "https") || subscriptionURL.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	if err = targets.CheckURL(ctx, request.URL); err != nil {
+		return err
+	}
+	if len(request.EventTypes) == 0 {
+		return errors.New("event_types is required")
+	}
+	for _, eventType := range request.EventTypes {
+		if eventType != model.WebhookAllEvents && !slices.Contains(model.EventTypes, eventType) {
+			return fmt.Errorf("unknown event type %q, use %s or %s", eventType, strings.Join(model.EventTypes, ", "),
+				model.WebhookAllEvents)
+		}
+	}
+	if len(request.Secret) < MinWebhookSecretLength {
+		return fmt.Errorf("secret must have at least %d characters", MinWebhookSecretLength)
+	}
+	return nil
+}
+
+func (service *WebhookService) GetDeliveries(subscriptionID int, offset int, limit int, ctx *context.Context) ([]*model.WebhookDeliveryResponse, error) {
+	if err := service.authorize(ctx, auth.PermissionWebhooksRead, subscriptionID); err != nil {
+		return nil, err
+	}
+	if err := validatePage(offset, limit, DefaultMaxLimit); err != nil {
+		return nil, err
+	}
+	deliveries, err := service.deliveryRepository.GetBySubscription(subscriptionID, offset, limit, ctx)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*model.WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = model.WebhookDeliveryModelToWebhookDeliveryResponse(delivery)
+	}
+	return responses, nil
+}
+
+func (service *WebhookService) ReplayDelivery(subscriptionID int, deliveryID int64, ctx *context.Context) (*model.WebhookDeliveryResponse, error) {
+	if err := service.authorize(ctx, auth.PermissionWebhooksWrite, subscriptionID); err != nil {
+		return nil, err
+	}
+	delivery, err := service.deliveryRepository.Replay(subscriptionID, deliveryID, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.WebhookDeliveryModelToWebhookDeliveryResponse(delivery), nil
+}
+
+func (service *WebhookService) authorize(ctx *context.Context, permission string, subscriptionID int) error {
+	if service.policy == nil {
+		return nil
+	}
+	return service.policy.Authorize(*ctx, permission, webhookResource, subscriptionID)
+}
+
+// WebhookFanOut is the outbox sink of the webhooks, it turns every event into a delivery per matching subscription.
+// It runs in the transaction of the relay, the deliveries are written exactly once per event and subscription
+type WebhookFanOut struct {
+	deliveryRepository repository.IWebhookDeliveryRepository
+}
+
+func NewWebhookFanOut(deliveryRepository repository.IWebhookDeliveryRepository) *WebhookFanOut {
+	return &WebhookFanOut{deliveryRepository: deliveryRepository}
+}
+
+func (fanOut *WebhookFanOut) Publish(ctx context.Context, event *model.EventMessage) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fanOut.deliveryRepository.Enqueue(event, payload, &ctx)
+	return err
+}
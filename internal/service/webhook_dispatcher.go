@@ -0,0 +1,210 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crud/internal/model"
+	"crud/internal/repository"
+	"crud/internal/util/request"
+	"crud/internal/webhook"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultWebhookPollInterval = time.Second
+	DefaultWebhookBatchSize    = 20
+	// DefaultWebhookMaxAttempts is the number of requests after which a failing delivery is dead
+	DefaultWebhookMaxAttempts = 10
+	DefaultWebhookTimeout     = 10 * time.Second
+
+	webhookMinBackoff = 10 * time.Second
+	webhookMaxBackoff = time.Hour
+)
+
+// WebhookDispatcherOptions configures the dispatcher, zero values fall back to the defaults
+type WebhookDispatcherOptions struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	// Timeout bounds a request, deliveries stay claimed for twice as long
+	Timeout time.Duration
+	// Targets rejects the addresses in the networks of the application, a guard allowing none of them when it is nil
+	Targets *webhook.TargetGuard
+	// Client sends the requests. When it is nil a client with Timeout which does not follow redirects and only
+	// connects to the addresses Targets allows
+	Client *http.Client
+	// Tenants lists the tenants whose deliveries are sent in the schema per tenant mode. When it is nil
+	// the deliveries are shared by all tenants
+	Tenants func(ctx context.Context) ([]string, error)
+}
+
+// WebhookDispatcher sends the pending webhook deliveries, signed with the secret of their subscription. A delivery
+// answered with a status other than 2xx is retried with an exponential backoff until it failed MaxAttempts times,
+// then it is dead until it is replayed. Replicas claim distinct deliveries, their order is not guaranteed
+type WebhookDispatcher struct {
+	deliveryRepository repository.IWebhookDeliveryRepository
+	options            WebhookDispatcherOptions
+	logger             *slog.Logger
+}
+
+func NewWebhookDispatcher(deliveryRepository repository.IWebhookDeliveryRepository, options WebhookDispatcherOptions,
+	logger *slog.Logger) *WebhookDispatcher {
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultWebhookPollInterval
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultWebhookBatchSize
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultWebhookMaxAttempts
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultWebhookTimeout
+	}
+	if options.Targets == nil {
+		options.Targets = &webhook.TargetGuard{}
+	}
+	if options.Client == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		// The addresses are checked again when connecting, names may resolve to other addresses than when the
+		// subscription was written. A proxy would connect on behalf of the dispatcher, past the check
+		transport.DialContext = (&net.Dialer{Timeout: options.Timeout, Control: options.Targets.Control}).DialContext
+		transport.Proxy = nil
+		options.Client = &http.Client{
+			Timeout:   options.Timeout,
+			Transport: transport,
+			// A redirect fails the delivery, following it would turn the POST into a GET
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+	return &WebhookDispatcher{deliveryRepository: deliveryRepository, options: options, logger: logger}
+}
+
+// Run sends the due deliveries every poll interval until ctx is done
+func (dispatcher *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.options.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			dispatcher.DispatchAll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// DispatchAll sends the due deliveries of every tenant
+func (dispatcher *WebhookDispatcher) DispatchAll(ctx context.Context) {
+	contexts, err := tenantContexts(ctx, dispatcher.options.Tenants)
+	if err != nil {
+		dispatcher.logger.Warn("failed to list the tenants of the webhooks", slog.String("error", err.Error()))
+		return
+	}
+	for _, tenantCtx := range contexts {
+		if err = dispatcher.drain(tenantCtx); err != nil && !errors.Is(err, context.Canceled) {
+			dispatcher.logger.Warn("failed to dispatch webhook deliveries", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// drain sends batches until fewer deliveries than the batch size are due, the deliveries of a batch are sent concurrently
+func (dispatcher *WebhookDispatcher) drain(ctx context.Context) error {
+	for ctx.Err() == nil {
+		leaseUntil := time.Now().Add(2 * dispatcher.options.Timeout)
+		deliveries, err := dispatcher.deliveryRepository.ClaimDue(dispatcher.options.BatchSize, leaseUntil, &ctx)
+		if err != nil {
+			return err
+		}
+		var wait sync.WaitGroup
+		errs := make([]error, len(deliveries))
+		for i, delivery := range deliveries {
+			wait.Add(1)
+			go func() {
+				defer wait.Done()
+				errs[i] = dispatcher.dispatch(ctx, delivery)
+			}()
+		}
+		wait.Wait()
+		if err = errors.Join(errs...); err != nil || len(deliveries) < dispatcher.options.BatchSize {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// dispatch sends a delivery and records the outcome. A delivery interrupted by ctx is left claimed and sent again
+// once its claim expires
+func (dispatcher *WebhookDispatcher) dispatch(ctx context.Context, delivery *model.WebhookDeliveryModel) error {
+	statusCode, err := dispatcher.send(ctx, delivery)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err == nil {
+		dispatcher.logger.Debug("delivered webhook",
+			slog.Int64("delivery_id", delivery.ID),
+			slog.String("event_type", delivery.EventType),
+			slog.Int("status", statusCode))
+		return dispatcher.deliveryRepository.MarkSucceeded(delivery.ID, statusCode, &ctx)
+	}
+
+	var lastStatusCode *int
+	if statusCode != 0 {
+		lastStatusCode = &statusCode
+	}
+	attempts := delivery.Attempts + 1
+	attrs := []any{
+		slog.Int64("delivery_id", delivery.ID),
+		slog.Int("subscription_id", delivery.SubscriptionID),
+		slog.String("event_type", delivery.EventType),
+		slog.Int("attempts", attempts),
+		slog.String("error", err.Error()),
+	}
+	if attempts >= dispatcher.options.MaxAttempts {
+		dispatcher.logger.Warn("webhook delivery is dead", attrs...)
+		return dispatcher.deliveryRepository.MarkDead(delivery.ID, lastStatusCode, err.Error(), &ctx)
+	}
+	nextAttemptAt := time.Now().Add(webhookBackoff(attempts))
+	dispatcher.logger.Info("failed to deliver webhook", append(attrs, slog.Time("next_attempt_at", nextAttemptAt))...)
+	return dispatcher.deliveryRepository.MarkFailed(delivery.ID, lastStatusCode, err.Error(), nextAttemptAt, &ctx)
+}
+
+// send posts the payload of the delivery, it returns the status code of the response when there is one
+func (dispatcher *WebhookDispatcher) send(ctx context.Context, delivery *model.WebhookDeliveryModel) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.HeaderID, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(webhook.HeaderEvent, delivery.EventType)
+	if delivery.RequestID != nil {
+		req.Header.Set(request.HeaderRequestID, *delivery.RequestID)
+	}
+	webhook.SetHeaders(req.Header, []byte(delivery.Secret), delivery.Payload, time.Now())
+	resp, err := dispatcher.options.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookBackoff doubles the delay with every failed attempt, up to webhookMaxBackoff
+func webhookBackoff(attempts int) time.Duration {
+	return exponentialBackoff(attempts, webhookMinBackoff, webhookMaxBackoff)
+}
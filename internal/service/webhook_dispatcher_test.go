@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"crud/internal/tenant"
+	"crud/internal/util/request"
+	"crud/internal/webhook"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookReceiver answers the deliveries with the given statuses in turn and records the requests whose signature
+// is valid for secret
+type webhookReceiver struct {
+	mutex    sync.Mutex
+	secret   []byte
+	statuses []int
+	received []*http.Request
+	bodies   [][]byte
+}
+
+func (receiver *webhookReceiver) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	body, _ := io.ReadAll(req.Body)
+	if webhook.Verify(req.Header, receiver.secret, body, time.Now(), webhook.DefaultTolerance) != nil {
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	receiver.received = append(receiver.received, req)
+	receiver.bodies = append(receiver.bodies, body)
+	status := http.StatusNoContent
+	if len(receiver.statuses) > 0 {
+		status, receiver.statuses = receiver.statuses[0], receiver.statuses[1:]
+	}
+	writer.WriteHeader(status)
+}
+
+// loopbackTargets lets the dispatcher reach the httptest servers on the loopback interface
+func loopbackTargets(t *testing.T) *webhook.TargetGuard {
+	targets, err := webhook.NewTargetGuard([]string{"127.0.0.0/8", "::1"}, nil)
+	require.NoError(t, err)
+	return targets
+}
+
+func webhookDelivery(id int64, url string, attempts int) *model.WebhookDeliveryModel {
+	payload, _ := json.Marshal(&model.EventMessage{ID: id, TenantID: "acme", Type: model.UserCreatedEvent})
+	return &model.WebhookDeliveryModel{
+		ID:             id,
+		TenantID:       "acme",
+		SubscriptionID: 3,
+		EventID:        id,
+		EventType:      model.UserCreatedEvent,
+		Payload:        payload,
+		Status:         model.WebhookDeliveryPending,
+		Attempts:       attempts,
+		URL:            url,
+		Secret:         "0123456789abcdef",
+	}
+}
+
+func TestUnitWebhookDispatcherSignsDeliveries(t *testing.T) {
+	t.Parallel()
+
+	receiver := &webhookReceiver{secret: []byte("0123456789abcdef")}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	mockDeliveries := mocks.NewMockIWebhookDeliveryRepository(t)
+	dispatcher := NewWebhookDispatcher(mockDeliveries, WebhookDispatcherOptions{Targets: loopbackTargets(t)}, slog.New(slog.DiscardHandler))
+
+	delivery := webhookDelivery(7, server.URL, 0)
+	requestID := "req-1"
+	delivery.RequestID = &requestID
+	mockDeliveries.EXPECT().ClaimDue(DefaultWebhookBatchSize, mock.Anything, mock.Anything).
+		Return([]*model.WebhookDeliveryModel{delivery}, nil).Once()
+	mockDeliveries.EXPECT().MarkSucceeded(int64(7), http.StatusNoContent, mock.Anything).Return(nil)
+	dispatcher.DispatchAll(context.Background())
+
+	require.Len(t, receiver.received, 1, "the signature is valid")
+	assert.Equal(t, "7", receiver.received[0].Header.Get(webhook.HeaderID))
+	assert.Equal(t, model.UserCreatedEvent, receiver.received[0].Header.Get(webhook.HeaderEvent))
+	assert.Equal(t, "application/json", receiver.received[0].Header.Get("Content-Type"))
+	assert.Equal(t, "req-1", receiver.received[0].Header.Get(request.HeaderRequestID))
+	assert.JSONEq(t, string(delivery.Payload), string(receiver.bodies[0]))
+}
+
+func TestUnitWebhookDispatcherRetriesFailedDeliveries(t *testing.T) {
+	t.Parallel()
+
+	receiver := &webhookReceiver{secret: []byte("0123456789abcdef"), statuses: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	mockDeliveries := mocks.NewMockIWebhookDeliveryRepository(t)
+	dispatcher := NewWebhookDispatcher(mockDeliveries, WebhookDispatcherOptions{Targets: loopbackTargets(t)}, slog.New(slog.DiscardHandler))
+
+	mockDeliveries.EXPECT().ClaimDue(DefaultWebhookBatchSize, mock.Anything, mock.Anything).
+		Return([]*model.WebhookDeliveryModel{webhookDelivery(7, server.URL, 2)}, nil).Once()
+	var statusCode *int
+	var nextAttemptAt time.Time
+	mockDeliveries.EXPECT().
+		MarkFailed(int64(7), mock.Anything, "webhook responded 503 Service Unavailable", mock.Anything, mock.Anything).
+		RunAndReturn(func(_ int64, status *int, _ string, next time.Time, _ *context.Context) error {
+			statusCode, nextAttemptAt = status, next
+			return nil
+		})
+	dispatcher.DispatchAll(context.Background())
+
+	require.NotNil(t, statusCode)
+	assert.Equal(t, http.StatusServiceUnavailable, *statusCode)
+	assert.WithinDuration(t, time.Now().Add(40*time.Second), nextAttemptAt, time.Second, "the fourth attempt waits 40s")
+}
+
+func TestUnitWebhookDispatcherDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	receiver := &webhookReceiver{secret: []byte("another secret")}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	mockDeliveries := mocks.NewMockIWebhookDeliveryRepository(t)
+	dispatcher := NewWebhookDispatcher(mockDeliveries, WebhookDispatcherOptions{MaxAttempts: 3, Targets: loopbackTargets(t)}, slog.New(slog.DiscardHandler))
+
+	mockDeliveries.EXPECT().ClaimDue(DefaultWebhookBatchSize, mock.Anything, mock.Anything).
+		Return([]*model.WebhookDeliveryModel{webhookDelivery(7, server.URL, 2), webhookDelivery(8, unreachable.URL, 2)}, nil).Once()
+	mockDeliveries.EXPECT().
+		MarkDead(int64(7), mock.Anything, "webhook responded 401 Unauthorized", mock.Anything).
+		RunAndReturn(func(_ int64, status *int, _ string, _ *context.Context) error {
+			assert.Equal(t, http.StatusUnauthorized, *status)
+			return nil
+		})
+	mockDeliveries.EXPECT().
+		MarkDead(int64(8), (*int)(nil), mock.Anything, mock.Anything).
+		Return(nil)
+	dispatcher.DispatchAll(context.Background())
+
+	assert.Empty(t, receiver.received, "deliveries signed with another secret are rejected")
+}
+
+func TestUnitWebhookDispatcherDoesNotFollowRedirects(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.RedirectHandler("/elsewhere", http.StatusFound))
+	defer server.Close()
+	mockDeliveries := mocks.NewMockIWebhookDeliveryRepository(t)
+	dispatcher := NewWebhookDispatcher(mockDeliveries, WebhookDispatcherOptions{Targets: loopbackTargets(t)}, slog.New(slog.DiscardHandler))
+
+	mockDeliveries.EXPECT().ClaimDue(DefaultWebhookBatchSize, mock.Anything, mock.Anything).
+		Return([]*model.WebhookDeliveryModel{webhookDelivery(7, server.URL, 0)}, nil).Once()
+	mockDeliveries.EXPECT().
+		MarkFailed(int64(7), mock.Anything, "webhook responded 302 Found", mock.Anything, mock.Anything).
+		Return(nil)
+	dispatcher.DispatchAll(context.Background())
+}
+
+func TestUnitWebhookDispatcherRefusesInternalTargets(t *testing.T) {
+	t.Parallel()
+
+	receiver := &webhookReceiver{secret: []byte("0123456789abcdef")}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	mockDeliveries := mocks.NewMockIWebhookDeliveryRepository(t)
+	dispatcher := NewWebhookDispatcher(mockDeliveries, WebhookDispatcherOptions{}, slog.New(slog.DiscardHandler))
+
+	mockDeliveries.EXPECT().ClaimDue(DefaultWebhookBatchSize, mock.Anything, mock.Anything).
+		Return([]*model.WebhookDeliveryModel{webhookDelivery(7, server.URL, 0)}, nil).Once()
+	mockDeliveries.EXPECT().
+		MarkFailed(int64(7), (*int)(nil), mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ int64, _ *int, lastError string, _ time.Time, _ *context.Context) error {
+			assert.Contains(t, lastError, webhook.ErrForbiddenTarget.Error())
+			return nil
+		})
+	dispatcher.DispatchAll(context.Background())
+
+	assert.Empty(t, receiver.received, "the dispatcher does not connect to the loopback interface")
+}
+
+func TestUnitWebhookDispatcherTenants(t *testing.T) {
+	t.Parallel()
+
+	mockDeliveries := mocks.NewMockIWebhookDeliveryRepository(t)
+	dispatcher := NewWebhookDispatcher(mockDeliveries, WebhookDispatcherOptions{
+		BatchSize: 1,
+		Tenants: func(context.Context) ([]string, error) {
+			return []string{tenant.DefaultTenant, "acme"}, nil
+		},
+	}, slog.New(slog.DiscardHandler))
+	claimed := make([]string, 0)
+	mockDeliveries.EXPECT().
+		ClaimDue(1, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ int, leaseUntil time.Time, ctx *context.Context) ([]*model.WebhookDeliveryModel, error) {
+			assert.WithinDuration(t, time.Now().Add(2*DefaultWebhookTimeout), leaseUntil, time.Second)
+			tenantID, _ := tenant.FromContext(*ctx)
+			claimed = append(claimed, tenantID)
+			return []*model.WebhookDeliveryModel{}, nil
+		})
+
+	dispatcher.DispatchAll(context.Background())
+	assert.Equal(t, []string{tenant.DefaultTenant, "acme"}, claimed, "the deliveries of every tenant schema are sent")
+}
+
+func TestUnitWebhookBackoff(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 10*time.Second, webhookBackoff(1))
+	assert.Equal(t, 80*time.Second, webhookBackoff(4))
+	assert.Equal(t, time.Hour, webhookBackoff(20))
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"crud/internal/auth"
+	"crud/internal/mocks"
+	"crud/internal/model"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+func TestUnitWebhookServiceValidation(t *testing.T) {
+	t.Parallel()
+
+	valid := model.WebhookSubscriptionRequest{
+		URL:        "https://203.0.113.10/users",
+		EventTypes: []string{model.UserCreatedEvent, model.UserDeletedEvent},
+		Secret:     "0123456789abcdef",
+	}
+	tests := []struct {
+		name    string
+		modify  func(request *model.WebhookSubscriptionRequest)
+		message string
+	}{
+		{name: "valid", modify: func(*model.WebhookSubscriptionRequest) {}},
+		{name: "all events", modify: func(request *model.WebhookSubscriptionRequest) {
+			request.EventTypes = []string{model.WebhookAllEvents}
+		}},
+		{name: "relative url", modify: func(request *model.WebhookSubscriptionRequest) {
+			request.URL = "/users"
+		}, message: "invalid request: url must be an absolute http or https URL"},
+		{name: "other scheme", modify: func(request *model.WebhookSubscriptionRequest) {
+			request.URL = "ftp://hooks.example.com/users"
+		}, message: "invalid request: url must be an absolute http or https URL"},
+		{name: "loopback url", modify: func(request *model.WebhookSubscriptionRequest) {
+			request.URL = "http://127.0.0.1:8080/users"
+		}, message: "invalid request: webhook target is not allowed: 127.0.0.1 is an internal address"},
+		{name: "private network url", modify: func(request *model.WebhookSubscriptionRequest) {
+			request.URL = "http://10.20.30.40/users"
+		}, message: "invalid request: webhook target is not allowed: 10.20.30.40 is an internal address"},
+		{name: "metadata endpoint url", modify: func(request *model.WebhookSubscriptionRequest) {
+			request.URL = "http://169.254.169.254/latest/meta-data/"
+		}, message: "invalid request: webhook target is not allowed: 169.254.169.254 is an internal address"},
+		{name: "no event types", modify: func(request *model.WebhookSubscriptionRequest) {
+			request.EventTypes = []string{}
+		}, message: "invalid request: event_types is required"},
+		{name: "unknown event type", modify: func(request *model.WebhookSubscriptionRequest) {
+			request.EventTypes = []string{"user.renamed"}
+		}, message: `invalid request: unknown event type "user.renamed", use user.created, user.updated, user.deleted or *`},
+		{name: "short secret", modify: func(request *model.WebhookSubscriptionRequest) {
+			request.Secret = "secret"
+		}, message: "invalid request: secret must have at least 16 characters"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			mockSubscriptions := mocks.NewMockIWebhookSubscriptionRepository(t)
+			service := NewWebhookService(mockSubscriptions, mocks.NewMockIWebhookDeliveryRepository(t), nil, nil)
+			request := valid
+			test.modify(&request)
+			if test.message == "" {
+				mockSubscriptions.EXPECT().Create(mock.Anything, mock.Anything).
+					RunAndReturn(func(subscription *model.WebhookSubscriptionModel, _ *context.Context) (*model.WebhookSubscriptionModel, error) {
+						subscription.ID = 3
+						return subscription, nil
+					})
+			}
+
+			ctx := context.Background()
+			created, err := service.Create(&request, &ctx)
+			if test.message != "" {
+				assert.EqualError(t, err, test.message)
+				assert.ErrorIs(t, err, ErrInvalidRequest)
+				return
+			}
+			require.NoError(t, err)
+			response, err := json.Marshal(created)
+			require.NoError(t, err)
+			assert.NotContains(t, string(response), request.Secret, "the secret is never returned")
+		})
+	}
+}
+
+func TestUnitWebhookServiceDeliveries(t *testing.T) {
+	t.Parallel()
+
+	mockDeliveries := mocks.NewMockIWebhookDeliveryRepository(t)
+	policy := auth.NewPolicy(auth.DefaultRolePermissions, slog.New(slog.DiscardHandler))
+	service := NewWebhookService(mocks.NewMockIWebhookSubscriptionRepository(t), mockDeliveries, nil, policy)
+	admin := contextWithPrincipal("1", "admin")
+	viewer := contextWithPrincipal("2", "viewer")
+
+	mockDeliveries.EXPECT().GetBySubscription(3, 0, 10, mock.Anything).
+		Return([]*model.WebhookDeliveryModel{
+			{ID: 8, SubscriptionID: 3, Status: model.WebhookDeliveryDead, Attempts: 10},
+			{ID: 7, SubscriptionID: 3, Status: model.WebhookDeliveryPending, Attempts: 1},
+		}, nil)
+	deliveries, err := service.GetDeliveries(3, 0, 10, &admin)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Nil(t, deliveries[0].NextAttemptAt, "dead deliveries are not attempted again")
+	assert.NotNil(t, deliveries[1].NextAttemptAt)
+	_, err = service.GetDeliveries(3, 0, DefaultMaxLimit+1, &admin)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = service.GetDeliveries(3, 0, 10, &viewer)
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+
+	mockDeliveries.EXPECT().Replay(3, int64(8), mock.Anything).
+		Return(&model.WebhookDeliveryModel{ID: 8, SubscriptionID: 3, Status: model.WebhookDeliveryPending}, nil)
+	replayed, err := service.ReplayDelivery(3, 8, &admin)
+	require.NoError(t, err)
+	assert.Equal(t, model.WebhookDeliveryPending, replayed.Status)
+	_, err = service.ReplayDelivery(3, 8, &viewer)
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+}
+
+func TestUnitWebhookFanOut(t *testing.T) {
+	t.Parallel()
+
+	mockDeliveries := mocks.NewMockIWebhookDeliveryRepository(t)
+	fanOut := NewWebhookFanOut(mockDeliveries)
+	event := &model.EventMessage{ID: 7, TenantID: "acme", AggregateType: model.UserAggregate, AggregateID: "5",
+		Type: model.UserCreatedEvent, Payload: json.RawMessage(`{"id":5}`)}
+	mockDeliveries.EXPECT().
+		Enqueue(event, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ *model.EventMessage, payload []byte, _ *context.Context) (int64, error) {
+			assert.JSONEq(t, `{"id":7,"tenant_id":"acme","aggregate_type":"user","aggregate_id":"5","type":"user.created",
+				"payload":{"id":5},"occurred_at":"0001-01-01T00:00:00Z"}`, string(payload), "deliveries carry the event message")
+			return 2, nil
+		})
+
+	assert.NoError(t, fanOut.Publish(context.Background(), event))
+}
@@ -2,15 +2,64 @@ package internal
 
 import (
 	"crud/docs"
+	"crud/internal/auth"
+	"crud/internal/buildinfo"
 	"crud/internal/controller"
+	"crud/internal/middleware"
+	"crud/internal/querystats"
 	"crud/internal/repository"
 	"crud/internal/service"
+	"crud/internal/webhook"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"log/slog"
+	"net/http"
+	"time"
 )
 
+// RouterOptions carries the cross-cutting middlewares of the routes
+type RouterOptions struct {
+	// RequireAuth rejects anonymous callers, it is nil when authentication is disabled
+	RequireAuth      gin.HandlerFunc
+	AnonymousSwagger bool
+	// Reauthenticate validates the credentials of a request again, the WebSocket API checks that they were not
+	// revoked on every heartbeat. It is nil when authentication is disabled
+	Reauthenticate func(req *http.Request) (*auth.Principal, error)
+	// Policy authorizes callers of the services, it is nil when authentication is disabled
+	Policy  *auth.Policy
+	Account service.AccountOptions
+	// RequireAdmin guards the admin endpoints served under /admin, it is nil when neither authentication nor
+	// an admin token is configured and the admin endpoints are not served then
+	RequireAdmin []gin.HandlerFunc
+	// QueryStats is exposed on the admin endpoints
+	QueryStats         *querystats.Collector
+	ExplainSlowQueries bool
+	// Logger is injected into the components which log
+	Logger *slog.Logger
+	// LogLevel is changed at runtime on the admin endpoints
+	LogLevel       *slog.LevelVar
+	DebugLogSecret []byte
+	// RecordEvents writes the domain events of the services to the outbox
+	RecordEvents bool
+	// Webhooks serves the webhook subscriptions, their deliveries are written by the outbox relay. WebhookTargets
+	// rejects the subscription URLs in the networks of the application
+	Webhooks       bool
+	WebhookTargets *webhook.TargetGuard
+	// UserStream streams the user changes, StreamHeartbeatInterval is how often idle streams send a heartbeat
+	UserStream              service.IUserStreamService
+	StreamHeartbeatInterval time.Duration
+	// UserLive serves the live user queries of the WebSocket API, it shares the change feed of UserStream
+	UserLive             service.IUserLiveService
+	MaxLiveSubscriptions int
+	// AllowedOrigins are the origins browsers may call the user routes and open the WebSocket API from, all origins
+	// may call the user routes when it is empty
+	AllowedOrigins []string
+	// Jobs administers the background jobs on the admin endpoints, it is nil when the job worker is disabled
+	Jobs service.IJobService
+}
+
 // SetupRouter function to configure route and wire up dependencies
 // @title					 User Template Service
 // @version					 1.0
@@ -30,18 +79,94 @@ import (
 //
 // @externalDocs.description OpenAPI Swag Go
 // @externalDocs.url         https://github.com/swaggo/swag#general-api-info
-func SetupRouter(dbPool *pgxpool.Pool, app *gin.Engine) {
+//
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				JWT bearer token, e.g. "Bearer eyJhbGciOi...", or API key, e.g. "ApiKey crud_3f9a1c2b_..."
+func SetupRouter(dbPool *pgxpool.Pool, app *gin.Engine, options RouterOptions) {
 	v1Router := app.Group("/api/v1")
-	setupV1Router(dbPool, v1Router)
+	authenticatedV1Router := v1Router.Group("")
+	if options.RequireAuth != nil {
+		authenticatedV1Router.Use(options.RequireAuth)
+	}
+	setupV1Router(dbPool, v1Router, authenticatedV1Router, options)
+	if options.RequireAdmin != nil {
+		setupAdminRouter(dbPool, app.Group("", options.RequireAdmin...), options)
+	}
 
 	docs.SwaggerInfo.Title = "Swagger Example API"
 	docs.SwaggerInfo.BasePath = "/api/v1"
-	app.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+	docs.SwaggerInfo.Version = buildinfo.Get().Version
+	swaggerHandlers := make([]gin.HandlerFunc, 0)
+	if options.RequireAuth != nil && !options.AnonymousSwagger {
+		swaggerHandlers = append(swaggerHandlers, options.RequireAuth)
+	}
+	swaggerHandlers = append(swaggerHandlers, ginSwagger.WrapHandler(swaggerfiles.Handler))
+	app.GET("/swagger/*any", swaggerHandlers...)
 }
 
-func setupV1Router(dbPool *pgxpool.Pool, router *gin.RouterGroup) {
-	userRepository := repository.NewUserRepository(dbPool)
-	userService := service.NewUserService(userRepository)
-	userController := controller.NewUserController(userService)
+func setupV1Router(dbPool *pgxpool.Pool, publicRouter *gin.RouterGroup, router *gin.RouterGroup, options RouterOptions) {
+	transactor := repository.NewTransactor(dbPool)
+	var outboxRepository repository.IOutboxRepository
+	if options.RecordEvents {
+		outboxRepository = repository.NewOutboxRepository(dbPool)
+	}
+	userRepository := repository.NewUserRepository(dbPool, options.Logger)
+	userService := service.NewUserService(userRepository, outboxRepository, transactor, options.Logger)
+	if options.Policy != nil {
+		userService = service.NewAuthorizedUserService(userService, options.Policy)
+	}
+	userController := controller.NewUserController(userService, options.AllowedOrigins)
 	userController.SetupRoutes(router)
+	if options.UserStream != nil {
+		userStreamController := controller.NewUserStreamController(options.UserStream, options.StreamHeartbeatInterval,
+			options.AllowedOrigins, options.Logger)
+		userStreamController.SetupRoutes(router)
+	}
+	if options.UserLive != nil {
+		userSocketController := controller.NewUserSocketController(options.UserLive, options.StreamHeartbeatInterval,
+			options.MaxLiveSubscriptions, options.AllowedOrigins, options.Reauthenticate, options.Logger)
+		userSocketController.SetupRoutes(router)
+	}
+
+	organizationService := service.NewOrganizationService(repository.NewOrganizationRepository(dbPool),
+		repository.NewMembershipRepository(dbPool), transactor, options.Policy)
+	organizationController := controller.NewOrganizationController(organizationService)
+	organizationController.SetupRoutes(router)
+
+	if options.Webhooks {
+		webhookService := service.NewWebhookService(repository.NewWebhookSubscriptionRepository(dbPool),
+			repository.NewWebhookDeliveryRepository(dbPool), options.WebhookTargets, options.Policy)
+		webhookController := controller.NewWebhookController(webhookService)
+		webhookController.SetupRoutes(router)
+	}
+
+	// gen:resources - resources scaffolded by "go run ./cmd/gen resource" are registered above this line
+
+	if options.Policy != nil {
+		apiKeyRepository := repository.NewAPIKeyRepository(dbPool)
+		apiKeyService := service.NewAPIKeyService(apiKeyRepository, options.Policy)
+		apiKeyController := controller.NewAPIKeyController(apiKeyService)
+		apiKeyController.SetupRoutes(router, middleware.RequirePermissionMiddleware(options.Policy, auth.PermissionAPIKeysAdmin))
+
+		credentialRepository := repository.NewCredentialRepository(dbPool)
+		sessionRepository := repository.NewSessionRepository(dbPool)
+		accountService := service.NewAccountService(credentialRepository, sessionRepository, options.Account)
+		accountController := controller.NewAccountController(accountService)
+		accountController.SetupRoutes(publicRouter, router, middleware.RequirePermissionMiddleware(options.Policy, auth.PermissionUsersWrite))
+	}
+}
+
+// setupAdminRouter serves the operational endpoints under /admin, outside of the versioned API
+func setupAdminRouter(dbPool *pgxpool.Pool, router *gin.RouterGroup, options RouterOptions) {
+	queryPlanRepository := repository.NewQueryPlanRepository(dbPool)
+	queryStatsService := service.NewQueryStatsService(options.QueryStats, queryPlanRepository, options.ExplainSlowQueries)
+	logLevelService := service.NewLogLevelService(options.LogLevel, options.DebugLogSecret, middleware.DebugLogHeader, options.Logger)
+	adminController := controller.NewAdminController(queryStatsService, logLevelService)
+	adminController.SetupRoutes(router)
+	if options.Jobs != nil {
+		jobController := controller.NewJobController(options.Jobs)
+		jobController.SetupRoutes(router)
+	}
 }
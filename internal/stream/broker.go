@@ -0,0 +1,161 @@
+package stream
+
+import (
+	"crud/internal/model"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// DefaultBuffer is how many events a subscriber may lag behind before it is dropped
+const DefaultBuffer = 64
+
+var (
+	// ErrSlowConsumer ends the subscriptions which did not keep up with the events, their clients resume from the log
+	ErrSlowConsumer = errors.New("subscriber too slow, resume from the last event id")
+	// ErrResync ends the subscriptions when events may have been missed, e.g. while the listener reconnected
+	ErrResync = errors.New("events may have been missed, resume from the last event id")
+	ErrClosed = errors.New("stream closed")
+)
+
+// Broker fans the events out to the subscribers of their tenant. Publishing never blocks, a subscriber whose
+// buffer is full is dropped with ErrSlowConsumer
+type Broker struct {
+	mutex       sync.Mutex
+	buffer      int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+func NewBroker(buffer int) *Broker {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	return &Broker{buffer: buffer, subscribers: make(map[*Subscription]struct{})}
+}
+
+// Subscription receives the events of a tenant, of the given types or of all types when there are none
+type Subscription struct {
+	broker   *Broker
+	tenantID string
+	types    []string
+	events   chan *model.EventMessage
+	err      error
+}
+
+// Subscribe subscribes to the events of the tenant, the subscription has to be closed
+func (broker *Broker) Subscribe(tenantID string, types []string) (*Subscription, error) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	if broker.closed {
+		return nil, ErrClosed
+	}
+	subscription := &Subscription{
+		broker:   broker,
+		tenantID: tenantID,
+		types:    types,
+		events:   make(chan *model.EventMessage, broker.buffer),
+	}
+	broker.subscribers[subscription] = struct{}{}
+	return subscription, nil
+}
+
+// Publish passes the event to the subscribers of its tenant
+func (broker *Broker) Publish(event *model.EventMessage) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	for subscription := range broker.subscribers {
+		if !subscription.matches(event) {
+			continue
+		}
+		select {
+		case subscription.events <- event:
+		default:
+			broker.drop(subscription, ErrSlowConsumer)
+		}
+	}
+}
+
+// DropAll ends every subscription with err
+func (broker *Broker) DropAll(err error) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	for subscription := range broker.subscribers {
+		broker.drop(subscription, err)
+	}
+}
+
+// Close ends every subscription with ErrClosed and rejects new ones, e.g. on shutdown
+func (broker *Broker) Close() {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	broker.closed = true
+	for subscription := range broker.subscribers {
+		broker.drop(subscription, ErrClosed)
+	}
+}
+
+// Len returns the number of subscriptions
+func (broker *Broker) Len() int {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	return len(broker.subscribers)
+}
+
+// drop requires the lock of the broker
+func (broker *Broker) drop(subscription *Subscription, err error) {
+	if _, ok := broker.subscribers[subscription]; !ok {
+		return
+	}
+	delete(broker.subscribers, subscription)
+	subscription.err = err
+	close(subscription.events)
+}
+
+func (subscription *Subscription) matches(event *model.EventMessage) bool {
+	return event.TenantID == subscription.tenantID &&
+		(len(subscription.types) == 0 || slices.Contains(subscription.types, event.Type))
+}
+
+// Events is closed when the subscription ends, Err tells why
+func (subscription *Subscription) Events() <-chan *model.EventMessage {
+	return subscription.events
+}
+
+// Err returns why the subscription ended once Events is closed, nil when it was closed by its owner
+func (subscription *Subscription) Err() error {
+	subscription.broker.mutex.Lock()
+	defer subscription.broker.mutex.Unlock()
+	return subscription.err
+}
+
+// Close ends the subscription
+func (subscription *Subscription) Close() {
+	subscription.broker.mutex.Lock()
+	defer subscription.broker.mutex.Unlock()
+	subscription.broker.drop(subscription, nil)
+}
+
+// Stream is a subscription resumed after the last event a client received
+type Stream struct {
+	*Subscription
+	// Reset is set when events after the last one received were missed, the client reloads its state
+	Reset bool
+	// Backlog holds the events after the last one received, they are sent before the live events
+	Backlog    []*model.EventMessage
+	backlogIDs map[int64]struct{}
+}
+
+func NewStream(subscription *Subscription, backlog []*model.EventMessage) *Stream {
+	backlogIDs := make(map[int64]struct{}, len(backlog))
+	for _, event := range backlog {
+		backlogIDs[event.ID] = struct{}{}
+	}
+	return &Stream{Subscription: subscription, Backlog: backlog, backlogIDs: backlogIDs}
+}
+
+// InBacklog reports whether a live event was sent with the backlog already
+func (stream *Stream) InBacklog(event *model.EventMessage) bool {
+	_, ok := stream.backlogIDs[event.ID]
+	return ok
+}
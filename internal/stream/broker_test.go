@@ -0,0 +1,116 @@
+package stream
+
+import (
+	"crud/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func testEvent(id int64, tenantID, eventType string) *model.EventMessage {
+	return &model.EventMessage{ID: id, TenantID: tenantID, AggregateType: model.UserAggregate, Type: eventType}
+}
+
+// received returns the events buffered for the subscription without waiting
+func received(subscription *Subscription) []int64 {
+	ids := make([]int64, 0)
+	for {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return ids
+			}
+			ids = append(ids, event.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestUnitBrokerRouting(t *testing.T) {
+	t.Parallel()
+
+	broker := NewBroker(0)
+	acme, err := broker.Subscribe("acme", nil)
+	require.NoError(t, err)
+	defer acme.Close()
+	acmeDeleted, err := broker.Subscribe("acme", []string{model.UserDeletedEvent})
+	require.NoError(t, err)
+	defer acmeDeleted.Close()
+	globex, err := broker.Subscribe("globex", nil)
+	require.NoError(t, err)
+	defer globex.Close()
+
+	broker.Publish(testEvent(1, "acme", model.UserCreatedEvent))
+	broker.Publish(testEvent(2, "globex", model.UserCreatedEvent))
+	broker.Publish(testEvent(3, "acme", model.UserDeletedEvent))
+
+	assert.Equal(t, []int64{1, 3}, received(acme))
+	assert.Equal(t, []int64{3}, received(acmeDeleted), "only the subscribed types are received")
+	assert.Equal(t, []int64{2}, received(globex), "tenants only receive their own events")
+	assert.Equal(t, 3, broker.Len())
+}
+
+func TestUnitBrokerSlowConsumer(t *testing.T) {
+	t.Parallel()
+
+	broker := NewBroker(2)
+	slow, err := broker.Subscribe("acme", nil)
+	require.NoError(t, err)
+	fast, err := broker.Subscribe("acme", nil)
+	require.NoError(t, err)
+	defer fast.Close()
+
+	for id := int64(1); id <= 3; id++ {
+		broker.Publish(testEvent(id, "acme", model.UserUpdatedEvent))
+		assert.Len(t, received(fast), 1)
+	}
+
+	assert.Equal(t, []int64{1, 2}, received(slow), "the buffered events are still received")
+	_, ok := <-slow.Events()
+	assert.False(t, ok, "the slow subscription is ended")
+	assert.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+	assert.NoError(t, fast.Err())
+	assert.Equal(t, 1, broker.Len())
+	slow.Close()
+	assert.ErrorIs(t, slow.Err(), ErrSlowConsumer, "closing an ended subscription keeps its error")
+}
+
+func TestUnitBrokerDropAllAndClose(t *testing.T) {
+	t.Parallel()
+
+	broker := NewBroker(0)
+	subscription, err := broker.Subscribe("acme", nil)
+	require.NoError(t, err)
+	broker.DropAll(ErrResync)
+	_, ok := <-subscription.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, subscription.Err(), ErrResync)
+
+	subscription, err = broker.Subscribe("acme", nil)
+	require.NoError(t, err)
+	closed, err := broker.Subscribe("acme", nil)
+	require.NoError(t, err)
+	closed.Close()
+	assert.NoError(t, closed.Err(), "subscriptions closed by their owner have no error")
+	broker.Close()
+	_, ok = <-subscription.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, subscription.Err(), ErrClosed)
+	assert.Equal(t, 0, broker.Len())
+	_, err = broker.Subscribe("acme", nil)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestUnitStreamInBacklog(t *testing.T) {
+	t.Parallel()
+
+	broker := NewBroker(0)
+	subscription, err := broker.Subscribe("acme", nil)
+	require.NoError(t, err)
+	defer subscription.Close()
+	stream := NewStream(subscription, []*model.EventMessage{testEvent(4, "acme", model.UserCreatedEvent)})
+
+	assert.True(t, stream.InBacklog(testEvent(4, "acme", model.UserCreatedEvent)))
+	assert.False(t, stream.InBacklog(testEvent(5, "acme", model.UserCreatedEvent)))
+}
@@ -0,0 +1,96 @@
+package stream
+
+import (
+	"context"
+	"crud/internal/model"
+	"encoding/json"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"log/slog"
+	"time"
+)
+
+// UserChangesChannel is the channel the user_change_log trigger notifies
+const UserChangesChannel = "user_changes"
+
+const (
+	listenerMinBackoff = time.Second
+	listenerMaxBackoff = 30 * time.Second
+)
+
+// Listener publishes the event messages notified on a PostgreSQL channel to a broker. It listens on a connection
+// of its own, which it takes from the pool for good, and reconnects when the connection fails
+type Listener struct {
+	pool    *pgxpool.Pool
+	channel string
+	broker  *Broker
+	release func(conn *pgx.Conn)
+	logger  *slog.Logger
+}
+
+// NewListener returns a listener on channel. release, when not nil, is called with the connection taken from the
+// pool, so that the state the pool hooks keep about it is dropped
+func NewListener(pool *pgxpool.Pool, channel string, broker *Broker, release func(conn *pgx.Conn),
+	logger *slog.Logger) *Listener {
+	return &Listener{pool: pool, channel: channel, broker: broker, release: release, logger: logger}
+}
+
+// Run listens until ctx is done. The events notified while it reconnects are lost, so the subscriptions are ended
+// with ErrResync once it listens again and their clients resume from the log
+func (listener *Listener) Run(ctx context.Context) {
+	backoff := listenerMinBackoff
+	reconnecting := false
+	for {
+		err := listener.listen(ctx, func() {
+			backoff = listenerMinBackoff
+			if reconnecting {
+				listener.broker.DropAll(ErrResync)
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		listener.logger.Warn("lost the connection listening for events",
+			slog.String("channel", listener.channel),
+			slog.Duration("retry_in", backoff),
+			slog.String("error", err.Error()))
+		reconnecting = true
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff = min(2*backoff, listenerMaxBackoff)
+	}
+}
+
+func (listener *Listener) listen(ctx context.Context, listening func()) error {
+	pooled, err := listener.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection keeps listening until it is closed, it never goes back to the pool
+	conn := pooled.Hijack()
+	if listener.release != nil {
+		listener.release(conn)
+	}
+	defer conn.Close(context.Background())
+	if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{listener.channel}.Sanitize()); err != nil {
+		return err
+	}
+	listening()
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		event := &model.EventMessage{}
+		if err = json.Unmarshal([]byte(notification.Payload), event); err != nil {
+			listener.logger.Warn("ignored invalid event notification",
+				slog.String("channel", listener.channel),
+				slog.String("error", err.Error()))
+			continue
+		}
+		listener.broker.Publish(event)
+	}
+}
@@ -0,0 +1,46 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+const (
+	// DefaultTenant owns the rows written before tenancy was enabled and every request while it is disabled
+	DefaultTenant = "default"
+	// ClaimName is the token claim, and the claim of session principals, naming the tenant of the caller
+	ClaimName = "tenant_id"
+)
+
+var (
+	ErrMissingTenant = errors.New("missing tenant")
+	ErrInvalidTenant = errors.New("invalid tenant")
+	// ErrTenantMismatch is returned when a request names another tenant than the one of its credentials
+	ErrTenantMismatch = errors.New("tenant does not match the credentials")
+	// ErrUnknownTenant is returned for tenants which were not provisioned
+	ErrUnknownTenant = errors.New("unknown tenant")
+)
+
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// IsValid reports whether id is a tenant identifier: lower case letters, digits, "-" and "_", at most 63 characters
+func IsValid(id string) bool {
+	return tenantPattern.MatchString(id)
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx that carries the tenant the request is scoped to
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant of the request, if any
+func FromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,63 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// CaptureHandler keeps the records it handles in memory, it lets tests assert on the logs of the
+// components they inject the logger into
+type CaptureHandler struct {
+	mutex   *sync.Mutex
+	records *[]slog.Record
+	attrs   []slog.Attr
+	level   slog.Leveler
+}
+
+// NewCaptureLogger returns a logger keeping records at or above level, and its handler
+func NewCaptureLogger(level slog.Leveler) (*slog.Logger, *CaptureHandler) {
+	handler := &CaptureHandler{mutex: &sync.Mutex{}, records: &[]slog.Record{}, level: level}
+	return slog.New(handler), handler
+}
+
+func (handler *CaptureHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= handler.level.Level()
+}
+
+func (handler *CaptureHandler) Handle(_ context.Context, record slog.Record) error {
+	record = record.Clone()
+	record.AddAttrs(handler.attrs...)
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	*handler.records = append(*handler.records, record)
+	return nil
+}
+
+// WithAttrs keeps the attributes on the records, groups are not supported
+func (handler *CaptureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *handler
+	clone.attrs = append(slices.Clone(handler.attrs), attrs...)
+	return &clone
+}
+
+func (handler *CaptureHandler) WithGroup(_ string) slog.Handler {
+	return handler
+}
+
+func (handler *CaptureHandler) Records() []slog.Record {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	return slices.Clone(*handler.records)
+}
+
+// Attrs returns the attributes of a record by key
+func Attrs(record slog.Record) map[string]slog.Value {
+	attrs := make(map[string]slog.Value)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value
+		return true
+	})
+	return attrs
+}
@@ -0,0 +1,122 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorGray   = "\033[90m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorBlue   = "\033[34m"
+	colorCyan   = "\033[36m"
+)
+
+// ConsoleHandler writes human friendly, colored lines for local development, e.g.
+// "15:04:05.000 INF message key=value"
+type ConsoleHandler struct {
+	mutex  *sync.Mutex
+	writer io.Writer
+	color  bool
+	// attrs are the preformatted attributes added with WithAttrs
+	attrs string
+	group string
+}
+
+func NewConsoleHandler(writer io.Writer, color bool) *ConsoleHandler {
+	return &ConsoleHandler{mutex: &sync.Mutex{}, writer: writer, color: color}
+}
+
+func (handler *ConsoleHandler) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
+}
+
+func (handler *ConsoleHandler) colorize(color, text string) string {
+	if !handler.color {
+		return text
+	}
+	return color + text + colorReset
+}
+
+func levelLabel(level slog.Level) (string, string) {
+	switch {
+	case level >= slog.LevelError:
+		return "ERR", colorRed
+	case level >= slog.LevelWarn:
+		return "WRN", colorYellow
+	case level >= slog.LevelInfo:
+		return "INF", colorBlue
+	default:
+		return "DBG", colorGray
+	}
+}
+
+func (handler *ConsoleHandler) Handle(_ context.Context, record slog.Record) error {
+	builder := &strings.Builder{}
+	builder.WriteString(handler.colorize(colorGray, record.Time.Format(time.TimeOnly+".000")))
+	label, color := levelLabel(record.Level)
+	builder.WriteString(" " + handler.colorize(color, label))
+	if record.Message != "" {
+		builder.WriteString(" " + record.Message)
+	}
+	builder.WriteString(handler.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		handler.appendAttr(builder, handler.group, attr)
+		return true
+	})
+	builder.WriteString("\n")
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	_, err := io.WriteString(handler.writer, builder.String())
+	return err
+}
+
+func (handler *ConsoleHandler) appendAttr(builder *strings.Builder, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix += attr.Key + "."
+		}
+		for _, groupAttr := range value.Group() {
+			handler.appendAttr(builder, groupPrefix, groupAttr)
+		}
+		return
+	}
+	text := value.String()
+	if strings.ContainsAny(text, " \t\n\"=") {
+		text = fmt.Sprintf("%q", text)
+	}
+	builder.WriteString(" " + handler.colorize(colorCyan, prefix+attr.Key+"=") + text)
+}
+
+func (handler *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	builder := &strings.Builder{}
+	builder.WriteString(handler.attrs)
+	for _, attr := range attrs {
+		handler.appendAttr(builder, handler.group, attr)
+	}
+	clone := *handler
+	clone.attrs = builder.String()
+	return &clone
+}
+
+func (handler *ConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return handler
+	}
+	clone := *handler
+	clone.group += name + "."
+	return &clone
+}
@@ -0,0 +1,100 @@
+package log
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type debugKey struct{}
+
+// WithDebug escalates the records logged with ctx to debug level, whatever the configured level is
+func WithDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugKey{}, true)
+}
+
+func IsDebug(ctx context.Context) bool {
+	debug, _ := ctx.Value(debugKey{}).(bool)
+	return debug
+}
+
+// MaxDebugTokenLifetime bounds the time between the issue and the expiry of a debug token, the tokens cannot be revoked
+const MaxDebugTokenLifetime = time.Hour
+
+// SignDebugToken creates a token which escalates requests to debug level from issuedAt until expiresAt, it has the
+// form "<issue unix seconds>.<expiry unix seconds>.<base64url HMAC-SHA256 of both>"
+func SignDebugToken(secret []byte, issuedAt time.Time, expiresAt time.Time) string {
+	validity := strconv.FormatInt(issuedAt.Unix(), 10) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return validity + "." + base64.RawURLEncoding.EncodeToString(debugTokenMAC(secret, validity))
+}
+
+// VerifyDebugToken checks the signature and the validity of a debug token, a token living longer than
+// MaxDebugTokenLifetime is rejected whatever its signature
+func VerifyDebugToken(secret []byte, token string, now time.Time) bool {
+	if len(secret) == 0 {
+		return false
+	}
+	cut := strings.LastIndexByte(token, '.')
+	if cut < 0 {
+		return false
+	}
+	validity, signature := token[:cut], token[cut+1:]
+	issue, expiry, found := strings.Cut(validity, ".")
+	if !found {
+		return false
+	}
+	issuedAt, err := strconv.ParseInt(issue, 10, 64)
+	if err != nil {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || expiresAt-issuedAt > int64(MaxDebugTokenLifetime/time.Second) ||
+		now.Unix() < issuedAt || now.Unix() >= expiresAt {
+		return false
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(mac, debugTokenMAC(secret, validity))
+}
+
+func debugTokenMAC(secret []byte, validity string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("debug-log:" + validity))
+	return mac.Sum(nil)
+}
+
+// DebugEscalationHandler enables every level for records logged with a context marked by WithDebug.
+// The wrapped handlers must not filter by level in Handle, which holds for the slog handlers
+type DebugEscalationHandler struct {
+	next slog.Handler
+}
+
+func NewDebugEscalationHandler(next slog.Handler) *DebugEscalationHandler {
+	return &DebugEscalationHandler{next: next}
+}
+
+func (handler *DebugEscalationHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if ctx != nil && level >= slog.LevelDebug && IsDebug(ctx) {
+		return true
+	}
+	return handler.next.Enabled(ctx, level)
+}
+
+func (handler *DebugEscalationHandler) Handle(ctx context.Context, record slog.Record) error {
+	return handler.next.Handle(ctx, record)
+}
+
+func (handler *DebugEscalationHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &DebugEscalationHandler{next: handler.next.WithAttrs(attrs)}
+}
+
+func (handler *DebugEscalationHandler) WithGroup(name string) slog.Handler {
+	return &DebugEscalationHandler{next: handler.next.WithGroup(name)}
+}
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitDebugToken(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("debug-secret")
+	now := time.Now()
+	token := SignDebugToken(secret, now, now.Add(time.Minute))
+
+	assert.True(t, VerifyDebugToken(secret, token, now))
+	assert.False(t, VerifyDebugToken(secret, token, now.Add(2*time.Minute)), "expired token")
+	assert.False(t, VerifyDebugToken(secret, token, now.Add(-time.Minute)), "token not issued yet")
+	assert.False(t, VerifyDebugToken([]byte("other-secret"), token, now), "token of another secret")
+	assert.False(t, VerifyDebugToken(nil, token, now), "tokens are disabled without a secret")
+	issue, _, _ := strings.Cut(token, ".")
+	extended := issue + ".9999999999" + token[strings.LastIndexByte(token, '.'):]
+	assert.False(t, VerifyDebugToken(secret, extended, now), "extended expiry")
+	assert.False(t, VerifyDebugToken(secret, SignDebugToken(secret, now, now.Add(MaxDebugTokenLifetime+time.Second)), now),
+		"token living longer than the maximum lifetime")
+	assert.True(t, VerifyDebugToken(secret, SignDebugToken(secret, now, now.Add(MaxDebugTokenLifetime)), now))
+	assert.False(t, VerifyDebugToken(secret, "garbage", now))
+}
+
+func TestUnitDebugEscalationHandler(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bytes.Buffer{}
+	logger := slog.New(NewDebugEscalationHandler(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	logger.DebugContext(context.Background(), "hidden")
+	assert.Empty(t, buffer.String())
+
+	logger.DebugContext(WithDebug(context.Background()), "traced")
+	assert.Contains(t, buffer.String(), "traced")
+}
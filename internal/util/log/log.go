@@ -9,6 +9,8 @@ import (
 	"time"
 )
 
+// The gin helpers log with the logger and the attributes of the request context
+
 func Info(ctx *gin.Context, msg string, args ...any) {
 	log(ctx.Request.Context(), slogctx.FromCtx(ctx), slog.LevelInfo, msg, 3, args...)
 }
@@ -25,6 +27,38 @@ func Debug(ctx *gin.Context, msg string, args ...any) {
 	log(ctx.Request.Context(), slogctx.FromCtx(ctx), slog.LevelDebug, msg, 3, args...)
 }
 
+// The context helpers log with the logger stored in ctx by WithLogger, or the default logger,
+// and the attributes appended to ctx. They can be used outside gin handlers, e.g. in background jobs
+
+func InfoContext(ctx context.Context, msg string, args ...any) {
+	log(ctx, FromContext(ctx), slog.LevelInfo, msg, 3, args...)
+}
+
+func WarnContext(ctx context.Context, msg string, args ...any) {
+	log(ctx, FromContext(ctx), slog.LevelWarn, msg, 3, args...)
+}
+
+func ErrorContext(ctx context.Context, msg string, args ...any) {
+	log(ctx, FromContext(ctx), slog.LevelError, msg, 3, args...)
+}
+
+func DebugContext(ctx context.Context, msg string, args ...any) {
+	log(ctx, FromContext(ctx), slog.LevelDebug, msg, 3, args...)
+}
+
+// WithLogger stores logger in ctx, it is used by the context helpers instead of the default logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return slogctx.NewCtx(ctx, logger)
+}
+
+// FromContext returns the logger stored in ctx, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx == nil {
+		return slog.Default()
+	}
+	return slogctx.FromCtx(ctx)
+}
+
 // log is the low-level logging method for methods that take ...any.
 // It must always be called directly by an exported logging method
 // or function, because it uses a fixed call depth to obtain the pc.
@@ -0,0 +1,26 @@
+package log
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"runtime"
+	"testing"
+)
+
+func TestUnitContextHelpers(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := NewCaptureLogger(slog.LevelDebug)
+	ctx := WithLogger(context.Background(), logger)
+
+	InfoContext(ctx, "job started", slog.Int("job_id", 7))
+	DebugContext(ctx, "job step")
+
+	records := logs.Records()
+	require.Len(t, records, 2)
+	assert.Equal(t, int64(7), Attrs(records[0])["job_id"].Int64())
+	frame, _ := runtime.CallersFrames([]uintptr{records[0].PC}).Next()
+	assert.Equal(t, "crud/internal/util/log.TestUnitContextHelpers", frame.Function, "the source is the caller of the helper")
+}
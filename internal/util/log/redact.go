@@ -0,0 +1,206 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Redacted replaces masked values in logs
+const Redacted = "[REDACTED]"
+
+// DefaultRedactKeys are attribute keys masked wherever they appear in a log record
+var DefaultRedactKeys = []string{
+	"password", "new_password", "current_password", "password_hash", "token", "secret", "authorization",
+	"cookie", "set-cookie", "api_key", "email",
+}
+
+// DefaultAllowedColumns are columns whose query parameters are safe to log
+var DefaultAllowedColumns = []string{"id", "user_id", "limit", "offset", "created_at", "updated_at"}
+
+const DefaultMaxValueLength = 256
+
+// Secret is a string which is always masked when logged
+type Secret string
+
+func (secret Secret) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// RedactionPolicy describes what is masked in logs
+type RedactionPolicy struct {
+	// Enabled is the global switch, nothing is masked or truncated when it is false
+	Enabled bool
+	// Keys are attribute keys masked in every log record, compared case-insensitively
+	Keys []string
+	// AllowedColumns are columns whose query parameters are logged as is, other string
+	// and binary parameters are masked
+	AllowedColumns []string
+	// MaxValueLength truncates longer string values, 0 disables truncation
+	MaxValueLength int
+}
+
+func DefaultRedactionPolicy() RedactionPolicy {
+	return RedactionPolicy{
+		Enabled:        true,
+		Keys:           DefaultRedactKeys,
+		AllowedColumns: DefaultAllowedColumns,
+		MaxValueLength: DefaultMaxValueLength,
+	}
+}
+
+type compiledPolicy struct {
+	enabled        bool
+	keys           map[string]struct{}
+	allowedColumns map[string]struct{}
+	maxValueLength int
+}
+
+func toSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		set[strings.ToLower(item)] = struct{}{}
+	}
+	return set
+}
+
+// Redactor applies a RedactionPolicy, the policy can be replaced at runtime like a slog.LevelVar
+type Redactor struct {
+	policy atomic.Pointer[compiledPolicy]
+}
+
+func NewRedactor(policy RedactionPolicy) *Redactor {
+	redactor := &Redactor{}
+	redactor.SetPolicy(policy)
+	return redactor
+}
+
+var defaultRedactor = NewRedactor(DefaultRedactionPolicy())
+
+// DefaultRedactor returns the redactor shared by the default logger and the query tracer
+func DefaultRedactor() *Redactor {
+	return defaultRedactor
+}
+
+func (redactor *Redactor) SetPolicy(policy RedactionPolicy) {
+	redactor.policy.Store(&compiledPolicy{
+		enabled:        policy.Enabled,
+		keys:           toSet(policy.Keys),
+		allowedColumns: toSet(policy.AllowedColumns),
+		maxValueLength: policy.MaxValueLength,
+	})
+}
+
+func (redactor *Redactor) Enabled() bool {
+	return redactor.policy.Load().enabled
+}
+
+// RedactAttr masks the attribute if its key is configured, otherwise masks secrets and
+// truncates long values, groups are redacted recursively
+func (redactor *Redactor) RedactAttr(attr slog.Attr) slog.Attr {
+	policy := redactor.policy.Load()
+	if !policy.enabled {
+		return attr
+	}
+	return policy.redactAttr(attr)
+}
+
+func (policy *compiledPolicy) redactAttr(attr slog.Attr) slog.Attr {
+	if _, ok := policy.keys[strings.ToLower(attr.Key)]; ok {
+		return slog.String(attr.Key, Redacted)
+	}
+	if attr.Value.Kind() == slog.KindLogValuer {
+		if _, ok := attr.Value.LogValuer().(Secret); ok {
+			return slog.String(attr.Key, Redacted)
+		}
+	}
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		attrs := value.Group()
+		redacted := make([]slog.Attr, len(attrs))
+		for i, groupAttr := range attrs {
+			redacted[i] = policy.redactAttr(groupAttr)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindString:
+		return slog.String(attr.Key, policy.truncate(value.String()))
+	case slog.KindAny:
+		if bytes, ok := value.Any().([]byte); ok {
+			return slog.String(attr.Key, fmt.Sprintf("[%d bytes]", len(bytes)))
+		}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
+
+func (policy *compiledPolicy) truncate(value string) string {
+	if policy.maxValueLength <= 0 || len(value) <= policy.maxValueLength {
+		return value
+	}
+	return fmt.Sprintf("%s...[%d more bytes]", value[:policy.maxValueLength], len(value)-policy.maxValueLength)
+}
+
+// RedactQueryArg masks a query parameter bound to column, the column is empty when unknown.
+// Parameters of allowed columns are only truncated, numbers, booleans and times are always logged
+// and everything else, e.g. strings and binary values, is masked
+func (redactor *Redactor) RedactQueryArg(column string, arg any) any {
+	policy := redactor.policy.Load()
+	if !policy.enabled {
+		return arg
+	}
+	if _, ok := policy.allowedColumns[strings.ToLower(column)]; ok && column != "" {
+		if value, isString := arg.(string); isString {
+			return policy.truncate(value)
+		}
+		return arg
+	}
+	switch arg.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64,
+		time.Time, time.Duration:
+		return arg
+	}
+	return Redacted
+}
+
+// RedactingHandler masks the attributes of records before passing them to the wrapped handler
+type RedactingHandler struct {
+	next     slog.Handler
+	redactor *Redactor
+}
+
+func NewRedactingHandler(next slog.Handler, redactor *Redactor) *RedactingHandler {
+	return &RedactingHandler{next: next, redactor: redactor}
+}
+
+func (handler *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return handler.next.Enabled(ctx, level)
+}
+
+func (handler *RedactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if !handler.redactor.Enabled() {
+		return handler.next.Handle(ctx, record)
+	}
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(handler.redactor.RedactAttr(attr))
+		return true
+	})
+	return handler.next.Handle(ctx, redacted)
+}
+
+// WithAttrs redacts the attributes once with the policy in effect, they are not redacted
+// again if the policy changes later
+func (handler *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = handler.redactor.RedactAttr(attr)
+	}
+	return &RedactingHandler{next: handler.next.WithAttrs(redacted), redactor: handler.redactor}
+}
+
+func (handler *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{next: handler.next.WithGroup(name), redactor: handler.redactor}
+}
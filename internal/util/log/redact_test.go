@@ -0,0 +1,76 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLogger(redactor *Redactor) (*slog.Logger, *bytes.Buffer) {
+	buffer := &bytes.Buffer{}
+	return slog.New(NewRedactingHandler(slog.NewJSONHandler(buffer, nil), redactor)), buffer
+}
+
+func decodeRecord(t *testing.T, buffer *bytes.Buffer) map[string]any {
+	record := make(map[string]any)
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+	return record
+}
+
+func TestUnitRedactingHandler(t *testing.T) {
+	t.Parallel()
+
+	policy := DefaultRedactionPolicy()
+	policy.MaxValueLength = 8
+	logger, buffer := newTestLogger(NewRedactor(policy))
+
+	logger.With(slog.String("Authorization", "Bearer abc")).Info("login",
+		slog.String("email", "user@example.com"),
+		slog.Group("request", slog.String("password", "hunter22"), slog.Int("id", 7)),
+		slog.Any("token", Secret("abc")),
+		slog.Any("hash", []byte{1, 2, 3}),
+		slog.Any("key", Secret("abc")),
+		slog.String("path", "/api/v1/user/123456"),
+	)
+
+	record := decodeRecord(t, buffer)
+	assert.Equal(t, Redacted, record["Authorization"])
+	assert.Equal(t, Redacted, record["email"])
+	assert.Equal(t, map[string]any{"password": Redacted, "id": float64(7)}, record["request"])
+	assert.Equal(t, "[3 bytes]", record["hash"])
+	assert.Equal(t, Redacted, record["key"], "secrets are masked whatever their key")
+	assert.Equal(t, "/api/v1/...[11 more bytes]", record["path"])
+}
+
+func TestUnitRedactingHandlerDisabled(t *testing.T) {
+	t.Parallel()
+
+	redactor := NewRedactor(RedactionPolicy{Enabled: false, Keys: DefaultRedactKeys})
+	logger, buffer := newTestLogger(redactor)
+	logger.Info("login", slog.String("email", "user@example.com"))
+	assert.Equal(t, "user@example.com", decodeRecord(t, buffer)["email"])
+
+	redactor.SetPolicy(DefaultRedactionPolicy())
+	buffer.Reset()
+	logger.Info("login", slog.String("email", "user@example.com"))
+	assert.False(t, strings.Contains(buffer.String(), "user@example.com"))
+}
+
+func TestUnitRedactQueryArg(t *testing.T) {
+	t.Parallel()
+
+	redactor := NewRedactor(DefaultRedactionPolicy())
+	now := time.Now()
+
+	assert.Equal(t, 7, redactor.RedactQueryArg("", 7))
+	assert.Equal(t, now, redactor.RedactQueryArg("", now))
+	assert.Equal(t, Redacted, redactor.RedactQueryArg("", "user@example.com"))
+	assert.Equal(t, Redacted, redactor.RedactQueryArg("email", "user@example.com"))
+	assert.Equal(t, Redacted, redactor.RedactQueryArg("token_hash", []byte{1, 2}))
+	assert.Equal(t, "42", redactor.RedactQueryArg("USER_ID", "42"))
+}
@@ -0,0 +1,214 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rotationTimeFormat = "20060102T150405.000"
+
+// RotationPolicy describes when a log file is rotated and how many rotated files are kept
+type RotationPolicy struct {
+	// MaxSize rotates the file before it grows over this many bytes, 0 disables size rotation
+	MaxSize int64
+	// Every rotates the file when it is older, 0 disables time rotation
+	Every time.Duration
+	// MaxBackups is the number of rotated files kept, 0 keeps all of them
+	MaxBackups int
+	// MaxAge removes rotated files which are older, 0 keeps them regardless of their age
+	MaxAge time.Duration
+}
+
+// RotatingFile is an io.WriteCloser appending to a file which is renamed to "<name>-<time><ext>"
+// when it is rotated, or "<name>-<time>-<n><ext>" when rotated several times in the same millisecond.
+// The rotated files over the retention are removed
+type RotatingFile struct {
+	mutex    sync.Mutex
+	path     string
+	policy   RotationPolicy
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+func NewRotatingFile(path string, policy RotationPolicy) (*RotatingFile, error) {
+	rotatingFile := &RotatingFile{path: path, policy: policy, now: time.Now}
+	if err := rotatingFile.open(); err != nil {
+		return nil, err
+	}
+	return rotatingFile, nil
+}
+
+func (rotatingFile *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(rotatingFile.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(rotatingFile.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	rotatingFile.file = file
+	rotatingFile.size = info.Size()
+	// A file left by a previous run is as old as its last write
+	rotatingFile.openedAt = rotatingFile.now()
+	if info.Size() > 0 {
+		rotatingFile.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+func (rotatingFile *RotatingFile) Write(data []byte) (int, error) {
+	rotatingFile.mutex.Lock()
+	defer rotatingFile.mutex.Unlock()
+
+	if rotatingFile.file == nil {
+		return 0, os.ErrClosed
+	}
+	if rotatingFile.shouldRotate(int64(len(data))) {
+		if err := rotatingFile.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	written, err := rotatingFile.file.Write(data)
+	rotatingFile.size += int64(written)
+	return written, err
+}
+
+func (rotatingFile *RotatingFile) shouldRotate(size int64) bool {
+	if rotatingFile.size == 0 {
+		return false
+	}
+	if rotatingFile.policy.MaxSize > 0 && rotatingFile.size+size > rotatingFile.policy.MaxSize {
+		return true
+	}
+	return rotatingFile.policy.Every > 0 && rotatingFile.now().Sub(rotatingFile.openedAt) >= rotatingFile.policy.Every
+}
+
+func (rotatingFile *RotatingFile) rotate() error {
+	if err := rotatingFile.file.Close(); err != nil {
+		return err
+	}
+	rotatingFile.file = nil
+	backupName, err := rotatingFile.backupName(rotatingFile.now())
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(rotatingFile.path, backupName); err != nil {
+		return err
+	}
+	if err := rotatingFile.open(); err != nil {
+		return err
+	}
+	return rotatingFile.removeOldBackups()
+}
+
+// backupName returns the first name of a rotation at rotatedAt which is not taken, so a backup is never replaced
+func (rotatingFile *RotatingFile) backupName(rotatedAt time.Time) (string, error) {
+	extension := filepath.Ext(rotatingFile.path)
+	name := fmt.Sprintf("%s-%s", strings.TrimSuffix(rotatingFile.path, extension), rotatedAt.UTC().Format(rotationTimeFormat))
+	for counter := 0; ; counter++ {
+		backupName := name + extension
+		if counter > 0 {
+			backupName = fmt.Sprintf("%s-%d%s", name, counter, extension)
+		}
+		if _, err := os.Lstat(backupName); os.IsNotExist(err) {
+			return backupName, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+}
+
+// parseBackupName returns the rotation time and counter of a backup name without prefix and extension
+func parseBackupName(name string) (time.Time, int, bool) {
+	stamp, counter, found := strings.Cut(name, "-")
+	rotatedAt, err := time.Parse(rotationTimeFormat, stamp)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	if !found {
+		return rotatedAt, 0, true
+	}
+	n, err := strconv.Atoi(counter)
+	return rotatedAt, n, err == nil && n > 0
+}
+
+// backups returns the rotated files, the newest first
+func (rotatingFile *RotatingFile) backups() ([]string, error) {
+	extension := filepath.Ext(rotatingFile.path)
+	prefix := strings.TrimSuffix(rotatingFile.path, extension) + "-"
+	matches, err := filepath.Glob(prefix + "*" + extension)
+	if err != nil {
+		return nil, err
+	}
+	// Skip other files sharing the prefix, e.g. "app-access.log" next to "app.log"
+	type backup struct {
+		name      string
+		rotatedAt time.Time
+		counter   int
+	}
+	found := make([]backup, 0, len(matches))
+	for _, match := range matches {
+		if rotatedAt, counter, ok := parseBackupName(strings.TrimSuffix(strings.TrimPrefix(match, prefix), extension)); ok {
+			found = append(found, backup{name: match, rotatedAt: rotatedAt, counter: counter})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if !found[i].rotatedAt.Equal(found[j].rotatedAt) {
+			return found[i].rotatedAt.After(found[j].rotatedAt)
+		}
+		return found[i].counter > found[j].counter
+	})
+	backups := make([]string, len(found))
+	for i, backup := range found {
+		backups[i] = backup.name
+	}
+	return backups, nil
+}
+
+func (rotatingFile *RotatingFile) removeOldBackups() error {
+	if rotatingFile.policy.MaxBackups <= 0 && rotatingFile.policy.MaxAge <= 0 {
+		return nil
+	}
+	backups, err := rotatingFile.backups()
+	if err != nil {
+		return err
+	}
+	for i, backup := range backups {
+		expired := false
+		if rotatingFile.policy.MaxAge > 0 {
+			info, err := os.Stat(backup)
+			expired = err == nil && rotatingFile.now().Sub(info.ModTime()) > rotatingFile.policy.MaxAge
+		}
+		if expired || (rotatingFile.policy.MaxBackups > 0 && i >= rotatingFile.policy.MaxBackups) {
+			if err = os.Remove(backup); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (rotatingFile *RotatingFile) Close() error {
+	rotatingFile.mutex.Lock()
+	defer rotatingFile.mutex.Unlock()
+
+	if rotatingFile.file == nil {
+		return nil
+	}
+	err := rotatingFile.file.Close()
+	rotatingFile.file = nil
+	return err
+}
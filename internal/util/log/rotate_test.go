@@ -0,0 +1,91 @@
+package log
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUnitRotatingFileSize(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	path := filepath.Join(directory, "app.log")
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "app-access.log"), []byte("other"), 0o600))
+
+	rotatingFile, err := NewRotatingFile(path, RotationPolicy{MaxSize: 10, MaxBackups: 2})
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rotatingFile.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = rotatingFile.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, rotatingFile.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fourth\n", string(content))
+
+	backups, err := rotatingFile.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2, "only the newest backups are kept")
+	content, err = os.ReadFile(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(content))
+	assert.FileExists(t, filepath.Join(directory, "app-access.log"), "files which are not backups are kept")
+}
+
+func TestUnitRotatingFileTime(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	rotatingFile, err := NewRotatingFile(path, RotationPolicy{Every: time.Hour})
+	require.NoError(t, err)
+	now := time.Now()
+	rotatingFile.now = func() time.Time { return now }
+	rotatingFile.openedAt = now
+
+	_, err = rotatingFile.Write([]byte("before\n"))
+	require.NoError(t, err)
+	now = now.Add(time.Hour)
+	_, err = rotatingFile.Write([]byte("after\n"))
+	require.NoError(t, err)
+	require.NoError(t, rotatingFile.Close())
+
+	backups, err := rotatingFile.backups()
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+}
+
+func TestUnitRotatingFileSameMillisecond(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	rotatingFile, err := NewRotatingFile(path, RotationPolicy{MaxSize: 10, MaxBackups: 11})
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rotatingFile.now = func() time.Time { return now }
+	for i := 0; i < 12; i++ {
+		_, err = rotatingFile.Write([]byte(fmt.Sprintf("line %02d\n", i)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, rotatingFile.Close())
+
+	backups, err := rotatingFile.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 11, "no backup replaced another one")
+	for i, backup := range backups {
+		content, err := os.ReadFile(backup)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("line %02d\n", 10-i), string(content), "the newest backups first")
+	}
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "app-20240101T000000.000-10.log"), backups[0])
+}
@@ -0,0 +1,81 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultSamplingTick is the period the sampling counters are reset after
+const DefaultSamplingTick = time.Second
+
+type samplingKey struct {
+	level   slog.Level
+	message string
+}
+
+type sampler struct {
+	mutex       sync.Mutex
+	first       uint64
+	thereafter  uint64
+	tick        time.Duration
+	windowStart time.Time
+	counters    map[samplingKey]uint64
+	now         func() time.Time
+}
+
+// SamplingHandler drops repetitive records at info level and below, e.g. the access log. Within each tick
+// the first records with the same level and message are kept, then every thereafter-th one.
+// Warnings, errors and records of requests escalated to debug level are never dropped
+type SamplingHandler struct {
+	next    slog.Handler
+	sampler *sampler
+}
+
+func NewSamplingHandler(next slog.Handler, first int, thereafter int, tick time.Duration) *SamplingHandler {
+	return &SamplingHandler{next: next, sampler: &sampler{
+		first:      uint64(max(first, 0)),
+		thereafter: uint64(max(thereafter, 0)),
+		tick:       tick,
+		counters:   make(map[samplingKey]uint64),
+		now:        time.Now,
+	}}
+}
+
+func (handler *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return handler.next.Enabled(ctx, level)
+}
+
+func (handler *SamplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level > slog.LevelInfo || (ctx != nil && IsDebug(ctx)) || handler.sampler.keep(record) {
+		return handler.next.Handle(ctx, record)
+	}
+	return nil
+}
+
+func (sampler *sampler) keep(record slog.Record) bool {
+	sampler.mutex.Lock()
+	defer sampler.mutex.Unlock()
+
+	// Resetting all counters at once keeps the map bounded by the messages of one tick
+	if now := sampler.now(); now.Sub(sampler.windowStart) >= sampler.tick {
+		sampler.windowStart = now
+		clear(sampler.counters)
+	}
+	key := samplingKey{level: record.Level, message: record.Message}
+	sampler.counters[key]++
+	count := sampler.counters[key]
+	if count <= sampler.first {
+		return true
+	}
+	return sampler.thereafter > 0 && (count-sampler.first)%sampler.thereafter == 0
+}
+
+func (handler *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: handler.next.WithAttrs(attrs), sampler: handler.sampler}
+}
+
+func (handler *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: handler.next.WithGroup(name), sampler: handler.sampler}
+}
@@ -0,0 +1,248 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatText    = "text"
+	FormatLogfmt  = "logfmt"
+	FormatConsole = "console"
+)
+
+// Sink is a handler which only receives records at or above its minimum level
+type Sink struct {
+	Handler  slog.Handler
+	MinLevel slog.Level
+}
+
+// NewFormatHandler creates a handler writing records to writer in format: json, text (or its alias logfmt)
+// or console, which is colored when color is set
+func NewFormatHandler(format string, writer io.Writer, addSource bool, color bool) (slog.Handler, error) {
+	// The level is checked by FanoutHandler, the handlers write every record they get
+	options := &slog.HandlerOptions{AddSource: addSource}
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return slog.NewJSONHandler(writer, options), nil
+	case FormatText, FormatLogfmt:
+		return slog.NewTextHandler(writer, options), nil
+	case FormatConsole:
+		return NewConsoleHandler(writer, color), nil
+	default:
+		return nil, fmt.Errorf("invalid log format: %s. Please use one of: json, text, logfmt, console", format)
+	}
+}
+
+type closers []io.Closer
+
+func (closers closers) Close() error {
+	var err error
+	for _, closer := range closers {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
+
+// OpenSinks opens the sinks of a semicolon separated list of outputs with optional query parameters, e.g.
+// "stdout?format=console;file:///var/log/crud.log?level=warn&max_size=100MB&max_backups=7".
+// The outputs are stdout, stderr and file:<path>. The parameters are format (defaultFormat when missing),
+// level, source (false to leave out the source location), color (console only) and, for files,
+// max_size, rotate_every, max_backups and max_age, see RotationPolicy. The closer closes the files
+func OpenSinks(spec string, defaultFormat string) ([]Sink, io.Closer, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = "stdout"
+	}
+	sinks := make([]Sink, 0)
+	files := make(closers, 0)
+	for _, item := range strings.Split(spec, ";") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		sink, file, err := openSink(item, defaultFormat)
+		if err != nil {
+			_ = files.Close()
+			return nil, nil, fmt.Errorf("invalid log sink %q: %w", item, err)
+		}
+		if file != nil {
+			files = append(files, file)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, files, nil
+}
+
+func openSink(item string, defaultFormat string) (Sink, io.Closer, error) {
+	sinkURL, err := url.Parse(item)
+	if err != nil {
+		return Sink{}, nil, err
+	}
+	query := sinkURL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = defaultFormat
+	}
+	minLevel := slog.LevelDebug
+	if level := query.Get("level"); level != "" {
+		if err = minLevel.UnmarshalText([]byte(level)); err != nil {
+			return Sink{}, nil, err
+		}
+	}
+	addSource, err := parseBoolParam(query, "source", format != FormatConsole)
+	if err != nil {
+		return Sink{}, nil, err
+	}
+
+	var writer io.Writer
+	var file io.Closer
+	isTerminal := false
+	switch {
+	case sinkURL.Scheme == "" && sinkURL.Path == "stdout":
+		writer, isTerminal = os.Stdout, isCharDevice(os.Stdout)
+	case sinkURL.Scheme == "" && sinkURL.Path == "stderr":
+		writer, isTerminal = os.Stderr, isCharDevice(os.Stderr)
+	case sinkURL.Scheme == "file":
+		path := sinkURL.Path
+		if sinkURL.Opaque != "" {
+			path = sinkURL.Opaque
+		}
+		policy, err := parseRotationPolicy(query)
+		if err != nil {
+			return Sink{}, nil, err
+		}
+		rotatingFile, err := NewRotatingFile(path, policy)
+		if err != nil {
+			return Sink{}, nil, err
+		}
+		writer, file = rotatingFile, rotatingFile
+	default:
+		return Sink{}, nil, errors.New("unknown output, use stdout, stderr or file:<path>")
+	}
+
+	color, err := parseBoolParam(query, "color", isTerminal)
+	if err == nil {
+		var handler slog.Handler
+		if handler, err = NewFormatHandler(format, writer, addSource, color); err == nil {
+			return Sink{Handler: handler, MinLevel: minLevel}, file, nil
+		}
+	}
+	if file != nil {
+		_ = file.Close()
+	}
+	return Sink{}, nil, err
+}
+
+func isCharDevice(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func parseBoolParam(query url.Values, name string, defaultValue bool) (bool, error) {
+	if !query.Has(name) {
+		return defaultValue, nil
+	}
+	return strconv.ParseBool(query.Get(name))
+}
+
+func parseRotationPolicy(query url.Values) (RotationPolicy, error) {
+	policy := RotationPolicy{}
+	var err error
+	if value := query.Get("max_size"); value != "" {
+		if policy.MaxSize, err = parseSize(value); err != nil {
+			return policy, err
+		}
+	}
+	if value := query.Get("rotate_every"); value != "" {
+		if policy.Every, err = time.ParseDuration(value); err != nil {
+			return policy, err
+		}
+	}
+	if value := query.Get("max_backups"); value != "" {
+		if policy.MaxBackups, err = strconv.Atoi(value); err != nil {
+			return policy, err
+		}
+	}
+	if value := query.Get("max_age"); value != "" {
+		if policy.MaxAge, err = time.ParseDuration(value); err != nil {
+			return policy, err
+		}
+	}
+	return policy, nil
+}
+
+// parseSize parses a number of bytes with an optional KB, MB or GB suffix, e.g. "100MB"
+func parseSize(value string) (int64, error) {
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	for _, unit := range units {
+		if number, found := strings.CutSuffix(upper, unit.suffix); found {
+			size, err := strconv.ParseInt(strings.TrimSpace(number), 10, 64)
+			return size * unit.multiplier, err
+		}
+	}
+	return strconv.ParseInt(upper, 10, 64)
+}
+
+// FanoutHandler passes records to every sink whose minimum level they reach. Records must also reach
+// the global level, unless they are logged with a context marked by WithDebug
+type FanoutHandler struct {
+	level slog.Leveler
+	sinks []Sink
+}
+
+func NewFanoutHandler(level slog.Leveler, sinks ...Sink) *FanoutHandler {
+	return &FanoutHandler{level: level, sinks: sinks}
+}
+
+func (handler *FanoutHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if level < handler.level.Level() {
+		return false
+	}
+	for _, sink := range handler.sinks {
+		if level >= sink.MinLevel {
+			return true
+		}
+	}
+	return false
+}
+
+func (handler *FanoutHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level < handler.level.Level() && (ctx == nil || !IsDebug(ctx)) {
+		return nil
+	}
+	var err error
+	for _, sink := range handler.sinks {
+		if record.Level >= sink.MinLevel {
+			err = errors.Join(err, sink.Handler.Handle(ctx, record.Clone()))
+		}
+	}
+	return err
+}
+
+func (handler *FanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	sinks := make([]Sink, len(handler.sinks))
+	for i, sink := range handler.sinks {
+		sinks[i] = Sink{Handler: sink.Handler.WithAttrs(attrs), MinLevel: sink.MinLevel}
+	}
+	return &FanoutHandler{level: handler.level, sinks: sinks}
+}
+
+func (handler *FanoutHandler) WithGroup(name string) slog.Handler {
+	sinks := make([]Sink, len(handler.sinks))
+	for i, sink := range handler.sinks {
+		sinks[i] = Sink{Handler: sink.Handler.WithGroup(name), MinLevel: sink.MinLevel}
+	}
+	return &FanoutHandler{level: handler.level, sinks: sinks}
+}
@@ -0,0 +1,92 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitFanoutHandlerLevels(t *testing.T) {
+	t.Parallel()
+
+	all := &bytes.Buffer{}
+	warnings := &bytes.Buffer{}
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	logger := slog.New(NewDebugEscalationHandler(NewFanoutHandler(level,
+		Sink{Handler: slog.NewTextHandler(all, nil), MinLevel: slog.LevelDebug},
+		Sink{Handler: slog.NewJSONHandler(warnings, nil), MinLevel: slog.LevelWarn},
+	)))
+
+	logger.Debug("hidden")
+	logger.Info("info")
+	logger.Warn("warning")
+	logger.DebugContext(WithDebug(context.Background()), "escalated")
+
+	assert.NotContains(t, all.String(), "hidden")
+	assert.Contains(t, all.String(), "msg=info")
+	assert.Contains(t, all.String(), "msg=escalated")
+	assert.Equal(t, 1, strings.Count(warnings.String(), "\n"), "the warn sink only gets warnings")
+	assert.Contains(t, warnings.String(), `"msg":"warning"`)
+}
+
+func TestUnitOpenSinks(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	sinks, closer, err := OpenSinks("stderr?format=console&color=false;file:"+path+"?format=json&level=error&max_size=1MB", "text")
+	require.NoError(t, err)
+	require.Len(t, sinks, 2)
+	assert.IsType(t, &ConsoleHandler{}, sinks[0].Handler)
+	assert.Equal(t, slog.LevelError, sinks[1].MinLevel)
+
+	logger := slog.New(NewFanoutHandler(slog.LevelInfo, sinks[1]))
+	logger.Error("failure", slog.Int("status", 500))
+	require.NoError(t, closer.Close())
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"msg":"failure"`)
+
+	for _, spec := range []string{"stdout?format=xml", "kafka://broker", "stdout?level=loud", "file:" + path + "?max_size=big"} {
+		_, _, err = OpenSinks(spec, FormatJSON)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestUnitConsoleHandler(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bytes.Buffer{}
+	logger := slog.New(NewConsoleHandler(buffer, false)).With(slog.String("service", "crud")).WithGroup("http")
+	logger.Warn("slow request", slog.Int("status", 200), slog.String("path", "/api v1"))
+
+	assert.Regexp(t, `^\d{2}:\d{2}:\d{2}\.\d{3} WRN slow request service=crud http\.status=200 http\.path="/api v1"\n$`, buffer.String())
+}
+
+func TestUnitSamplingHandler(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bytes.Buffer{}
+	handler := NewSamplingHandler(slog.NewTextHandler(buffer, nil), 2, 3, time.Second)
+	now := time.Now()
+	handler.sampler.now = func() time.Time { return now }
+	logger := slog.New(handler)
+
+	for i := 0; i < 8; i++ {
+		logger.Info("access")
+	}
+	logger.Warn("access")
+	assert.Equal(t, 5, strings.Count(buffer.String(), "msg=access"), "first 2, the 5th and the 8th info record and the warning")
+
+	buffer.Reset()
+	now = now.Add(time.Second)
+	logger.Info("access")
+	assert.Contains(t, buffer.String(), "msg=access", "the counters are reset every tick")
+}
@@ -0,0 +1,143 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type clientIPKey struct{}
+
+// ClientIPResolver finds the client address of a request, trusting the forwarding header only when it was
+// added by one of the configured proxies. For a single X-Forwarded-For like header it resolves the address the way
+// gin's Context.ClientIP does with the same header and trusted proxies, handlers should still use GetClientIP so
+// that they agree with logging and rate limiting on repeated headers and on Forwarded
+type ClientIPResolver struct {
+	trustedProxies []netip.Prefix
+	header         string
+}
+
+// NewClientIPResolver creates a resolver from a list of CIDRs or single IP addresses and the forwarding header
+// set by the proxies, either the RFC 7239 Forwarded header or a comma separated list of addresses like
+// X-Forwarded-For. Any other header is ignored
+func NewClientIPResolver(trustedProxies []string, header string) (*ClientIPResolver, error) {
+	if strings.TrimSpace(header) == "" {
+		return nil, fmt.Errorf("client IP header is required")
+	}
+	prefixes := make([]netip.Prefix, 0, len(trustedProxies))
+	for _, proxy := range trustedProxies {
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return &ClientIPResolver{trustedProxies: prefixes, header: http.CanonicalHeaderKey(strings.TrimSpace(header))}, nil
+}
+
+// Resolve walks the forwarding chain of the header from right to left and returns the first hop that is not
+// a trusted proxy. A chain with an invalid hop is not trusted at all, the peer address is returned then
+func (resolver *ClientIPResolver) Resolve(r *http.Request) string {
+	remoteAddr := hostFromRemoteAddr(r.RemoteAddr)
+	remote, err := netip.ParseAddr(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	remoteAddr = remote.Unmap().String()
+	values := r.Header.Values(resolver.header)
+	if len(values) == 0 || !resolver.isTrusted(remote) {
+		return remoteAddr
+	}
+
+	hops := forwardingHops(values, resolver.header == "Forwarded")
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(hops[i])
+		if err != nil {
+			return remoteAddr
+		}
+		if i == 0 || !resolver.isTrusted(addr) {
+			return addr.Unmap().String()
+		}
+	}
+	return remoteAddr
+}
+
+// forwardingHops returns the addresses of the forwarding chain in every line of the header, the closest proxy being
+// the last one. The addresses of Forwarded are its "for" parameters, an element without one gives an empty hop
+func forwardingHops(values []string, forwarded bool) []string {
+	hops := make([]string, 0)
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			if !forwarded {
+				hops = append(hops, strings.TrimSpace(element))
+				continue
+			}
+			hop := ""
+			for _, pair := range strings.Split(element, ";") {
+				name, node, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if found && strings.EqualFold(strings.TrimSpace(name), "for") {
+					hop = forwardedNodeAddress(node)
+				}
+			}
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
+// forwardedNodeAddress strips the quotes, brackets and port from a Forwarded node, e.g. "[2001:db8::1]:4711"
+func forwardedNodeAddress(node string) string {
+	node = strings.Trim(strings.TrimSpace(node), `"`)
+	if strings.HasPrefix(node, "[") {
+		if end := strings.Index(node, "]"); end > 0 {
+			return node[1:end]
+		}
+		return node
+	}
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		return host
+	}
+	return node
+}
+
+func (resolver *ClientIPResolver) isTrusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range resolver.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func hostFromRemoteAddr(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+// WithClientIP returns a copy of ctx that carries the resolved client IP
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, clientIP)
+}
+
+// GetClientIP gets the client IP resolved by the client IP middleware, falling back to the socket address
+func GetClientIP(c *gin.Context) string {
+	if clientIP, ok := c.Request.Context().Value(clientIPKey{}).(string); ok {
+		return clientIP
+	}
+	return hostFromRemoteAddr(c.Request.RemoteAddr)
+}
@@ -0,0 +1,120 @@
+package request
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitClientIPResolver(t *testing.T) {
+	t.Parallel()
+
+	trustedProxies := []string{"10.0.0.0/8", "192.168.1.1"}
+	resolver, err := NewClientIPResolver(trustedProxies, "X-Forwarded-For")
+	require.NoError(t, err)
+	engine := gin.New()
+	engine.RemoteIPHeaders = []string{"X-Forwarded-For"}
+	require.NoError(t, engine.SetTrustedProxies(trustedProxies))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expectedIP string
+	}{
+		{"Untrusted peer headers are ignored", "203.0.113.7:1234",
+			map[string]string{"X-Forwarded-For": "1.2.3.4"}, "203.0.113.7"},
+		{"Trusted peer without headers", "10.1.2.3:1234",
+			map[string]string{}, "10.1.2.3"},
+		{"Rightmost untrusted hop is used", "10.1.2.3:1234",
+			map[string]string{"X-Forwarded-For": "6.6.6.6, 198.51.100.2, 10.9.9.9"}, "198.51.100.2"},
+		{"All hops trusted returns leftmost", "192.168.1.1:1234",
+			map[string]string{"X-Forwarded-For": "10.0.0.2, 10.0.0.3"}, "10.0.0.2"},
+		{"Invalid hop falls back to the peer", "10.1.2.3:1234",
+			map[string]string{"X-Forwarded-For": "198.51.100.2, garbage"}, "10.1.2.3"},
+		{"X-Real-IP is not the configured header", "10.1.2.3:1234",
+			map[string]string{"X-Real-IP": "198.51.100.9"}, "10.1.2.3"},
+		{"Forwarded header cannot override the configured header", "10.1.2.3:1234",
+			map[string]string{
+				"Forwarded":       `for=6.6.6.6;proto=https`,
+				"X-Forwarded-For": "198.51.100.17",
+			}, "198.51.100.17"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			assert.Equal(t, tt.expectedIP, resolver.Resolve(req))
+
+			c := gin.CreateTestContextOnly(httptest.NewRecorder(), engine)
+			c.Request = req
+			assert.Equal(t, c.ClientIP(), resolver.Resolve(req), "gin resolves the same client IP")
+		})
+	}
+}
+
+func TestUnitClientIPResolverForwarded(t *testing.T) {
+	t.Parallel()
+
+	resolver, err := NewClientIPResolver([]string{"10.0.0.0/8", "2001:db8:ffff::/48"}, "forwarded")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		values     []string
+		expectedIP string
+	}{
+		{"Single element", "10.1.2.3:1234", []string{`for=198.51.100.2;proto=https`}, "198.51.100.2"},
+		{"Node with port", "10.1.2.3:1234", []string{`for="198.51.100.2:4711"`}, "198.51.100.2"},
+		{"Quoted IPv6 node", "10.1.2.3:1234", []string{`for="[2001:db8::1]"`}, "2001:db8::1"},
+		{"Quoted IPv6 node with port", "10.1.2.3:1234", []string{`for="[2001:db8::1]:4711";by=10.1.2.3`}, "2001:db8::1"},
+		{"Parameter names are case insensitive", "10.1.2.3:1234", []string{`proto=http;For=198.51.100.2`}, "198.51.100.2"},
+		{"Rightmost untrusted element is used", "10.1.2.3:1234",
+			[]string{`for=6.6.6.6, for=198.51.100.2, for="[2001:db8:ffff::7]:80"`}, "198.51.100.2"},
+		{"Every line of the header is read", "10.1.2.3:1234",
+			[]string{`for=6.6.6.6`, `for=198.51.100.2`, `for=10.9.9.9`}, "198.51.100.2"},
+		{"Obfuscated node falls back to the peer", "10.1.2.3:1234", []string{`for=_hidden, for=10.9.9.9`}, "10.1.2.3"},
+		{"Element without a node falls back to the peer", "10.1.2.3:1234", []string{`proto=https`}, "10.1.2.3"},
+		{"Untrusted peer header is ignored", "203.0.113.7:1234", []string{`for=198.51.100.2`}, "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.values {
+				req.Header.Add("Forwarded", value)
+			}
+			assert.Equal(t, tt.expectedIP, resolver.Resolve(req))
+		})
+	}
+}
+
+func TestUnitClientIPResolverRepeatedHeader(t *testing.T) {
+	t.Parallel()
+
+	resolver, err := NewClientIPResolver([]string{"10.0.0.0/8"}, "X-Forwarded-For")
+	require.NoError(t, err)
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.1.2.3:1234"
+	req.Header.Add("X-Forwarded-For", "6.6.6.6")
+	req.Header.Add("X-Forwarded-For", "198.51.100.2, 10.9.9.9")
+	assert.Equal(t, "198.51.100.2", resolver.Resolve(req), "the last proxy may append its own line")
+}
+
+func TestUnitClientIPResolverInvalidProxy(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClientIPResolver([]string{"10.0.0.0/33"}, "X-Forwarded-For")
+	assert.Error(t, err)
+	_, err = NewClientIPResolver([]string{"not-an-ip"}, "X-Forwarded-For")
+	assert.Error(t, err)
+	_, err = NewClientIPResolver([]string{"10.0.0.0/8"}, " ")
+	assert.Error(t, err)
+}
@@ -1,8 +1,6 @@
 package request
 
 import (
-	"github.com/gin-gonic/gin"
-	"strings"
 	"time"
 )
 
@@ -14,25 +12,3 @@ func GetDurationInMilliseconds(start time.Time) float64 {
 	rounded := float64(int(milliseconds*100+.5)) / 100
 	return rounded
 }
-
-// GetClientIP gets the correct IP for the end client instead of the proxy
-func GetClientIP(c *gin.Context) string {
-	// first check the X-Forwarded-For header
-	requester := c.Request.Header.Get("X-Forwarded-For")
-	// if empty, check the Real-IP header
-	if len(requester) == 0 {
-		requester = c.Request.Header.Get("X-Real-IP")
-	}
-	// if the requester is still empty, use the hard-coded address from the socket
-	if len(requester) == 0 {
-		requester = c.Request.RemoteAddr
-	}
-
-	// if requester is a comma-delimited list, take the first one
-	// (this happens when proxied via elastic load balancer then again through nginx)
-	if strings.Contains(requester, ",") {
-		requester = strings.Split(requester, ",")[0]
-	}
-
-	return requester
-}
@@ -0,0 +1,35 @@
+package request
+
+import (
+	"context"
+	"regexp"
+)
+
+// HeaderRequestID carries the request id on the outbound requests, e.g. the webhook deliveries
+const HeaderRequestID = "X-Request-ID"
+
+// MaxRequestIDLength is the longest inbound request id that is accepted as is
+const MaxRequestIDLength = 128
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:\-]+$`)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx that carries the given request id
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// GetRequestID returns the request id stored in ctx or an empty string
+func GetRequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// IsValidRequestID checks that an inbound request id is safe to log and echo back to the caller
+func IsValidRequestID(requestID string) bool {
+	return len(requestID) > 0 && len(requestID) <= MaxRequestIDLength && validRequestID.MatchString(requestID)
+}
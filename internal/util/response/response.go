@@ -2,23 +2,76 @@ package response
 
 import (
 	"crud/internal/util/log"
+	"crud/internal/util/request"
+	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"net/http"
 	"strconv"
 )
 
 func NewError(ctx *gin.Context, status int, err error) {
 	httpError := HTTPStatusMessage{
-		Code:    status,
-		Message: err.Error(),
+		Code:      status,
+		Message:   err.Error(),
+		RequestID: request.GetRequestID(ctx.Request.Context()),
 	}
 	log.Warn(ctx, err.Error())
 	ctx.JSON(status, httpError)
 }
 
+// AbortWithError writes the error response and stops the remaining handlers, it's meant for middlewares
+func AbortWithError(ctx *gin.Context, status int, err error) {
+	NewError(ctx, status, err)
+	ctx.Abort()
+}
+
+// ProblemContentType is the media type of the RFC 9457 problem details
+const ProblemContentType = "application/problem+json"
+
+// AbortWithProblem writes the error as RFC 9457 problem details and stops the remaining handlers
+func AbortWithProblem(ctx *gin.Context, status int, err error) {
+	problem := ProblemDetails{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    err.Error(),
+		Instance:  ctx.Request.URL.Path,
+		RequestID: request.GetRequestID(ctx.Request.Context()),
+	}
+	log.Warn(ctx, err.Error())
+	ctx.Render(status, problemRender{problem: problem})
+	ctx.Abort()
+}
+
+// ProblemDetails is the body of the RFC 9457 problem responses
+type ProblemDetails struct {
+	Type      string `json:"type" example:"about:blank"`
+	Title     string `json:"title" example:"Too Many Requests"`
+	Status    int    `json:"status" example:"429"`
+	Detail    string `json:"detail,omitempty" example:"rate limit exceeded"`
+	Instance  string `json:"instance,omitempty" example:"/api/v1/user"`
+	RequestID string `json:"request_id,omitempty" example:"3f2b8c1e-5d4a-4e7b-9c6f-0a1b2c3d4e5f"`
+}
+
+// problemRender is render.JSON with the problem media type
+type problemRender struct {
+	problem ProblemDetails
+}
+
+func (r problemRender) Render(writer http.ResponseWriter) error {
+	r.WriteContentType(writer)
+	return json.NewEncoder(writer).Encode(r.problem)
+}
+
+func (r problemRender) WriteContentType(writer http.ResponseWriter) {
+	writer.Header().Set("Content-Type", ProblemContentType)
+}
+
 type HTTPStatusMessage struct {
-	Code    int    `json:"code" example:"400"`
-	Message string `json:"message" example:"status bad request"`
+	Code      int    `json:"code" example:"400"`
+	Message   string `json:"message" example:"status bad request"`
+	RequestID string `json:"request_id,omitempty" example:"3f2b8c1e-5d4a-4e7b-9c6f-0a1b2c3d4e5f"`
 }
 
 func GetIntQueryParamOrDefault(ctx *gin.Context, paramName string, defaultValue int) (resultVal int, resultErr error) {
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of the webhook deliveries
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// signatureVersion prefixes the signatures, receivers ignore the signatures of versions they do not know
+const signatureVersion = "v1="
+
+// DefaultTolerance is the age up to which receivers should accept a delivery, older ones may be replayed requests
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	ErrMissingSignature = errors.New("missing webhook signature")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrExpiredTimestamp = errors.New("webhook timestamp outside of the tolerance")
+)
+
+// Sign returns the signature header of a delivery, the hex encoded HMAC-SHA256 of "<timestamp>.<body>"
+// keyed with the secret of the subscription
+func Sign(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SetHeaders signs the body and sets the signature headers of a delivery sent at now
+func SetHeaders(header http.Header, secret []byte, body []byte, now time.Time) {
+	timestamp := now.Unix()
+	header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	header.Set(HeaderSignature, Sign(secret, timestamp, body))
+}
+
+// Verify checks the signature headers of a delivery received at now, as receivers are expected to. The signature
+// header may list several comma separated signatures, e.g. while a secret is rotated, one of them has to match
+func Verify(header http.Header, secret []byte, body []byte, now time.Time, tolerance time.Duration) error {
+	signatures := header.Get(HeaderSignature)
+	if signatures == "" || header.Get(HeaderTimestamp) == "" {
+		return ErrMissingSignature
+	}
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return ErrExpiredTimestamp
+	}
+	expected := Sign(secret, timestamp, body)
+	for _, signature := range strings.Split(signatures, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(signature)), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestUnitSign(t *testing.T) {
+	t.Parallel()
+
+	// echo -n '1700000000.{"id":1}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "v1=3dd1b9aef568d75f6790a84bd2e5dfa1f44409eef3cbdbd3f10b837376100c11",
+		Sign([]byte("secret"), 1700000000, []byte(`{"id":1}`)))
+}
+
+func TestUnitVerify(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("secret")
+	body := []byte(`{"id":1}`)
+	sentAt := time.Unix(1700000000, 0)
+	signed := func() http.Header {
+		header := http.Header{}
+		SetHeaders(header, secret, body, sentAt)
+		return header
+	}
+
+	tests := []struct {
+		name     string
+		header   func() http.Header
+		body     []byte
+		now      time.Time
+		expected error
+	}{
+		{name: "valid", header: signed, body: body, now: sentAt.Add(time.Minute)},
+		{name: "tampered body", header: signed, body: []byte(`{"id":2}`), now: sentAt, expected: ErrInvalidSignature},
+		{name: "expired", header: signed, body: body, now: sentAt.Add(DefaultTolerance + time.Second), expected: ErrExpiredTimestamp},
+		{name: "from the future", header: signed, body: body, now: sentAt.Add(-DefaultTolerance - time.Second), expected: ErrExpiredTimestamp},
+		{name: "missing", header: func() http.Header { return http.Header{} }, body: body, now: sentAt, expected: ErrMissingSignature},
+		{name: "other secret", header: func() http.Header {
+			header := http.Header{}
+			SetHeaders(header, []byte("other"), body, sentAt)
+			return header
+		}, body: body, now: sentAt, expected: ErrInvalidSignature},
+		{name: "one of several signatures", header: func() http.Header {
+			header := signed()
+			header.Set(HeaderSignature, Sign([]byte("old"), sentAt.Unix(), body)+", "+header.Get(HeaderSignature))
+			return header
+		}, body: body, now: sentAt},
+		{name: "invalid timestamp", header: func() http.Header {
+			header := signed()
+			header.Set(HeaderTimestamp, "yesterday")
+			return header
+		}, body: body, now: sentAt, expected: ErrInvalidSignature},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			err := Verify(test.header(), secret, test.body, test.now, DefaultTolerance)
+			if test.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ErrForbiddenTarget is returned for webhook URLs and addresses in the networks of the application
+var ErrForbiddenTarget = errors.New("webhook target is not allowed")
+
+// internalNetworks are the ranges netip.Addr has no predicate for
+var internalNetworks = []netip.Prefix{
+	// Shared address space of carrier-grade NAT, RFC 6598
+	netip.MustParsePrefix("100.64.0.0/10"),
+	// "This network", RFC 1122, Linux routes 0.0.0.0 to the local host
+	netip.MustParsePrefix("0.0.0.0/8"),
+}
+
+// Resolver looks up the addresses of a host, net.DefaultResolver implements it
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// TargetGuard keeps the webhooks out of the networks of the application, so that a subscription cannot make the
+// dispatcher call internal services. It rejects loopback, private (RFC 1918, fc00::/7), shared (100.64.0.0/10, used by
+// carrier-grade NAT and cloud internal networks), link-local, e.g. the cloud metadata endpoint 169.254.169.254,
+// "this network" (0.0.0.0/8), unspecified and multicast addresses, and the blocked addresses, e.g. the admin
+// listener. The URLs are checked when subscriptions are written, Control checks the addresses the dispatcher
+// connects to, which catches names resolving to internal addresses later on. The zero value is ready to use
+type TargetGuard struct {
+	allowed  []netip.Prefix
+	blocked  []netip.AddrPort
+	resolver Resolver
+}
+
+// NewTargetGuard creates a guard which allows the allowedNetworks, CIDRs or single addresses, e.g. of a receiver
+// in the cluster network, and rejects the host:port blockedAddresses in any case. A blocked address without a host,
+// e.g. :6060, blocks the port on every address of the interfaces of the host
+func NewTargetGuard(allowedNetworks []string, blockedAddresses []string) (*TargetGuard, error) {
+	guard := &TargetGuard{}
+	for _, network := range allowedNetworks {
+		if strings.Contains(network, "/") {
+			prefix, err := netip.ParsePrefix(network)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowed webhook network %q: %w", network, err)
+			}
+			guard.allowed = append(guard.allowed, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed webhook network %q: %w", network, err)
+		}
+		guard.allowed = append(guard.allowed, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	for _, address := range blockedAddresses {
+		addrs, port, err := hostAddresses(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocked webhook address %q: %w", address, err)
+		}
+		for _, addr := range addrs {
+			guard.blocked = append(guard.blocked, netip.AddrPortFrom(addr.Unmap(), port))
+		}
+	}
+	return guard, nil
+}
+
+// hostAddresses returns the addresses and the port of a host:port address, the addresses of the interfaces for an
+// unspecified host
+func hostAddresses(address string) ([]netip.Addr, uint16, error) {
+	host, portValue, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, 0, err
+	}
+	port, err := strconv.ParseUint(portValue, 10, 16)
+	if err != nil {
+		return nil, 0, err
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !addr.IsUnspecified() {
+		return []netip.Addr{addr}, uint16(port), nil
+	}
+	if host != "" && host != "0.0.0.0" && host != "::" {
+		addrs, err := net.DefaultResolver.LookupNetIP(context.Background(), "ip", host)
+		return addrs, uint16(port), err
+	}
+	interfaceAddrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, 0, err
+	}
+	addrs := make([]netip.Addr, 0, len(interfaceAddrs))
+	for _, interfaceAddr := range interfaceAddrs {
+		if prefix, err := netip.ParsePrefix(interfaceAddr.String()); err == nil {
+			addrs = append(addrs, prefix.Addr())
+		}
+	}
+	return addrs, uint16(port), nil
+}
+
+// CheckURL rejects the URLs whose host is or resolves to a forbidden address. Names which do not resolve are
+// accepted, the dispatcher checks the addresses when it connects
+func (guard *TargetGuard) CheckURL(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	port := uint16(80)
+	if target.Scheme == "https" {
+		port = 443
+	}
+	if target.Port() != "" {
+		value, err := strconv.ParseUint(target.Port(), 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid port %q", target.Port())
+		}
+		port = uint16(value)
+	}
+	host := target.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return guard.Check(netip.AddrPortFrom(addr, port))
+	}
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return fmt.Errorf("%w: %s is a loopback host", ErrForbiddenTarget, host)
+	}
+	resolver := guard.resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if err = guard.Check(netip.AddrPortFrom(addr, port)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check rejects the forbidden addresses, see TargetGuard
+func (guard *TargetGuard) Check(target netip.AddrPort) error {
+	addr := target.Addr().Unmap()
+	for _, blocked := range guard.blocked {
+		if blocked.Addr() == addr && blocked.Port() == target.Port() {
+			return fmt.Errorf("%w: %s is blocked", ErrForbiddenTarget, netip.AddrPortFrom(addr, target.Port()))
+		}
+	}
+	for _, prefix := range guard.allowed {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() ||
+		slices.ContainsFunc(internalNetworks, func(prefix netip.Prefix) bool { return prefix.Contains(addr) }) {
+		return fmt.Errorf("%w: %s is an internal address", ErrForbiddenTarget, addr)
+	}
+	return nil
+}
+
+// Control is a net.Dialer Control function refusing to connect to the forbidden addresses
+func (guard *TargetGuard) Control(_, address string, _ syscall.RawConn) error {
+	target, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenTarget, err)
+	}
+	return guard.Check(target)
+}
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/netip"
+	"testing"
+)
+
+// staticResolver resolves every host to its addresses
+type staticResolver []netip.Addr
+
+func (resolver staticResolver) LookupNetIP(context.Context, string, string) ([]netip.Addr, error) {
+	return resolver, nil
+}
+
+func TestUnitTargetGuardCheckURL(t *testing.T) {
+	t.Parallel()
+
+	guard, err := NewTargetGuard([]string{"10.1.0.0/16"}, []string{"203.0.113.7:6060"})
+	require.NoError(t, err)
+	guard.resolver = staticResolver{netip.MustParseAddr("93.184.215.14")}
+
+	tests := []struct {
+		name      string
+		url       string
+		forbidden bool
+	}{
+		{"Public address", "https://93.184.215.14/hook", false},
+		{"Public name", "https://hooks.example.com/hook", false},
+		{"Loopback", "http://127.0.0.1:8080/hook", true},
+		{"Loopback range", "http://127.8.0.1/hook", true},
+		{"IPv6 loopback", "http://[::1]/hook", true},
+		{"Localhost", "http://localhost:8080/hook", true},
+		{"RFC 1918 10.0.0.0/8", "http://10.0.0.1/hook", true},
+		{"RFC 1918 10.0.0.0/8 end", "http://10.255.255.254/hook", true},
+		{"RFC 1918 172.16.0.0/12", "http://172.16.4.2/hook", true},
+		{"RFC 1918 192.168.0.0/16", "http://192.168.1.1/hook", true},
+		{"Metadata endpoint", "http://169.254.169.254/latest/meta-data/", true},
+		{"IPv4 mapped metadata endpoint", "http://[::ffff:169.254.169.254]/", true},
+		{"Unspecified", "http://0.0.0.0/hook", true},
+		{"This network 0.0.0.0/8", "http://0.1.2.3/hook", true},
+		{"Shared address space 100.64.0.0/10", "http://100.64.0.1/hook", true},
+		{"Shared address space 100.64.0.0/10 end", "http://100.127.255.254/hook", true},
+		{"Public address next to the shared space", "http://100.128.0.1/hook", false},
+		{"Allowed network", "http://10.1.2.3/hook", false},
+		{"Admin listener", "http://203.0.113.7:6060/debug/vars", true},
+		{"Other port of the admin host", "https://203.0.113.7/hook", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := guard.CheckURL(context.Background(), tt.url)
+			if tt.forbidden {
+				assert.ErrorIs(t, err, ErrForbiddenTarget)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUnitTargetGuardResolvedNames(t *testing.T) {
+	t.Parallel()
+
+	guard := &TargetGuard{resolver: staticResolver{netip.MustParseAddr("93.184.215.14"), netip.MustParseAddr("10.0.0.5")}}
+	assert.ErrorIs(t, guard.CheckURL(context.Background(), "https://rebinding.example.com/hook"), ErrForbiddenTarget,
+		"a name is rejected when any of its addresses is internal")
+}
+
+func TestUnitTargetGuardControl(t *testing.T) {
+	t.Parallel()
+
+	guard := &TargetGuard{}
+	assert.ErrorIs(t, guard.Control("tcp4", "127.0.0.1:80", nil), ErrForbiddenTarget)
+	assert.ErrorIs(t, guard.Control("tcp4", "10.0.0.1:443", nil), ErrForbiddenTarget)
+	assert.ErrorIs(t, guard.Control("tcp4", "169.254.169.254:80", nil), ErrForbiddenTarget)
+	assert.NoError(t, guard.Control("tcp4", "93.184.215.14:443", nil))
+}